@@ -13,6 +13,6 @@ func (a *arena) Insert(s schema) int {
 	return len(a.schemas) - 1
 }
 
-func (a *arena) Get(i int) schema {
-	return a.schemas[i]
+func (a *arena) Get(i int) *schema {
+	return &a.schemas[i]
 }
@@ -0,0 +1,282 @@
+package jsonschema
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// BundleConfig contains configuration for Bundle.
+type BundleConfig struct {
+	// URI identifies the schema to bundle. The empty URI identifies the default
+	// schema. If URI has a fragment, only the subschema it points to is
+	// bundled.
+	URI url.URL
+
+	// Definitions is the keyword under which referenced schemas are placed. If
+	// empty, "definitions" is used. Set it to "$defs" for consumers which follow
+	// later drafts of JSON Schema.
+	Definitions string
+}
+
+// Bundle produces a single schema, with no references to other schemas, which
+// accepts the same instances as the schema identified by config.URI.
+//
+// Each subschema of another schema which is referred to, directly or
+// indirectly, is copied into the definitions of the bundled schema, and "$ref"
+// values are rewritten to point at the copies. Copies have their "$id" and
+// "$schema" removed, so that references within them resolve against the
+// bundled schema. The given schemas are not modified.
+//
+// A subschema is bundled as a schema which refers to a copy of it, like any
+// other referred-to schema, in its definitions.
+//
+// The schemas are given as to NewValidator, and the same errors are returned
+// if they are not valid. If the schema to bundle already has a definition with
+// the name chosen for a copy, a number is appended to the name.
+func Bundle(schemas []interface{}, config BundleConfig) (interface{}, error) {
+	if _, err := NewValidator(schemas); err != nil {
+		return nil, err
+	}
+
+	b := bundler{
+		rawSchemas:  map[url.URL]interface{}{},
+		existing:    map[string]interface{}{},
+		definitions: map[string]interface{}{},
+		names:       map[url.URL]string{},
+		keyword:     config.Definitions,
+	}
+
+	if b.keyword == "" {
+		b.keyword = "definitions"
+	}
+
+	for _, schema := range schemas {
+		id := rawSchemaID(schema)
+		id.Fragment = ""
+		b.rawSchemas[id] = schema
+	}
+
+	rootURI := config.URI
+	rootURI.Fragment = ""
+
+	rawRoot, ok := b.rawSchemas[rootURI]
+	if !ok {
+		return nil, ErrNoSuchSchema
+	}
+
+	if config.URI.Fragment != "" {
+		return b.bundleSubschema(config.URI)
+	}
+
+	root := copyRawValue(rawRoot)
+	object, ok := root.(map[string]interface{})
+	if !ok {
+		// a boolean schema cannot refer to anything
+		return root, nil
+	}
+
+	b.rootURI = rootURI
+	if defs, ok := object[b.keyword]; ok {
+		if b.existing, ok = defs.(map[string]interface{}); !ok {
+			return nil, ErrInvalidSchema
+		}
+	}
+
+	b.rewriteRefs(rootURI, root)
+	if b.keyword != "definitions" {
+		// walkRawSchema only knows of "definitions"
+		for _, key := range sortedKeys(b.existing) {
+			b.rewriteRefs(rootURI, b.existing[key])
+		}
+	}
+
+	b.rewritePending()
+	if len(b.definitions) > 0 {
+		for name, schema := range b.definitions {
+			b.existing[name] = schema
+		}
+
+		object[b.keyword] = b.existing
+	}
+
+	return root, nil
+}
+
+type bundler struct {
+	rawSchemas map[url.URL]interface{}
+	rootURI    url.URL
+	keyword    string
+
+	// subschema is whether the bundled schema is only part of its document, in
+	// which case references into the rest of the document are copied like any
+	// other.
+	subschema bool
+
+	// existing holds the definitions the bundled schema already had, and
+	// definitions holds the copies added to them.
+	existing    map[string]interface{}
+	definitions map[string]interface{}
+
+	// names holds the name of the definition each referred-to URI was copied to.
+	names map[url.URL]string
+
+	// pending holds copies whose references have yet to be rewritten.
+	pending []bundledSchema
+}
+
+type bundledSchema struct {
+	baseURI url.URL
+	schema  interface{}
+}
+
+// bundleSubschema bundles the subschema identified by uri, whose fragment is
+// not empty.
+func (b *bundler) bundleSubschema(uri url.URL) (interface{}, error) {
+	b.subschema = true
+
+	name, ok := b.define(uri)
+	if !ok {
+		return nil, ErrNoSuchSchema
+	}
+
+	b.rewritePending()
+	return map[string]interface{}{
+		"$ref":    "#" + jsonpointer.Ptr{Tokens: []string{b.keyword, name}}.String(),
+		b.keyword: b.definitions,
+	}, nil
+}
+
+// rewritePending rewrites the references of copies until none are pending.
+func (b *bundler) rewritePending() {
+	for len(b.pending) > 0 {
+		next := b.pending[0]
+		b.pending = b.pending[1:]
+
+		b.rewriteRefs(next.baseURI, next.schema)
+	}
+}
+
+// rewriteRefs rewrites every "$ref" within schema, which belongs to the document
+// identified by baseURI, so that it points within the bundled schema.
+func (b *bundler) rewriteRefs(baseURI url.URL, schema interface{}) {
+	walkRawSchema(schema, []string{}, func(tokens []string, object map[string]interface{}) {
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return
+		}
+
+		uri, err := baseURI.Parse(ref)
+		if err != nil {
+			// only refs in parts of a schema which are never evaluated can fail
+			// to parse; they are left as they are
+			return
+		}
+
+		refBaseURI := *uri
+		refBaseURI.Fragment = ""
+
+		if !b.subschema && refBaseURI == b.rootURI {
+			object["$ref"] = "#" + uri.Fragment
+			return
+		}
+
+		if name, ok := b.define(*uri); ok {
+			object["$ref"] = "#" + jsonpointer.Ptr{Tokens: []string{b.keyword, name}}.String()
+		}
+	})
+}
+
+// define copies the schema identified by uri into the definitions of the
+// bundled schema, if it has not been already, and returns the name of its
+// definition. If the schema does not exist, ok is false.
+func (b *bundler) define(uri url.URL) (name string, ok bool) {
+	if name, ok := b.names[uri]; ok {
+		return name, true
+	}
+
+	baseURI := uri
+	baseURI.Fragment = ""
+
+	document, ok := b.rawSchemas[baseURI]
+	if !ok {
+		return "", false
+	}
+
+	ptr, err := jsonpointer.New(uri.Fragment)
+	if err != nil {
+		return "", false
+	}
+
+	rawSchema, err := ptr.Eval(document)
+	if err != nil {
+		return "", false
+	}
+
+	schema := copyRawValue(*rawSchema)
+	walkRawSchema(schema, []string{}, func(tokens []string, object map[string]interface{}) {
+		delete(object, "$id")
+		delete(object, "$schema")
+	})
+
+	name = b.definitionName(uri, ptr)
+	b.names[uri] = name
+	b.definitions[name] = schema
+	b.pending = append(b.pending, bundledSchema{baseURI: baseURI, schema: schema})
+
+	return name, true
+}
+
+// definitionName chooses an unused name for the definition of the schema
+// identified by uri. Names are taken from the last token of the fragment, or
+// else from the last segment of the path.
+func (b *bundler) definitionName(uri url.URL, ptr jsonpointer.Ptr) string {
+	base := ""
+	if len(ptr.Tokens) > 0 {
+		base = ptr.Tokens[len(ptr.Tokens)-1]
+	} else {
+		base = path.Base(uri.Path)
+		base = strings.TrimSuffix(base, path.Ext(base))
+	}
+
+	if base == "" || base == "." || base == "/" {
+		base = "schema"
+	}
+
+	name := base
+	for i := 2; ; i++ {
+		_, defined := b.definitions[name]
+		_, existing := b.existing[name]
+		if !defined && !existing {
+			return name
+		}
+
+		name = base + "-" + strconv.Itoa(i)
+	}
+}
+
+// copyRawValue makes a deep copy of a value of the sort produced by
+// encoding/json.
+func copyRawValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, elem := range value {
+			out[key] = copyRawValue(elem)
+		}
+
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, elem := range value {
+			out[i] = copyRawValue(elem)
+		}
+
+		return out
+	default:
+		return value
+	}
+}
@@ -0,0 +1,180 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustDecode(t *testing.T, s string) interface{} {
+	var value interface{}
+	assert.NoError(t, json.Unmarshal([]byte(s), &value))
+	return value
+}
+
+func TestBundle(t *testing.T) {
+	schemas := []interface{}{
+		mustDecode(t, `{
+			"$id": "http://example.com/common.json",
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"definitions": {
+				"name": {"type": "string", "minLength": 1},
+				"person": {
+					"properties": {
+						"name": {"$ref": "#/definitions/name"},
+						"friends": {"items": {"$ref": "#/definitions/person"}},
+						"address": {"$ref": "address.json"}
+					}
+				}
+			}
+		}`),
+		mustDecode(t, `{
+			"$id": "http://example.com/address.json",
+			"properties": {"city": {"$ref": "common.json#/definitions/name"}},
+			"required": ["city"]
+		}`),
+		mustDecode(t, `{
+			"$id": "http://example.com/root.json",
+			"definitions": {"name": {"const": "root"}},
+			"properties": {
+				"owner": {"$ref": "common.json#/definitions/person"},
+				"label": {"$ref": "#/definitions/name"},
+				"self": {"$ref": "http://example.com/root.json"}
+			}
+		}`),
+	}
+
+	bundled, err := Bundle(schemas, BundleConfig{URI: url.URL{Scheme: "http", Host: "example.com", Path: "/root.json"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, mustDecode(t, `{
+		"$id": "http://example.com/root.json",
+		"definitions": {
+			"name": {"const": "root"},
+			"person": {
+				"properties": {
+					"name": {"$ref": "#/definitions/name-2"},
+					"friends": {"items": {"$ref": "#/definitions/person"}},
+					"address": {"$ref": "#/definitions/address"}
+				}
+			},
+			"name-2": {"type": "string", "minLength": 1},
+			"address": {
+				"properties": {"city": {"$ref": "#/definitions/name-2"}},
+				"required": ["city"]
+			}
+		},
+		"properties": {
+			"owner": {"$ref": "#/definitions/person"},
+			"label": {"$ref": "#/definitions/name"},
+			"self": {"$ref": "#"}
+		}
+	}`), bundled)
+
+	// the given schemas are left as they were
+	assert.Equal(t, "common.json#/definitions/person", schemas[2].(map[string]interface{})["properties"].(map[string]interface{})["owner"].(map[string]interface{})["$ref"])
+
+	// the bundled schema stands alone, and accepts the same instances
+	before, err := NewValidator(schemas)
+	assert.NoError(t, err)
+
+	after, err := NewValidator([]interface{}{bundled})
+	assert.NoError(t, err)
+
+	instances := []string{
+		`{}`,
+		`{"label": "root"}`,
+		`{"label": "x"}`,
+		`{"owner": {"name": "a", "friends": [{"name": "b", "address": {"city": "c"}}]}}`,
+		`{"owner": {"name": "a", "friends": [{"name": ""}]}}`,
+		`{"owner": {"address": {}}}`,
+		`{"self": {"self": {"label": 1}}}`,
+	}
+
+	for _, instance := range instances {
+		expected, err := before.IsValidURI(url.URL{Scheme: "http", Host: "example.com", Path: "/root.json"}, mustDecode(t, instance))
+		assert.NoError(t, err)
+
+		actual, err := after.IsValidURI(url.URL{Scheme: "http", Host: "example.com", Path: "/root.json"}, mustDecode(t, instance))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, instance)
+	}
+}
+
+func TestBundleDefs(t *testing.T) {
+	schemas := []interface{}{
+		mustDecode(t, `{"$id": "http://example.com/a", "type": "integer"}`),
+		mustDecode(t, `{"items": {"$ref": "http://example.com/a"}, "$defs": {"b": {"$ref": "http://example.com/a"}}}`),
+	}
+
+	bundled, err := Bundle(schemas, BundleConfig{Definitions: "$defs"})
+	assert.NoError(t, err)
+	assert.Equal(t, mustDecode(t, `{
+		"items": {"$ref": "#/$defs/a"},
+		"$defs": {
+			"a": {"type": "integer"},
+			"b": {"$ref": "#/$defs/a"}
+		}
+	}`), bundled)
+}
+
+func TestBundleSubschema(t *testing.T) {
+	schemas := []interface{}{
+		mustDecode(t, `{"$id": "http://example.com/a", "type": "integer"}`),
+		mustDecode(t, `{
+			"$id": "http://example.com/root",
+			"type": "object",
+			"definitions": {
+				"list": {"type": "array", "items": {"$ref": "#/definitions/item"}},
+				"item": {"anyOf": [{"$ref": "a"}, {"$ref": "#/definitions/list"}]}
+			}
+		}`),
+	}
+
+	uri := url.URL{Scheme: "http", Host: "example.com", Path: "/root", Fragment: "/definitions/list"}
+	bundled, err := Bundle(schemas, BundleConfig{URI: uri})
+	assert.NoError(t, err)
+	assert.Equal(t, mustDecode(t, `{
+		"$ref": "#/definitions/list",
+		"definitions": {
+			"list": {"type": "array", "items": {"$ref": "#/definitions/item"}},
+			"item": {"anyOf": [{"$ref": "#/definitions/a"}, {"$ref": "#/definitions/list"}]},
+			"a": {"type": "integer"}
+		}
+	}`), bundled)
+
+	// the bundled schema accepts the same instances as a reference to the
+	// subschema
+	before, err := NewValidator(append(schemas, mustDecode(t, `{"$ref": "http://example.com/root#/definitions/list"}`)))
+	assert.NoError(t, err)
+
+	after, err := NewValidator([]interface{}{bundled})
+	assert.NoError(t, err)
+
+	for _, instance := range []string{`[1, [2, []]]`, `[1, "x"]`, `{}`} {
+		expected, err := before.IsValid(mustDecode(t, instance))
+		assert.NoError(t, err)
+
+		actual, err := after.IsValid(mustDecode(t, instance))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, instance)
+	}
+
+	uri.Fragment = "/definitions/missing"
+	_, err = Bundle(schemas, BundleConfig{URI: uri})
+	assert.Equal(t, ErrNoSuchSchema, err)
+}
+
+func TestBundleErrors(t *testing.T) {
+	_, err := Bundle([]interface{}{mustDecode(t, `{"$ref": "http://example.com/missing"}`)}, BundleConfig{})
+	assert.Equal(t, ErrMissingURIs{URIs: []url.URL{{Scheme: "http", Host: "example.com", Path: "/missing"}}}, err)
+
+	_, err = Bundle([]interface{}{mustDecode(t, `{"$id": "http://example.com/a"}`)}, BundleConfig{})
+	assert.Equal(t, ErrNoSuchSchema, err)
+
+	bundled, err := Bundle([]interface{}{true}, BundleConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, true, bundled)
+}
@@ -0,0 +1,172 @@
+// Command jsonschema-gen generates Go functions which validate instances
+// against JSON schemas, using jsonschema.GenerateGo. It is meant to be invoked
+// from a go:generate directive, such as:
+//
+//	//go:generate jsonschema-gen -package person -func ValidatePerson -o person_gen.go person.json
+//
+// Every schema file given is loaded into a single Validator. Each -func flag
+// names a function to generate, optionally followed by "=" and the URI of the
+// schema it validates against; without a URI, the default schema is used. The
+// -func flag may be repeated. A function name followed by ":" and the name of a
+// Go type, such as one generated with -type, makes the function take values of
+// that type instead of interface{}:
+//
+//	//go:generate jsonschema-gen -package person -func ValidatePerson:Person -o person_gen.go person.json
+//
+// With -type flags instead of -func flags, jsonschema-gen generates Go types
+// which instances of the schemas decode into, using jsonschema.GenerateGoTypes:
+//
+//	//go:generate jsonschema-gen -package person -type Person -o person_types.go person.json
+//
+// Each -type flag is a type name, a URI, or a name followed by "=" and a URI.
+// Types named only by URI take their name from the schema.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/json-schema-spec/json-schema-go"
+)
+
+type funcFlags []string
+
+func (f *funcFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *funcFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func main() {
+	var funcs, types funcFlags
+
+	pkg := flag.String("package", "", "name of the package of the generated file")
+	out := flag.String("o", "", "file to write to; defaults to standard output")
+	flag.Var(&funcs, "func", "function to generate, as NAME[:TYPE] or NAME[:TYPE]=URI")
+	flag.Var(&types, "type", "type to generate, as NAME, URI or NAME=URI")
+	flag.Parse()
+
+	if err := run(*pkg, *out, funcs, types, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "jsonschema-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, out string, funcs, types []string, paths []string) error {
+	if pkg == "" {
+		return fmt.Errorf("-package is required")
+	}
+
+	if len(funcs) == 0 && len(types) == 0 {
+		return fmt.Errorf("at least one -func or -type is required")
+	}
+
+	if len(funcs) > 0 && len(types) > 0 {
+		return fmt.Errorf("-func and -type cannot be used together; run jsonschema-gen once for each")
+	}
+
+	schemas := []interface{}{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var schema interface{}
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+
+		schemas = append(schemas, schema)
+	}
+
+	validator, err := jsonschema.NewValidator(schemas)
+	if err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	if len(types) > 0 {
+		if err := generateTypes(&src, pkg, &validator, types); err != nil {
+			return err
+		}
+
+		return write(out, src.Bytes())
+	}
+
+	config := jsonschema.GenerateConfig{Package: pkg}
+	for _, fn := range funcs {
+		parts := strings.SplitN(fn, "=", 2)
+
+		uri := url.URL{}
+		if len(parts) == 2 {
+			parsed, err := url.Parse(parts[1])
+			if err != nil {
+				return err
+			}
+
+			uri = *parsed
+		}
+
+		name, typ := parts[0], ""
+		if i := strings.Index(name, ":"); i >= 0 {
+			name, typ = name[:i], name[i+1:]
+		}
+
+		config.Funcs = append(config.Funcs, jsonschema.GenerateFunc{
+			Name:      name,
+			Validator: &validator,
+			URI:       uri,
+			Type:      typ,
+		})
+	}
+
+	if err := jsonschema.GenerateGo(&src, config); err != nil {
+		return err
+	}
+
+	return write(out, src.Bytes())
+}
+
+func generateTypes(w io.Writer, pkg string, validator *jsonschema.Validator, types []string) error {
+	config := jsonschema.GenerateTypesConfig{Package: pkg, Validator: validator}
+	for _, typ := range types {
+		name, uri := typ, ""
+		if parts := strings.SplitN(typ, "=", 2); len(parts) == 2 {
+			name, uri = parts[0], parts[1]
+		} else if strings.ContainsAny(typ, ":/#.") {
+			name, uri = "", typ
+		}
+
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			return err
+		}
+
+		config.Types = append(config.Types, jsonschema.GenerateType{
+			Name: name,
+			URI:  *parsed,
+		})
+	}
+
+	return jsonschema.GenerateGoTypes(w, config)
+}
+
+func write(out string, src []byte) error {
+	if out == "" {
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+
+	return ioutil.WriteFile(out, src, 0644)
+}
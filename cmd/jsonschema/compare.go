@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/json-schema-spec/json-schema-go"
+)
+
+func runCompare(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jsonschema compare [--old-ref ref.json ...] [--new-ref ref.json ...] [flags] old.json new.json\n\n")
+		fmt.Fprintf(stderr, "Reports the differences between two versions of a schema, and whether each\n")
+		fmt.Fprintf(stderr, "is breaking: whether the new version rejects instances the old accepted.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	var oldRefs, newRefs stringsFlag
+	flags.Var(&oldRefs, "old-ref", "schema referred to by the old version; may be repeated")
+	flags.Var(&newRefs, "new-ref", "schema referred to by the new version; may be repeated")
+	format := flags.String("format", "text", "output format: text or json")
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+
+		return exitError
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "jsonschema compare: unknown format %q\n", *format)
+		return exitError
+	}
+
+	if flags.NArg() != 2 {
+		fmt.Fprintf(stderr, "jsonschema compare: expected two schemas, got %d\n", flags.NArg())
+		flags.Usage()
+		return exitError
+	}
+
+	config := jsonschema.ValidatorConfig{MaxStackDepth: jsonschema.DefaultMaxStackDepth}
+
+	old, oldURI, err := loadValidator(flags.Arg(0), oldRefs, config)
+	if err != nil {
+		printSchemaError(stderr, "compare", err)
+		return exitError
+	}
+
+	new, newURI, err := loadValidator(flags.Arg(1), newRefs, config)
+	if err != nil {
+		printSchemaError(stderr, "compare", err)
+		return exitError
+	}
+
+	changes, err := jsonschema.CompareURI(&old, oldURI, &new, newURI)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonschema compare: %v\n", err)
+		return exitError
+	}
+
+	if *format == "json" {
+		err = writeCompareJSON(stdout, &old, &new, changes)
+	} else {
+		err = writeCompareText(stdout, &old, &new, changes)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonschema compare: %v\n", err)
+		return exitError
+	}
+
+	for _, change := range changes {
+		if change.Breaking {
+			return exitInvalid
+		}
+	}
+
+	return exitOK
+}
+
+func writeCompareText(w io.Writer, old, new *jsonschema.Validator, changes []jsonschema.SchemaChange) error {
+	for _, change := range changes {
+		// changes are described where they are in the new version, unless the
+		// new version lacks the changed keyword
+		line := change.String()
+		if pos, ok := new.SchemaPosition(change.New.URI, change.New.Path); ok {
+			line = pos.String() + ": " + line
+		} else if pos, ok := old.SchemaPosition(change.Old.URI, change.Old.Path); ok {
+			line = pos.String() + ": " + line
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type jsonChange struct {
+	Breaking bool             `json:"breaking"`
+	Message  string           `json:"message"`
+	Old      jsonKeywordPlace `json:"old"`
+	New      jsonKeywordPlace `json:"new"`
+}
+
+type jsonKeywordPlace struct {
+	URI      string        `json:"uri"`
+	Path     string        `json:"path"`
+	Position *jsonPosition `json:"position,omitempty"`
+}
+
+func newJSONKeywordPlace(v *jsonschema.Validator, location jsonschema.KeywordLocation) jsonKeywordPlace {
+	uri := location.URI
+	uri.Fragment = ""
+
+	return jsonKeywordPlace{
+		URI:      uri.String(),
+		Path:     location.Path.String(),
+		Position: newJSONPosition(v.SchemaPosition(location.URI, location.Path)),
+	}
+}
+
+func writeCompareJSON(w io.Writer, old, new *jsonschema.Validator, changes []jsonschema.SchemaChange) error {
+	out := []jsonChange{}
+	for _, change := range changes {
+		out = append(out, jsonChange{
+			Breaking: change.Breaking,
+			Message:  change.Message,
+			Old:      newJSONKeywordPlace(old, change.Old),
+			New:      newJSONKeywordPlace(new, change.New),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/json-schema-spec/json-schema-go"
+)
+
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jsonschema lint [flags] schema.json ...\n\n")
+		fmt.Fprintf(stderr, "Checks schemas for likely mistakes. Schemas may be glob patterns, and may\n")
+		fmt.Fprintf(stderr, "refer to one another.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	format := flags.String("format", "text", "output format: text or json")
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+
+		return exitError
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "jsonschema lint: unknown format %q\n", *format)
+		return exitError
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintf(stderr, "jsonschema lint: no schemas given\n")
+		flags.Usage()
+		return exitError
+	}
+
+	paths, err := expandPaths(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonschema lint: %v\n", err)
+		return exitError
+	}
+
+	sources, err := readSources(paths)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonschema lint: %v\n", err)
+		return exitError
+	}
+
+	validator, err := jsonschema.NewValidatorFromJSON(sources, jsonschema.ValidatorConfig{
+		MaxStackDepth: jsonschema.DefaultMaxStackDepth,
+	})
+	if err != nil {
+		printSchemaError(stderr, "lint", err)
+		return exitError
+	}
+
+	warnings := validator.Lint()
+	if *format == "json" {
+		err = writeLintJSON(stdout, warnings)
+	} else {
+		for _, warning := range warnings {
+			if _, err = fmt.Fprintln(stdout, warning.String()); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonschema lint: %v\n", err)
+		return exitError
+	}
+
+	if len(warnings) > 0 {
+		return exitInvalid
+	}
+
+	return exitOK
+}
+
+type jsonWarning struct {
+	URI      string        `json:"uri"`
+	Path     string        `json:"path"`
+	Position *jsonPosition `json:"position,omitempty"`
+	Check    string        `json:"check"`
+	Message  string        `json:"message"`
+}
+
+func writeLintJSON(w io.Writer, warnings []jsonschema.LintWarning) error {
+	out := []jsonWarning{}
+	for _, warning := range warnings {
+		uri := warning.URI
+		uri.Fragment = ""
+
+		out = append(out, jsonWarning{
+			URI:      uri.String(),
+			Path:     warning.Path.String(),
+			Position: newJSONPosition(warning.Position, true),
+			Check:    warning.Check,
+			Message:  warning.Message,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
@@ -0,0 +1,71 @@
+// Command jsonschema validates documents against JSON schemas, and checks
+// schemas for mistakes. It is meant for use in scripts and CI pipelines:
+//
+//	jsonschema validate -s schema.json [-r ref.json ...] [flags] [file ...]
+//	jsonschema lint [flags] schema.json ...
+//	jsonschema compare [flags] old.json new.json
+//
+// Run "jsonschema help" for a list of commands, and "jsonschema COMMAND -h"
+// for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes shared by every command.
+const (
+	exitOK      = 0 // every check passed
+	exitInvalid = 1 // some document was invalid, schema had warnings, or change was breaking
+	exitError   = 2 // the command could not be carried out
+)
+
+// command is a subcommand of jsonschema.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"validate", "validate documents against a schema", runValidate},
+	{"lint", "check schemas for likely mistakes", runLint},
+	{"compare", "find breaking changes between versions of a schema", runCompare},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "jsonschema: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: jsonschema COMMAND [flags] [args]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+
+	fmt.Fprintf(w, "\nExit codes: %d if every check passes, %d if any fails, %d on other errors.\n", exitOK, exitInvalid, exitError)
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testdir creates a directory holding the given files, and returns its path.
+func testdir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "jsonschema")
+	assert.NoError(t, err)
+
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	return dir
+}
+
+var validateFiles = map[string]string{
+	"schema.json": `{
+  "$id": "http://example.com/config",
+  "properties": {
+    "port": {"$ref": "http://example.com/port"}
+  }
+}`,
+	"port.json":    `{"$id": "http://example.com/port", "type": "integer"}`,
+	"good.json":    `{"port": 80}`,
+	"bad.json":     "{\n  \"port\": \"x\"\n}",
+	"lines.ndjson": "{\"port\": 1}\n\n{\"port\": true}\n",
+	"bad.yaml":     "port: x\n",
+	"broken.json":  `{"port":`,
+}
+
+func runTest(args []string, stdin string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestValidateText(t *testing.T) {
+	dir := testdir(t, validateFiles)
+	defer os.RemoveAll(dir)
+
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	code, stdout, _ := runTest([]string{"validate", "-s", path("schema.json"), "-r", path("port.json"), path("good.json")}, "")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "", stdout)
+
+	code, stdout, _ = runTest([]string{"validate", "-s", path("schema.json"), "-r", path("port.json"), path("*.json"), path("lines.ndjson"), path("bad.yaml")}, "")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, strings.Join([]string{
+		path("bad.json") + ":2:11: /port: rejected by http://example.com/port#/type (" + path("port.json") + ":1:44)",
+		path("broken.json") + ":1:9: unexpected end of input",
+		path("lines.ndjson") + ":3:10: /port: rejected by http://example.com/port#/type (" + path("port.json") + ":1:44)",
+		path("bad.yaml") + ":1:7: /port: rejected by http://example.com/port#/type (" + path("port.json") + ":1:44)",
+		"4 of 8 documents invalid",
+		"",
+	}, "\n"), stdout)
+
+	code, stdout, _ = runTest([]string{"validate", "-s", path("schema.json"), "-r", path("port.json")}, `{"port": 1.5}`)
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, "<stdin>:1:10: /port: rejected by http://example.com/port#/type ("+path("port.json")+":1:44)\n1 of 1 documents invalid\n", stdout)
+
+	code, stdout, _ = runTest([]string{"validate", "-s", path("schema.json"), "-r", path("port.json"), "--input-format", "yaml", "-"}, "port: 1\n")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "", stdout)
+}
+
+func TestValidateMaxErrors(t *testing.T) {
+	dir := testdir(t, map[string]string{
+		"schema.json": `{"items": {"type": "string"}}`,
+		"data.json":   `[1, 2, 3]`,
+		"one.json":    `[1]`,
+	})
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runTest([]string{"validate", "-s", filepath.Join(dir, "schema.json"), "--max-errors", "1", filepath.Join(dir, "data.json")}, "")
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, 3, strings.Count(stdout, "\n"))
+	assert.Contains(t, stdout, "further errors not shown")
+
+	code, stdout, _ = runTest([]string{"validate", "-s", filepath.Join(dir, "schema.json"), "--max-errors", "1", filepath.Join(dir, "one.json")}, "")
+	assert.Equal(t, exitInvalid, code)
+	assert.NotContains(t, stdout, "further errors not shown")
+}
+
+func TestValidateJSONFormat(t *testing.T) {
+	dir := testdir(t, validateFiles)
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runTest([]string{
+		"validate", "-s", filepath.Join(dir, "schema.json"), "-r", filepath.Join(dir, "port.json"), "--format", "json",
+		filepath.Join(dir, "good.json"), filepath.Join(dir, "bad.json"),
+	}, "")
+	assert.Equal(t, exitInvalid, code)
+
+	var out jsonReport
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.False(t, out.Valid)
+	assert.Equal(t, 2, len(out.Documents))
+	assert.True(t, out.Documents[0].Valid)
+	assert.Equal(t, []jsonError{{
+		InstancePath:     "/port",
+		InstancePosition: &jsonPosition{File: filepath.Join(dir, "bad.json"), Line: 2, Column: 11, Offset: 12},
+		SchemaPath:       "/type",
+		SchemaPosition:   &jsonPosition{File: filepath.Join(dir, "port.json"), Line: 1, Column: 44, Offset: 43},
+		URI:              "http://example.com/port",
+	}}, out.Documents[1].Errors)
+}
+
+func TestValidateJUnitFormat(t *testing.T) {
+	dir := testdir(t, validateFiles)
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runTest([]string{
+		"validate", "-s", filepath.Join(dir, "schema.json"), "-r", filepath.Join(dir, "port.json"), "--format", "junit",
+		filepath.Join(dir, "good.json"), filepath.Join(dir, "bad.json"), filepath.Join(dir, "broken.json"),
+	}, "")
+	assert.Equal(t, exitError, code)
+
+	var out junitTestSuites
+	assert.NoError(t, xml.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, 1, len(out.Suites))
+
+	suite := out.Suites[0]
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Errors)
+	assert.Nil(t, suite.Cases[0].Failure)
+	assert.Equal(t, "1 validation errors", suite.Cases[1].Failure.Message)
+	assert.Contains(t, suite.Cases[2].Error.Text, "unexpected end of input")
+}
+
+func TestValidateCoverage(t *testing.T) {
+	dir := testdir(t, map[string]string{
+		"schema.json": `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+		"data.json":   `"x"`,
+	})
+	defer os.RemoveAll(dir)
+
+	code, _, _ := runTest([]string{"validate", "-s", filepath.Join(dir, "schema.json"), "--coverage", filepath.Join(dir, "coverage.json"), filepath.Join(dir, "data.json")}, "")
+	assert.Equal(t, exitOK, code)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "coverage.json"))
+	assert.NoError(t, err)
+
+	var entries []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &entries))
+
+	exercised := map[string]bool{}
+	for _, entry := range entries {
+		if entry["branch"] == true {
+			exercised[entry["path"].(string)] = entry["exercised"].(bool)
+		}
+	}
+
+	assert.Equal(t, map[string]bool{"/oneOf/0": true, "/oneOf/1": false}, exercised)
+
+	code, _, _ = runTest([]string{"validate", "-s", filepath.Join(dir, "schema.json"), "--coverage", filepath.Join(dir, "coverage.html"), filepath.Join(dir, "data.json")}, "")
+	assert.Equal(t, exitOK, code)
+
+	data, err = ioutil.ReadFile(filepath.Join(dir, "coverage.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `<tr class="unexercised"><td>#/oneOf/1</td>`)
+}
+
+func TestValidateUsageErrors(t *testing.T) {
+	dir := testdir(t, map[string]string{
+		"schema.json":  `{}`,
+		"invalid.json": `{"type": 1}`,
+	})
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		args   []string
+		stderr string
+	}{
+		{[]string{}, "Usage: jsonschema COMMAND"},
+		{[]string{"nope"}, `unknown command "nope"`},
+		{[]string{"validate"}, "-s is required"},
+		{[]string{"validate", "-s", filepath.Join(dir, "schema.json"), "--format", "xml"}, `unknown format "xml"`},
+		{[]string{"validate", "-s", filepath.Join(dir, "schema.json"), "--input-format", "xml"}, `unknown input format "xml"`},
+		{[]string{"validate", "-s", filepath.Join(dir, "missing.json")}, "no such file"},
+		{[]string{"validate", "-s", filepath.Join(dir, "invalid.json")}, "invalid schema"},
+		{[]string{"validate", "-s", filepath.Join(dir, "schema.json"), filepath.Join(dir, "*.yaml")}, "no files match"},
+	}
+
+	for _, tt := range testCases {
+		code, _, stderr := runTest(tt.args, "")
+		assert.Equal(t, exitError, code, "%v", tt.args)
+		assert.Contains(t, stderr, tt.stderr, "%v", tt.args)
+	}
+}
+
+func TestLint(t *testing.T) {
+	dir := testdir(t, map[string]string{
+		"a.json": `{"$id": "http://example.com/a", "properties": {"b": {"$ref": "http://example.com/b"}}}`,
+		"b.json": `{"$id": "http://example.com/b", "type": "string", "minimum": 1}`,
+	})
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runTest([]string{"lint", filepath.Join(dir, "a.json")}, "")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "", stdout)
+
+	code, stdout, _ = runTest([]string{"lint", filepath.Join(dir, "*.json")}, "")
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, filepath.Join(dir, "b.json")+`:1:62: http://example.com/b#/minimum: "minimum" has no effect, as "type" does not allow numbers (type-mismatch)`+"\n", stdout)
+
+	code, stdout, _ = runTest([]string{"lint", "--format", "json", filepath.Join(dir, "*.json")}, "")
+	assert.Equal(t, exitInvalid, code)
+
+	var out []jsonWarning
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, []jsonWarning{{
+		URI:      "http://example.com/b",
+		Path:     "/minimum",
+		Position: &jsonPosition{File: filepath.Join(dir, "b.json"), Line: 1, Column: 62, Offset: 61},
+		Check:    "type-mismatch",
+		Message:  `"minimum" has no effect, as "type" does not allow numbers`,
+	}}, out)
+
+	code, _, stderr := runTest([]string{"lint"}, "")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "no schemas given")
+}
+
+func TestCompare(t *testing.T) {
+	dir := testdir(t, map[string]string{
+		"v1.json":      `{"properties": {"a": {"type": "string"}}}`,
+		"v2.json":      `{"properties": {"a": {"type": ["string", "null"]}}}`,
+		"v3.json":      `{"properties": {"a": {"$ref": "http://example.com/a"}}}`,
+		"v3-refs.json": `{"$id": "http://example.com/a", "type": "string", "maxLength": 3}`,
+	})
+	defer os.RemoveAll(dir)
+
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	code, stdout, _ := runTest([]string{"compare", path("v1.json"), path("v2.json")}, "")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, path("v2.json")+`:1:31: non-breaking: #/properties/a/type: "type" now allows null`+"\n", stdout)
+
+	code, stdout, _ = runTest([]string{"compare", "--new-ref", path("v3-refs.json"), path("v2.json"), path("v3.json")}, "")
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, strings.Join([]string{
+		path("v3-refs.json") + `:1:41: breaking: http://example.com/a#/type: "type" no longer allows null`,
+		path("v3-refs.json") + `:1:64: breaking: http://example.com/a#/maxLength: "maxLength" of 3 added`,
+		"",
+	}, "\n"), stdout)
+
+	code, stdout, _ = runTest([]string{"compare", "--format", "json", path("v2.json"), path("v1.json")}, "")
+	assert.Equal(t, exitInvalid, code)
+
+	var out []jsonChange
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, []jsonChange{{
+		Breaking: true,
+		Message:  `"type" no longer allows null`,
+		Old: jsonKeywordPlace{
+			Path:     "/properties/a/type",
+			Position: &jsonPosition{File: path("v2.json"), Line: 1, Column: 31, Offset: 30},
+		},
+		New: jsonKeywordPlace{
+			Path:     "/properties/a/type",
+			Position: &jsonPosition{File: path("v1.json"), Line: 1, Column: 31, Offset: 30},
+		},
+	}}, out)
+
+	code, _, stderr := runTest([]string{"compare", path("v1.json")}, "")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "expected two schemas, got 1")
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/json-schema-spec/json-schema-go"
+)
+
+// report is the outcome of a run of the validate command.
+type report struct {
+	validator *jsonschema.Validator
+	schema    string
+	docs      []document
+}
+
+// formats holds how to write a report in each output format.
+var formats = map[string]func(w io.Writer, r report) error{
+	"text":  writeText,
+	"json":  writeJSON,
+	"junit": writeJUnit,
+}
+
+// errorLines describes each error in a document, one per line, in the form
+// "file:line:column: /instance/path: rejected by uri#/schema/path (schema.json:line:column)".
+func (r report) errorLines(doc document) []string {
+	if doc.err != nil {
+		if _, ok := doc.err.(jsonschema.ErrSyntax); ok {
+			return []string{doc.err.Error()}
+		}
+
+		if _, ok := doc.err.(jsonschema.ErrUnsupportedValue); ok {
+			return []string{doc.err.Error()}
+		}
+
+		return []string{fmt.Sprintf("%s: %v", doc.name, doc.err)}
+	}
+
+	lines := []string{}
+	for _, e := range doc.result.Errors {
+		where := doc.name
+		if e.InstancePosition.IsValid() {
+			where = e.InstancePosition.String()
+		}
+
+		line := fmt.Sprintf("%s: %s: rejected by %s", where, instancePath(e), schemaLocation(e))
+		if pos, ok := r.validator.SchemaPosition(e.URI, e.SchemaPath); ok {
+			line += fmt.Sprintf(" (%s)", pos.String())
+		}
+
+		lines = append(lines, line)
+	}
+
+	if doc.result.Overflowed {
+		lines = append(lines, fmt.Sprintf("%s: further errors not shown", doc.name))
+	}
+
+	return lines
+}
+
+func instancePath(e jsonschema.ValidationError) string {
+	if len(e.InstancePath.Tokens) == 0 {
+		return "(root)"
+	}
+
+	return e.InstancePath.String()
+}
+
+func schemaLocation(e jsonschema.ValidationError) string {
+	uri := e.URI
+	uri.Fragment = e.SchemaPath.String()
+	return uri.String()
+}
+
+func writeText(w io.Writer, r report) error {
+	invalid := 0
+	for _, doc := range r.docs {
+		if doc.err != nil || !doc.result.IsValid() {
+			invalid++
+		}
+
+		for _, line := range r.errorLines(doc) {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	if invalid > 0 {
+		_, err := fmt.Fprintf(w, "%d of %d documents invalid\n", invalid, len(r.docs))
+		return err
+	}
+
+	return nil
+}
+
+type jsonReport struct {
+	Valid     bool           `json:"valid"`
+	Documents []jsonDocument `json:"documents"`
+}
+
+type jsonDocument struct {
+	Name       string      `json:"name"`
+	Valid      bool        `json:"valid"`
+	Error      string      `json:"error,omitempty"`
+	Errors     []jsonError `json:"errors,omitempty"`
+	Overflowed bool        `json:"overflowed,omitempty"`
+}
+
+type jsonError struct {
+	InstancePath     string        `json:"instancePath"`
+	InstancePosition *jsonPosition `json:"instancePosition,omitempty"`
+	SchemaPath       string        `json:"schemaPath"`
+	SchemaPosition   *jsonPosition `json:"schemaPosition,omitempty"`
+	URI              string        `json:"uri"`
+	Causes           []jsonError   `json:"causes,omitempty"`
+	Matches          []int         `json:"matches,omitempty"`
+}
+
+type jsonPosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Offset int    `json:"offset"`
+}
+
+func newJSONPosition(pos jsonschema.Position, ok bool) *jsonPosition {
+	if !ok || !pos.IsValid() {
+		return nil
+	}
+
+	return &jsonPosition{File: pos.Filename, Line: pos.Line, Column: pos.Column, Offset: pos.Offset}
+}
+
+func (r report) jsonErrors(errors []jsonschema.ValidationError) []jsonError {
+	out := []jsonError{}
+	for _, e := range errors {
+		uri := e.URI
+		uri.Fragment = ""
+
+		out = append(out, jsonError{
+			InstancePath:     e.InstancePath.String(),
+			InstancePosition: newJSONPosition(e.InstancePosition, true),
+			SchemaPath:       e.SchemaPath.String(),
+			SchemaPosition:   newJSONPosition(r.validator.SchemaPosition(e.URI, e.SchemaPath)),
+			URI:              uri.String(),
+			Causes:           r.jsonErrors(e.Causes),
+			Matches:          e.Matches,
+		})
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
+}
+
+func writeJSON(w io.Writer, r report) error {
+	out := jsonReport{Valid: true, Documents: []jsonDocument{}}
+	for _, doc := range r.docs {
+		jsonDoc := jsonDocument{
+			Name:       doc.name,
+			Valid:      doc.err == nil && doc.result.IsValid(),
+			Errors:     r.jsonErrors(doc.result.Errors),
+			Overflowed: doc.result.Overflowed,
+		}
+
+		if doc.err != nil {
+			jsonDoc.Error = r.errorLines(doc)[0]
+		}
+
+		out.Valid = out.Valid && jsonDoc.Valid
+		out.Documents = append(out.Documents, jsonDoc)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, r report) error {
+	suite := junitTestSuite{Name: r.schema, Tests: len(r.docs), Cases: []junitTestCase{}}
+	for _, doc := range r.docs {
+		testCase := junitTestCase{Name: doc.name, ClassName: r.schema}
+		text := strings.Join(r.errorLines(doc), "\n")
+
+		if doc.err != nil {
+			suite.Errors++
+			testCase.Error = &junitProblem{Message: "document could not be read", Text: text}
+		} else if !doc.result.IsValid() {
+			suite.Failures++
+			testCase.Failure = &junitProblem{
+				Message: fmt.Sprintf("%d validation errors", len(doc.result.Errors)),
+				Text:    text,
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
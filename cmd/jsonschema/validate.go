@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/json-schema-spec/json-schema-go"
+)
+
+// stringsFlag is a flag which may be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// document is the outcome of validating a single document. For files holding
+// several documents, such as newline-delimited JSON, there is one document for
+// each.
+type document struct {
+	name   string
+	result jsonschema.ValidationResult
+
+	// err is set if the document could not be read or decoded
+	err error
+}
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jsonschema validate -s schema.json [-r ref.json ...] [flags] [file ...]\n\n")
+		fmt.Fprintf(stderr, "Validates each file against the schema. Files may be glob patterns. With no\n")
+		fmt.Fprintf(stderr, "files, or a file of \"-\", standard input is read.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	var refs stringsFlag
+	schemaPath := flags.String("s", "", "schema to validate against (required)")
+	flags.Var(&refs, "r", "schema referred to by the schema; may be repeated")
+	maxErrors := flags.Int("max-errors", 0, "maximum number of errors to report per document; 0 for no limit")
+	format := flags.String("format", "text", "output format: text, json or junit")
+	inputFormat := flags.String("input-format", "auto", "format of documents: auto, json, ndjson, yaml or toml; auto picks by file extension, and reads standard input as json")
+	coveragePath := flags.String("coverage", "", "write a report of which parts of the schema were evaluated to this file; HTML if it ends in .html, JSON otherwise")
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+
+		return exitError
+	}
+
+	if *schemaPath == "" {
+		fmt.Fprintf(stderr, "jsonschema validate: -s is required\n")
+		flags.Usage()
+		return exitError
+	}
+
+	write, ok := formats[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "jsonschema validate: unknown format %q\n", *format)
+		return exitError
+	}
+
+	if !isInputFormat(*inputFormat) {
+		fmt.Fprintf(stderr, "jsonschema validate: unknown input format %q\n", *inputFormat)
+		return exitError
+	}
+
+	validator, uri, err := loadValidator(*schemaPath, refs, jsonschema.ValidatorConfig{
+		MaxStackDepth: jsonschema.DefaultMaxStackDepth,
+		MaxErrors:     *maxErrors,
+		Coverage:      *coveragePath != "",
+	})
+	if err != nil {
+		printSchemaError(stderr, "validate", err)
+		return exitError
+	}
+
+	paths, err := expandPaths(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonschema validate: %v\n", err)
+		return exitError
+	}
+
+	docs := []document{}
+	for _, path := range paths {
+		docs = append(docs, validateFile(&validator, uri, path, *inputFormat, stdin)...)
+	}
+
+	if err := write(stdout, report{validator: &validator, schema: *schemaPath, docs: docs}); err != nil {
+		fmt.Fprintf(stderr, "jsonschema validate: %v\n", err)
+		return exitError
+	}
+
+	if *coveragePath != "" {
+		if err := writeCoverage(*coveragePath, validator.Coverage()); err != nil {
+			fmt.Fprintf(stderr, "jsonschema validate: %v\n", err)
+			return exitError
+		}
+	}
+
+	code := exitOK
+	for _, doc := range docs {
+		if doc.err != nil {
+			return exitError
+		}
+
+		if !doc.result.IsValid() {
+			code = exitInvalid
+		}
+	}
+
+	return code
+}
+
+// writeCoverage writes a coverage report to path, as HTML if path ends in
+// ".html", and otherwise as JSON.
+func writeCoverage(path string, report jsonschema.CoverageReport) error {
+	var buf bytes.Buffer
+	if filepath.Ext(path) == ".html" {
+		if err := report.WriteHTML(&buf); err != nil {
+			return err
+		}
+	} else {
+		if err := report.WriteJSON(&buf); err != nil {
+			return err
+		}
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// loadValidator compiles the schema at path, along with the schemas it refers
+// to, and returns the URI to validate documents against.
+func loadValidator(path string, refs []string, config jsonschema.ValidatorConfig) (jsonschema.Validator, url.URL, error) {
+	// the schema goes last, so that it is the default schema if it has no $id
+	sources, err := readSources(append(refs, path))
+	if err != nil {
+		return jsonschema.Validator{}, url.URL{}, err
+	}
+
+	validator, err := jsonschema.NewValidatorFromJSON(sources, config)
+	if err != nil {
+		return jsonschema.Validator{}, url.URL{}, err
+	}
+
+	schema, _, err := jsonschema.DecodeJSON(path, sources[len(sources)-1].Data)
+	if err != nil {
+		return jsonschema.Validator{}, url.URL{}, err
+	}
+
+	uri := url.URL{}
+	if object, ok := schema.(map[string]interface{}); ok {
+		if id, ok := object["$id"].(string); ok {
+			parsed, err := url.Parse(id)
+			if err != nil {
+				return jsonschema.Validator{}, url.URL{}, err
+			}
+
+			uri = *parsed
+		}
+	}
+
+	return validator, uri, nil
+}
+
+func readSources(paths []string) ([]jsonschema.Source, error) {
+	sources := []jsonschema.Source{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, jsonschema.Source{Name: path, Data: data})
+	}
+
+	return sources, nil
+}
+
+func printSchemaError(w io.Writer, cmd string, err error) {
+	if schemaErrs, ok := err.(jsonschema.ErrSchemaErrors); ok {
+		for _, schemaErr := range schemaErrs.Errors {
+			fmt.Fprintf(w, "%v\n", schemaErr)
+		}
+
+		return
+	}
+
+	fmt.Fprintf(w, "jsonschema %s: %v\n", cmd, err)
+}
+
+// expandPaths expands glob patterns among paths. Patterns which match nothing
+// are an error, as they are most likely mistakes. With no paths, standard
+// input is read.
+func expandPaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return []string{"-"}, nil
+	}
+
+	expanded := []string{}
+	for _, path := range paths {
+		if path == "-" || !strings.ContainsAny(path, "*?[") {
+			expanded = append(expanded, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", path)
+		}
+
+		expanded = append(expanded, matches...)
+	}
+
+	return expanded, nil
+}
+
+// validateFuncs holds how to validate a document of each input format, except
+// ndjson, which holds many documents.
+var validateFuncs = map[string]func(v *jsonschema.Validator, uri url.URL, name string, data []byte) (jsonschema.ValidationResult, error){
+	"json": (*jsonschema.Validator).ValidateJSONURI,
+	"yaml": (*jsonschema.Validator).ValidateYAMLURI,
+	"toml": (*jsonschema.Validator).ValidateTOMLURI,
+}
+
+func isInputFormat(format string) bool {
+	_, ok := validateFuncs[format]
+	return ok || format == "ndjson" || format == "auto"
+}
+
+var extensionFormats = map[string]string{
+	".json":   "json",
+	".ndjson": "ndjson",
+	".jsonl":  "ndjson",
+	".yaml":   "yaml",
+	".yml":    "yaml",
+	".toml":   "toml",
+}
+
+// validateFile validates the documents in the file at path, or in stdin if
+// path is "-".
+func validateFile(v *jsonschema.Validator, uri url.URL, path, format string, stdin io.Reader) []document {
+	var data []byte
+	var err error
+
+	name := path
+	if path == "-" {
+		name = "<stdin>"
+		data, err = ioutil.ReadAll(stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+
+	if err != nil {
+		return []document{{name: name, err: err}}
+	}
+
+	if format == "auto" {
+		format = extensionFormats[strings.ToLower(filepath.Ext(path))]
+		if format == "" {
+			format = "json"
+		}
+	}
+
+	if format == "ndjson" {
+		return validateNDJSON(v, uri, name, data)
+	}
+
+	result, err := validateFuncs[format](v, uri, name, data)
+	return []document{{name: name, result: result, err: err}}
+}
+
+// validateNDJSON validates each non-blank line of data as a separate JSON
+// document. Documents are named after the line they are on.
+func validateNDJSON(v *jsonschema.Validator, uri url.URL, name string, data []byte) []document {
+	docs := []document{}
+
+	offset := 0
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		lineOffset := offset
+		offset += len(line) + 1
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		result, err := v.ValidateJSONURI(uri, name, line)
+		if syntaxErr, ok := err.(jsonschema.ErrSyntax); ok {
+			syntaxErr.Position = shiftPosition(syntaxErr.Position, i, lineOffset)
+			err = syntaxErr
+		}
+
+		shiftErrors(result.Errors, i, lineOffset)
+		docs = append(docs, document{name: fmt.Sprintf("%s:%d", name, i+1), result: result, err: err})
+	}
+
+	return docs
+}
+
+// shiftErrors moves the positions of errors found in a single line of a file to
+// where that line is in the file.
+func shiftErrors(errors []jsonschema.ValidationError, lines, offset int) {
+	for i := range errors {
+		errors[i].InstancePosition = shiftPosition(errors[i].InstancePosition, lines, offset)
+		shiftErrors(errors[i].Causes, lines, offset)
+	}
+}
+
+func shiftPosition(pos jsonschema.Position, lines, offset int) jsonschema.Position {
+	if pos.IsValid() {
+		pos.Line += lines
+		pos.Offset += offset
+	}
+
+	return pos
+}
@@ -0,0 +1,726 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// SchemaChange is a difference between two versions of a schema, found by
+// Compare.
+type SchemaChange struct {
+	// Breaking is true if the change makes the new version reject instances
+	// the old version accepted. Changes which make the new version accept more
+	// instances are not breaking.
+	Breaking bool
+
+	// A human-readable description of the change.
+	Message string
+
+	// Where the change is in each version. If a keyword was added or removed,
+	// the location in the version lacking it is where it would be.
+	Old KeywordLocation
+	New KeywordLocation
+}
+
+// String describes the change in terms of its location in the new version.
+func (c SchemaChange) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+
+	uri := c.New.URI
+	uri.Fragment = ""
+
+	return fmt.Sprintf("%s: %s#%s: %s", kind, uri.String(), c.New.Path.String(), c.Message)
+}
+
+// Compare finds the differences between the default schemas of two
+// Validators, old and new, and classifies them as breaking or not. See
+// CompareURI.
+func Compare(old, new *Validator) ([]SchemaChange, error) {
+	return CompareURI(old, url.URL{}, new, url.URL{})
+}
+
+// CompareURI finds the differences between the schema identified by oldURI in
+// old and the schema identified by newURI in new, and classifies them as
+// breaking or not.
+//
+// The schemas are walked side by side, matching up subschemas by property
+// name, array index and position within "allOf", "anyOf" and "oneOf", and
+// following references which make up the whole of a schema. Where one version
+// lacks a subschema, it is compared as if it were true, so that, for instance,
+// adding a property with a "type" narrows what the property accepts.
+//
+// Some changes cannot be classified precisely, such as a changed "pattern", and
+// are reported as breaking. Every change within a "oneOf" alternative is
+// breaking, as an alternative which accepts more instances may come to match
+// instances another alternative already matches. Each change is reported once, even if the changed
+// schema is reachable along several paths.
+//
+// If either schema does not exist, ErrNoSuchSchema is returned.
+func CompareURI(old *Validator, oldURI url.URL, new *Validator, newURI url.URL) ([]SchemaChange, error) {
+	c := comparer{
+		old:     old,
+		new:     new,
+		oldURIs: schemaURIs(old.registry),
+		newURIs: schemaURIs(new.registry),
+		visited: map[comparePair]bool{},
+		changes: []SchemaChange{},
+	}
+
+	oldIndex, ok := old.registry.schemas[oldURI]
+	if !ok {
+		return nil, ErrNoSuchSchema
+	}
+
+	newIndex, ok := new.registry.schemas[newURI]
+	if !ok {
+		return nil, ErrNoSuchSchema
+	}
+
+	c.compare(c.oldSide(oldIndex), c.newSide(newIndex))
+	return c.changes, nil
+}
+
+// schemaURIs returns the URI of each schema in a registry, by index.
+func schemaURIs(r registry) map[int]url.URL {
+	uris := map[int]url.URL{}
+	for uri, index := range r.schemas {
+		uris[index] = uri
+	}
+
+	return uris
+}
+
+type comparer struct {
+	old, new         *Validator
+	oldURIs, newURIs map[int]url.URL
+
+	// negated is true while comparing the subschemas of "not", whose changes
+	// have the opposite effect.
+	negated bool
+
+	// inOneOf is true while comparing the alternatives of "oneOf", where
+	// widening an alternative may make it match instances another alternative
+	// already matches, and so every change is breaking.
+	inOneOf bool
+
+	visited map[comparePair]bool
+	changes []SchemaChange
+}
+
+type comparePair struct {
+	old, new int
+	negated  bool
+	inOneOf  bool
+}
+
+// compareSide is a subschema of one version of a schema. An index of -1 means
+// the version lacks the subschema, which is treated as true.
+type compareSide struct {
+	index    int
+	location KeywordLocation
+}
+
+// emptySchema stands in for missing subschemas.
+var emptySchema = schema{}
+
+func (c *comparer) oldSide(index int) compareSide {
+	return sideAt(c.oldURIs, index)
+}
+
+func (c *comparer) newSide(index int) compareSide {
+	return sideAt(c.newURIs, index)
+}
+
+func sideAt(uris map[int]url.URL, index int) compareSide {
+	uri := uris[index]
+
+	// fragments are known to be valid JSON Pointers, as they were parsed
+	ptr, _ := jsonpointer.New(uri.Fragment)
+	uri.Fragment = ""
+
+	return compareSide{index: index, location: KeywordLocation{URI: uri, Path: ptr}}
+}
+
+// missing returns a side for a subschema which parent lacks.
+func missing(parent compareSide, tokens ...string) compareSide {
+	return compareSide{index: -1, location: parent.at(tokens...)}
+}
+
+// at returns the location of a keyword within the side.
+func (s compareSide) at(tokens ...string) KeywordLocation {
+	path := make([]string, 0, len(s.location.Path.Tokens)+len(tokens))
+	path = append(path, s.location.Path.Tokens...)
+	return KeywordLocation{URI: s.location.URI, Path: jsonpointer.Ptr{Tokens: append(path, tokens...)}}
+}
+
+// sub returns the side for a subschema of parent, which is missing if ok is
+// false.
+func (c *comparer) sub(v *Validator, parent compareSide, index int, ok bool, tokens ...string) compareSide {
+	if !ok {
+		return missing(parent, tokens...)
+	}
+
+	if v == c.old {
+		return c.oldSide(index)
+	}
+
+	return c.newSide(index)
+}
+
+func (c *comparer) schema(v *Validator, side compareSide) *schema {
+	if side.index == -1 {
+		return &emptySchema
+	}
+
+	return v.registry.GetIndex(side.index)
+}
+
+// resolve follows references which make up the whole of a schema.
+func (c *comparer) resolve(v *Validator, side compareSide) compareSide {
+	// a cycle of references visits each schema at most once before repeating
+	for i := 0; side.index != -1 && i < len(v.registry.arena.schemas); i++ {
+		s := c.schema(v, side)
+		if !s.Ref.IsSet || !isRefOnly(s) {
+			break
+		}
+
+		side = c.sub(v, side, s.Ref.Schema, true)
+	}
+
+	return side
+}
+
+// isRefOnly determines whether a schema has no keywords besides "$ref".
+func isRefOnly(s *schema) bool {
+	value := reflect.ValueOf(*s)
+	for i := 0; i < value.NumField(); i++ {
+		switch value.Type().Field(i).Name {
+		case "ID", "Ref", "Program":
+			continue
+		case "Keywords":
+			if len(s.Keywords) > 0 {
+				return false
+			}
+		default:
+			if value.Field(i).FieldByName("IsSet").Bool() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (c *comparer) report(o, n compareSide, keyword []string, breaking bool, format string, args ...interface{}) {
+	c.changes = append(c.changes, SchemaChange{
+		Breaking: breaking != c.negated || c.inOneOf,
+		Message:  fmt.Sprintf(format, args...),
+		Old:      o.at(keyword...),
+		New:      n.at(keyword...),
+	})
+}
+
+// differs determines whether comparing two subschemas finds any changes.
+func (c *comparer) differs(o, n compareSide) bool {
+	sub := *c
+	sub.visited = map[comparePair]bool{}
+	sub.changes = nil
+	sub.compare(o, n)
+
+	return len(sub.changes) > 0
+}
+
+func (c *comparer) compare(o, n compareSide) {
+	o, n = c.resolve(c.old, o), c.resolve(c.new, n)
+	if o.index == -1 && n.index == -1 {
+		return
+	}
+
+	pair := comparePair{old: o.index, new: n.index, negated: c.negated, inOneOf: c.inOneOf}
+	if c.visited[pair] {
+		return
+	}
+
+	c.visited[pair] = true
+
+	so, sn := c.schema(c.old, o), c.schema(c.new, n)
+
+	oldFalse := so.Bool.IsSet && !so.Bool.Value
+	newFalse := sn.Bool.IsSet && !sn.Bool.Value
+	if oldFalse || newFalse {
+		if !oldFalse {
+			c.report(o, n, nil, true, "schema now rejects every instance")
+		} else if !newFalse {
+			c.report(o, n, nil, false, "schema no longer rejects every instance")
+		}
+
+		return
+	}
+
+	c.compareType(o, n, so, sn)
+	c.compareValues(o, n, so, sn)
+	c.compareBounds(o, n, so, sn)
+	c.compareStrings(o, n, so, sn)
+	c.compareRequired(o, n, so, sn)
+	c.compareDependencies(o, n, so, sn)
+	c.compareItems(o, n, so, sn)
+	c.compareProperties(o, n, so, sn)
+	c.compareApplicators(o, n, so, sn)
+	c.compareKeywords(o, n, so, sn)
+}
+
+// typeNames are the names of the JSON types, in the order in which changes to
+// them are reported.
+var typeNames = []struct {
+	typ  jsonType
+	name string
+}{
+	{jsonTypeNull, "null"},
+	{jsonTypeBoolean, "boolean"},
+	{jsonTypeInteger, "integer"},
+	{jsonTypeNumber, "number"},
+	{jsonTypeString, "string"},
+	{jsonTypeArray, "array"},
+	{jsonTypeObject, "object"},
+}
+
+// allowsType determines whether a schema's "type" allows values of typ.
+// Integers are allowed by "number" as well as "integer".
+func allowsType(s *schema, typ jsonType) bool {
+	if !s.Type.IsSet {
+		return true
+	}
+
+	return s.Type.contains(typ) || (typ == jsonTypeInteger && s.Type.contains(jsonTypeNumber))
+}
+
+func (c *comparer) compareType(o, n compareSide, so, sn *schema) {
+	for _, t := range typeNames {
+		oldAllows, newAllows := allowsType(so, t.typ), allowsType(sn, t.typ)
+		if oldAllows && !newAllows {
+			c.report(o, n, []string{"type"}, true, "\"type\" no longer allows %s", t.name)
+		} else if !oldAllows && newAllows {
+			c.report(o, n, []string{"type"}, false, "\"type\" now allows %s", t.name)
+		}
+	}
+}
+
+func encodeValue(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func (c *comparer) compareValues(o, n compareSide, so, sn *schema) {
+	if sn.Const.IsSet && (!so.Const.IsSet || !jsonEqual(so.Const.Value, sn.Const.Value)) {
+		c.report(o, n, []string{"const"}, true, "\"const\" now requires %s", encodeValue(sn.Const.Value))
+	} else if so.Const.IsSet && !sn.Const.IsSet {
+		c.report(o, n, []string{"const"}, false, "\"const\" removed")
+	}
+
+	switch {
+	case so.Enum.IsSet && sn.Enum.IsSet:
+		for _, value := range so.Enum.Values {
+			if !sn.Enum.Set.contains(value) {
+				c.report(o, n, []string{"enum"}, true, "enum value %s removed", encodeValue(value))
+			}
+		}
+
+		for _, value := range sn.Enum.Values {
+			if !so.Enum.Set.contains(value) {
+				c.report(o, n, []string{"enum"}, false, "enum value %s added", encodeValue(value))
+			}
+		}
+	case sn.Enum.IsSet:
+		c.report(o, n, []string{"enum"}, true, "\"enum\" added")
+	case so.Enum.IsSet:
+		c.report(o, n, []string{"enum"}, false, "\"enum\" removed")
+	}
+}
+
+func (c *comparer) compareBounds(o, n compareSide, so, sn *schema) {
+	bounds := []struct {
+		keyword            string
+		oldIsSet, newIsSet bool
+		oldValue, newValue float64
+		lower              bool
+	}{
+		{"minimum", so.Minimum.IsSet, sn.Minimum.IsSet, so.Minimum.Value, sn.Minimum.Value, true},
+		{"exclusiveMinimum", so.ExclusiveMinimum.IsSet, sn.ExclusiveMinimum.IsSet, so.ExclusiveMinimum.Value, sn.ExclusiveMinimum.Value, true},
+		{"maximum", so.Maximum.IsSet, sn.Maximum.IsSet, so.Maximum.Value, sn.Maximum.Value, false},
+		{"exclusiveMaximum", so.ExclusiveMaximum.IsSet, sn.ExclusiveMaximum.IsSet, so.ExclusiveMaximum.Value, sn.ExclusiveMaximum.Value, false},
+		{"minLength", so.MinLength.IsSet, sn.MinLength.IsSet, float64(so.MinLength.Value), float64(sn.MinLength.Value), true},
+		{"maxLength", so.MaxLength.IsSet, sn.MaxLength.IsSet, float64(so.MaxLength.Value), float64(sn.MaxLength.Value), false},
+		{"minItems", so.MinItems.IsSet, sn.MinItems.IsSet, float64(so.MinItems.Value), float64(sn.MinItems.Value), true},
+		{"maxItems", so.MaxItems.IsSet, sn.MaxItems.IsSet, float64(so.MaxItems.Value), float64(sn.MaxItems.Value), false},
+		{"minProperties", so.MinProperties.IsSet, sn.MinProperties.IsSet, float64(so.MinProperties.Value), float64(sn.MinProperties.Value), true},
+		{"maxProperties", so.MaxProperties.IsSet, sn.MaxProperties.IsSet, float64(so.MaxProperties.Value), float64(sn.MaxProperties.Value), false},
+	}
+
+	for _, b := range bounds {
+		keyword := []string{b.keyword}
+
+		switch {
+		case b.oldIsSet && b.newIsSet && b.oldValue != b.newValue:
+			// raising a lower bound, or lowering an upper one, narrows it
+			raised := b.newValue > b.oldValue
+			direction := "lowered"
+			if raised {
+				direction = "raised"
+			}
+
+			c.report(o, n, keyword, raised == b.lower, "%q %s from %v to %v", b.keyword, direction, b.oldValue, b.newValue)
+		case b.newIsSet && !b.oldIsSet:
+			c.report(o, n, keyword, true, "%q of %v added", b.keyword, b.newValue)
+		case b.oldIsSet && !b.newIsSet:
+			c.report(o, n, keyword, false, "%q removed", b.keyword)
+		}
+	}
+
+	keyword := []string{"multipleOf"}
+	switch {
+	case so.MultipleOf.IsSet && sn.MultipleOf.IsSet && so.MultipleOf.Value != sn.MultipleOf.Value:
+		// multiples of the old value remain valid if the new value divides it
+		relaxed := math.Mod(so.MultipleOf.Value, sn.MultipleOf.Value) == 0
+		c.report(o, n, keyword, !relaxed, "\"multipleOf\" changed from %v to %v", so.MultipleOf.Value, sn.MultipleOf.Value)
+	case sn.MultipleOf.IsSet && !so.MultipleOf.IsSet:
+		c.report(o, n, keyword, true, "\"multipleOf\" of %v added", sn.MultipleOf.Value)
+	case so.MultipleOf.IsSet && !sn.MultipleOf.IsSet:
+		c.report(o, n, keyword, false, "\"multipleOf\" removed")
+	}
+}
+
+func (c *comparer) compareStrings(o, n compareSide, so, sn *schema) {
+	keyword := []string{"pattern"}
+	switch {
+	case so.Pattern.IsSet && sn.Pattern.IsSet && so.Pattern.Value.String() != sn.Pattern.Value.String():
+		c.report(o, n, keyword, true, "\"pattern\" changed from %q to %q", so.Pattern.Value.String(), sn.Pattern.Value.String())
+	case sn.Pattern.IsSet && !so.Pattern.IsSet:
+		c.report(o, n, keyword, true, "\"pattern\" %q added", sn.Pattern.Value.String())
+	case so.Pattern.IsSet && !sn.Pattern.IsSet:
+		c.report(o, n, keyword, false, "\"pattern\" removed")
+	}
+
+	oldUnique := so.UniqueItems.IsSet && so.UniqueItems.Value
+	newUnique := sn.UniqueItems.IsSet && sn.UniqueItems.Value
+	if newUnique && !oldUnique {
+		c.report(o, n, []string{"uniqueItems"}, true, "items must now be unique")
+	} else if oldUnique && !newUnique {
+		c.report(o, n, []string{"uniqueItems"}, false, "items need no longer be unique")
+	}
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *comparer) compareRequired(o, n compareSide, so, sn *schema) {
+	for _, property := range sn.Required.Properties {
+		if !containsString(so.Required.Properties, property) {
+			c.report(o, n, []string{"required"}, true, "property %q is now required", property)
+		}
+	}
+
+	for _, property := range so.Required.Properties {
+		if !containsString(sn.Required.Properties, property) {
+			c.report(o, n, []string{"required"}, false, "property %q is no longer required", property)
+		}
+	}
+}
+
+func (c *comparer) compareDependencies(o, n compareSide, so, sn *schema) {
+	keys := map[string]bool{}
+	for key := range so.Dependencies.Deps {
+		keys[key] = true
+	}
+
+	for key := range sn.Dependencies.Deps {
+		keys[key] = true
+	}
+
+	for _, key := range sortedSet(keys) {
+		keyword := []string{"dependencies", key}
+		oldDep, oldOK := so.Dependencies.Deps[key]
+		newDep, newOK := sn.Dependencies.Deps[key]
+
+		switch {
+		case !oldOK:
+			c.report(o, n, keyword, true, "dependency of %q added", key)
+		case !newOK:
+			c.report(o, n, keyword, false, "dependency of %q removed", key)
+		case oldDep.IsSchema && newDep.IsSchema:
+			c.compare(c.sub(c.old, o, oldDep.Schema, true), c.sub(c.new, n, newDep.Schema, true))
+		case oldDep.IsSchema || newDep.IsSchema:
+			c.report(o, n, keyword, true, "dependency of %q changed", key)
+		default:
+			for _, property := range newDep.Properties {
+				if !containsString(oldDep.Properties, property) {
+					c.report(o, n, keyword, true, "property %q now depends on %q", key, property)
+				}
+			}
+
+			for _, property := range oldDep.Properties {
+				if !containsString(newDep.Properties, property) {
+					c.report(o, n, keyword, false, "property %q no longer depends on %q", key, property)
+				}
+			}
+		}
+	}
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// itemSide returns the subschema which applies to the i-th item of arrays, or
+// to the items after the last positional one if i is -1.
+func (c *comparer) itemSide(v *Validator, side compareSide, s *schema, i int) compareSide {
+	if !s.Items.IsSet {
+		return missing(side, "items")
+	}
+
+	if s.Items.IsSingle {
+		return c.sub(v, side, s.Items.Schemas[0], true)
+	}
+
+	if i != -1 && i < len(s.Items.Schemas) {
+		return c.sub(v, side, s.Items.Schemas[i], true)
+	}
+
+	return c.sub(v, side, s.AdditionalItems.Schema, s.AdditionalItems.IsSet, "additionalItems")
+}
+
+func (c *comparer) compareItems(o, n compareSide, so, sn *schema) {
+	positional := 0
+	if so.Items.IsSet && !so.Items.IsSingle && len(so.Items.Schemas) > positional {
+		positional = len(so.Items.Schemas)
+	}
+
+	if sn.Items.IsSet && !sn.Items.IsSingle && len(sn.Items.Schemas) > positional {
+		positional = len(sn.Items.Schemas)
+	}
+
+	for i := 0; i < positional; i++ {
+		c.compare(c.itemSide(c.old, o, so, i), c.itemSide(c.new, n, sn, i))
+	}
+
+	c.compare(c.itemSide(c.old, o, so, -1), c.itemSide(c.new, n, sn, -1))
+
+	switch {
+	case so.Contains.IsSet && sn.Contains.IsSet:
+		c.compare(c.sub(c.old, o, so.Contains.Schema, true), c.sub(c.new, n, sn.Contains.Schema, true))
+	case sn.Contains.IsSet:
+		c.report(o, n, []string{"contains"}, true, "\"contains\" added")
+	case so.Contains.IsSet:
+		c.report(o, n, []string{"contains"}, false, "\"contains\" removed")
+	}
+}
+
+// propertySide returns the subschema which applies to a property. Properties
+// matching "patternProperties" are compared by pattern instead, and so are
+// treated as unconstrained here.
+func (c *comparer) propertySide(v *Validator, side compareSide, s *schema, property string) compareSide {
+	if index, ok := s.Properties.Schemas[property]; ok {
+		return c.sub(v, side, index, true)
+	}
+
+	for pattern := range s.PatternProperties.Schemas {
+		if pattern.MatchString(property) {
+			return missing(side, "properties", property)
+		}
+	}
+
+	return c.sub(v, side, s.AdditionalProperties.Schema, s.AdditionalProperties.IsSet, "additionalProperties")
+}
+
+func (c *comparer) compareProperties(o, n compareSide, so, sn *schema) {
+	properties := map[string]bool{}
+	for property := range so.Properties.Schemas {
+		properties[property] = true
+	}
+
+	for property := range sn.Properties.Schemas {
+		properties[property] = true
+	}
+
+	for _, property := range sortedSet(properties) {
+		c.compare(c.propertySide(c.old, o, so, property), c.propertySide(c.new, n, sn, property))
+	}
+
+	oldPatterns := map[string]int{}
+	patterns := map[string]bool{}
+	for pattern, index := range so.PatternProperties.Schemas {
+		oldPatterns[pattern.String()] = index
+		patterns[pattern.String()] = true
+	}
+
+	newPatterns := map[string]int{}
+	for pattern, index := range sn.PatternProperties.Schemas {
+		newPatterns[pattern.String()] = index
+		patterns[pattern.String()] = true
+	}
+
+	for _, pattern := range sortedSet(patterns) {
+		oldIndex, oldOK := oldPatterns[pattern]
+		newIndex, newOK := newPatterns[pattern]
+		c.compare(
+			c.sub(c.old, o, oldIndex, oldOK, "patternProperties", pattern),
+			c.sub(c.new, n, newIndex, newOK, "patternProperties", pattern),
+		)
+	}
+
+	c.compare(
+		c.sub(c.old, o, so.AdditionalProperties.Schema, so.AdditionalProperties.IsSet, "additionalProperties"),
+		c.sub(c.new, n, sn.AdditionalProperties.Schema, sn.AdditionalProperties.IsSet, "additionalProperties"),
+	)
+
+	c.compare(
+		c.sub(c.old, o, so.PropertyNames.Schema, so.PropertyNames.IsSet, "propertyNames"),
+		c.sub(c.new, n, sn.PropertyNames.Schema, sn.PropertyNames.IsSet, "propertyNames"),
+	)
+}
+
+func (c *comparer) compareApplicators(o, n compareSide, so, sn *schema) {
+	c.compare(
+		c.sub(c.old, o, so.Ref.Schema, so.Ref.IsSet, "$ref"),
+		c.sub(c.new, n, sn.Ref.Schema, sn.Ref.IsSet, "$ref"),
+	)
+
+	switch {
+	case so.Not.IsSet && sn.Not.IsSet:
+		c.negated = !c.negated
+		c.compare(c.sub(c.old, o, so.Not.Schema, true), c.sub(c.new, n, sn.Not.Schema, true))
+		c.negated = !c.negated
+	case sn.Not.IsSet:
+		c.report(o, n, []string{"not"}, true, "\"not\" added")
+	case so.Not.IsSet:
+		c.report(o, n, []string{"not"}, false, "\"not\" removed")
+	}
+
+	oldIf := c.sub(c.old, o, so.If.Schema, so.If.IsSet, "if")
+	newIf := c.sub(c.new, n, sn.If.Schema, sn.If.IsSet, "if")
+	switch {
+	case so.If.IsSet && sn.If.IsSet && c.differs(oldIf, newIf):
+		c.report(o, n, []string{"if"}, true, "\"if\" changed")
+	case so.If.IsSet && sn.If.IsSet:
+		c.compare(c.sub(c.old, o, so.Then.Schema, so.Then.IsSet, "then"), c.sub(c.new, n, sn.Then.Schema, sn.Then.IsSet, "then"))
+		c.compare(c.sub(c.old, o, so.Else.Schema, so.Else.IsSet, "else"), c.sub(c.new, n, sn.Else.Schema, sn.Else.IsSet, "else"))
+	case sn.If.IsSet:
+		c.report(o, n, []string{"if"}, true, "\"if\" added")
+	case so.If.IsSet:
+		c.report(o, n, []string{"if"}, false, "\"if\" removed")
+	}
+
+	// a missing "allOf" subschema is true, so added subschemas are compared
+	// against true
+	c.compareSchemaArrays(o, n, "allOf", so.AllOf.Schemas, sn.AllOf.Schemas)
+
+	switch {
+	case so.AnyOf.IsSet && sn.AnyOf.IsSet:
+		shared := minInt(len(so.AnyOf.Schemas), len(sn.AnyOf.Schemas))
+		c.compareSchemaArrays(o, n, "anyOf", so.AnyOf.Schemas[:shared], sn.AnyOf.Schemas[:shared])
+		for i := len(so.AnyOf.Schemas); i < len(sn.AnyOf.Schemas); i++ {
+			c.report(o, n, []string{"anyOf", strconv.Itoa(i)}, false, "\"anyOf\" alternative added")
+		}
+
+		for i := len(sn.AnyOf.Schemas); i < len(so.AnyOf.Schemas); i++ {
+			c.report(o, n, []string{"anyOf", strconv.Itoa(i)}, true, "\"anyOf\" alternative removed")
+		}
+	case sn.AnyOf.IsSet:
+		c.report(o, n, []string{"anyOf"}, true, "\"anyOf\" added")
+	case so.AnyOf.IsSet:
+		c.report(o, n, []string{"anyOf"}, false, "\"anyOf\" removed")
+	}
+
+	switch {
+	case so.OneOf.IsSet && sn.OneOf.IsSet:
+		// any added alternative may match instances another already matches, and
+		// any removed one may have been the only match
+		shared := minInt(len(so.OneOf.Schemas), len(sn.OneOf.Schemas))
+		inOneOf := c.inOneOf
+		c.inOneOf = true
+		c.compareSchemaArrays(o, n, "oneOf", so.OneOf.Schemas[:shared], sn.OneOf.Schemas[:shared])
+		c.inOneOf = inOneOf
+		if len(so.OneOf.Schemas) != len(sn.OneOf.Schemas) {
+			c.report(o, n, []string{"oneOf"}, true, "\"oneOf\" alternatives changed from %d to %d", len(so.OneOf.Schemas), len(sn.OneOf.Schemas))
+		}
+	case sn.OneOf.IsSet:
+		c.report(o, n, []string{"oneOf"}, true, "\"oneOf\" added")
+	case so.OneOf.IsSet:
+		c.report(o, n, []string{"oneOf"}, false, "\"oneOf\" removed")
+	}
+}
+
+// compareSchemaArrays compares subschemas by position. Subschemas missing from
+// the shorter array are compared as if they were true.
+func (c *comparer) compareSchemaArrays(o, n compareSide, keyword string, oldSchemas, newSchemas []int) {
+	for i := 0; i < len(oldSchemas) || i < len(newSchemas); i++ {
+		token := strconv.Itoa(i)
+
+		oldSide := missing(o, keyword, token)
+		if i < len(oldSchemas) {
+			oldSide = c.sub(c.old, o, oldSchemas[i], true)
+		}
+
+		newSide := missing(n, keyword, token)
+		if i < len(newSchemas) {
+			newSide = c.sub(c.new, n, newSchemas[i], true)
+		}
+
+		c.compare(oldSide, newSide)
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// compareKeywords reports custom keywords which were added or removed. Changes
+// to their values cannot be classified.
+func (c *comparer) compareKeywords(o, n compareSide, so, sn *schema) {
+	oldKeywords, newKeywords := map[string]bool{}, map[string]bool{}
+	for _, keyword := range so.Keywords {
+		oldKeywords[keyword.Name] = true
+	}
+
+	for _, keyword := range sn.Keywords {
+		newKeywords[keyword.Name] = true
+	}
+
+	for _, keyword := range sn.Keywords {
+		if !oldKeywords[keyword.Name] {
+			c.report(o, n, []string{keyword.Name}, true, "custom keyword %q added", keyword.Name)
+		}
+	}
+
+	for _, keyword := range so.Keywords {
+		if !newKeywords[keyword.Name] {
+			c.report(o, n, []string{keyword.Name}, false, "custom keyword %q removed", keyword.Name)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package jsonschema
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucarion/json-pointer"
+)
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		old     string
+		new     string
+		changes []string
+	}{
+		{`{"type": "string"}`, `{"type": "string"}`, []string{}},
+		{
+			`{"type": ["number", "null"]}`,
+			`{"type": ["integer", "string"]}`,
+			[]string{
+				`breaking: #/type: "type" no longer allows null`,
+				`breaking: #/type: "type" no longer allows number`,
+				`non-breaking: #/type: "type" now allows string`,
+			},
+		},
+		{
+			`{"required": ["a"], "properties": {"a": {"minLength": 1}, "b": {"enum": [1, 2]}}}`,
+			`{"required": ["b"], "properties": {"a": {"minLength": 3}, "b": {"enum": [2, 3]}}}`,
+			[]string{
+				`breaking: #/required: property "b" is now required`,
+				`non-breaking: #/required: property "a" is no longer required`,
+				`breaking: #/properties/a/minLength: "minLength" raised from 1 to 3`,
+				`breaking: #/properties/b/enum: enum value 1 removed`,
+				`non-breaking: #/properties/b/enum: enum value 3 added`,
+			},
+		},
+		{
+			`{"maximum": 10, "minimum": 0, "multipleOf": 2, "pattern": "^a"}`,
+			`{"maximum": 20, "exclusiveMinimum": 0, "multipleOf": 4, "pattern": "^b"}`,
+			[]string{
+				`non-breaking: #/minimum: "minimum" removed`,
+				`breaking: #/exclusiveMinimum: "exclusiveMinimum" of 0 added`,
+				`non-breaking: #/maximum: "maximum" raised from 10 to 20`,
+				`breaking: #/multipleOf: "multipleOf" changed from 2 to 4`,
+				`breaking: #/pattern: "pattern" changed from "^a" to "^b"`,
+			},
+		},
+		{
+			`{"properties": {"a": true}, "additionalProperties": {"type": "string"}}`,
+			`{"properties": {"a": true, "b": {"type": "string", "maxLength": 5}}, "additionalProperties": false}`,
+			[]string{
+				`breaking: #/properties/b/maxLength: "maxLength" of 5 added`,
+				`breaking: #/additionalProperties: schema now rejects every instance`,
+			},
+		},
+		{
+			`{"items": [{"type": "string"}], "additionalItems": false}`,
+			`{"items": [{"type": "string"}, {"type": "number"}], "uniqueItems": true}`,
+			[]string{
+				`breaking: #/uniqueItems: items must now be unique`,
+				`non-breaking: #/items/1: schema no longer rejects every instance`,
+				`non-breaking: #/additionalItems: schema no longer rejects every instance`,
+			},
+		},
+		{
+			`{"definitions": {"a": {"type": "string"}}, "properties": {"x": {"$ref": "#/definitions/a"}}}`,
+			`{"definitions": {"b": {"type": ["string", "null"]}}, "properties": {"x": {"$ref": "#/definitions/b"}}}`,
+			[]string{`non-breaking: #/definitions/b/type: "type" now allows null`},
+		},
+		{
+			`{"not": {"type": "string"}, "anyOf": [{"minimum": 1}, true], "allOf": [true]}`,
+			`{"not": {"type": ["string", "null"]}, "anyOf": [{"minimum": 1}], "allOf": [true, {"required": ["a"]}]}`,
+			[]string{
+				`breaking: #/not/type: "type" now allows null`,
+				`breaking: #/allOf/1/required: property "a" is now required`,
+				`breaking: #/anyOf/1: "anyOf" alternative removed`,
+			},
+		},
+		{
+			`{"if": {"type": "string"}, "then": {"minLength": 1}, "oneOf": [true]}`,
+			`{"if": {"type": "string"}, "then": {"minLength": 0}, "oneOf": [true, true]}`,
+			[]string{
+				`non-breaking: #/then/minLength: "minLength" lowered from 1 to 0`,
+				`breaking: #/oneOf: "oneOf" alternatives changed from 1 to 2`,
+			},
+		},
+		{
+			`{"oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+			`{"oneOf": [{"type": "string"}, {}]}`,
+			[]string{
+				`breaking: #/oneOf/1/type: "type" now allows null`,
+				`breaking: #/oneOf/1/type: "type" now allows boolean`,
+				`breaking: #/oneOf/1/type: "type" now allows number`,
+				`breaking: #/oneOf/1/type: "type" now allows string`,
+				`breaking: #/oneOf/1/type: "type" now allows array`,
+				`breaking: #/oneOf/1/type: "type" now allows object`,
+			},
+		},
+		{
+			`{"properties": {"a": {"$ref": "#"}, "b": {"const": 1}}}`,
+			`{"properties": {"a": {"$ref": "#"}, "b": {"const": 2}}}`,
+			[]string{`breaking: #/properties/b/const: "const" now requires 2`},
+		},
+	}
+
+	for _, tt := range testCases {
+		old, err := NewValidator([]interface{}{mustDecode(t, tt.old)})
+		assert.NoError(t, err, tt.old)
+
+		new, err := NewValidator([]interface{}{mustDecode(t, tt.new)})
+		assert.NoError(t, err, tt.new)
+
+		changes, err := Compare(&old, &new)
+		assert.NoError(t, err)
+
+		actual := []string{}
+		for _, change := range changes {
+			actual = append(actual, change.String())
+		}
+
+		assert.Equal(t, tt.changes, actual, "%s -> %s", tt.old, tt.new)
+	}
+}
+
+func TestCompareLocations(t *testing.T) {
+	old, err := NewValidator([]interface{}{
+		mustDecode(t, `{"$id": "http://example.com/a", "properties": {"x": {"$ref": "http://example.com/b"}}}`),
+		mustDecode(t, `{"$id": "http://example.com/b", "maximum": 3}`),
+	})
+	assert.NoError(t, err)
+
+	new, err := NewValidator([]interface{}{
+		mustDecode(t, `{"$id": "http://example.com/a", "properties": {"x": {"maximum": 2}}}`),
+	})
+	assert.NoError(t, err)
+
+	uri := url.URL{Scheme: "http", Host: "example.com", Path: "/a"}
+	changes, err := CompareURI(&old, uri, &new, uri)
+	assert.NoError(t, err)
+	assert.Equal(t, []SchemaChange{{
+		Breaking: true,
+		Message:  `"maximum" lowered from 3 to 2`,
+		Old:      KeywordLocation{URI: url.URL{Scheme: "http", Host: "example.com", Path: "/b"}, Path: jsonpointer.Ptr{Tokens: []string{"maximum"}}},
+		New:      KeywordLocation{URI: uri, Path: jsonpointer.Ptr{Tokens: []string{"properties", "x", "maximum"}}},
+	}}, changes)
+
+	_, err = Compare(&old, &new)
+	assert.Equal(t, ErrNoSuchSchema, err)
+}
@@ -0,0 +1,499 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// CoverageEntry records how often one part of a schema was evaluated, as
+// collected when the Coverage option of ValidatorConfig is enabled.
+type CoverageEntry struct {
+	// Location identifies the part of the schema. It points to either a schema,
+	// a keyword, or an entry of "dependencies".
+	Location KeywordLocation
+
+	// Keyword is the name of the keyword at Location. It is empty if Location
+	// points to a schema or to an entry of "dependencies".
+	Keyword string
+
+	// Branch indicates whether the entry is a branch which instances may or may
+	// not take: an alternative of "anyOf" or "oneOf", the "then" or "else" of an
+	// "if", or an entry of "dependencies".
+	Branch bool
+
+	// Evaluated is the number of times the part of the schema was evaluated.
+	// A schema or keyword may be evaluated many times for one instance, such as
+	// for each element of an array.
+	Evaluated int
+
+	// Passed is the number of evaluations which accepted the instance.
+	Passed int
+
+	// Failed is the number of evaluations which rejected the instance.
+	Failed int
+}
+
+// Exercised determines whether the entry has been exercised. Alternatives of
+// "anyOf" and "oneOf" are exercised once they have accepted an instance; other
+// entries are exercised once they have been evaluated.
+func (e CoverageEntry) Exercised() bool {
+	if e.Branch && e.Keyword == "" && isAlternative(e.Location.Path) {
+		return e.Passed > 0
+	}
+
+	return e.Evaluated > 0
+}
+
+func isAlternative(path jsonpointer.Ptr) bool {
+	n := len(path.Tokens)
+	return n >= 2 && (path.Tokens[n-2] == "anyOf" || path.Tokens[n-2] == "oneOf")
+}
+
+// CoverageReport describes how often each part of the schemas of a Validator
+// has been evaluated.
+type CoverageReport struct {
+	// Entries holds an entry for every schema, keyword, and entry of
+	// "dependencies" of the schemas, including those never evaluated. They are
+	// sorted by URI and path, with each schema before its keywords.
+	Entries []CoverageEntry
+}
+
+// Unexercised returns the branches of the report which have not been
+// exercised.
+func (r CoverageReport) Unexercised() []CoverageEntry {
+	entries := []CoverageEntry{}
+	for _, entry := range r.Entries {
+		if entry.Branch && !entry.Exercised() {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+type jsonCoverageEntry struct {
+	URI       string `json:"uri"`
+	Path      string `json:"path"`
+	Keyword   string `json:"keyword,omitempty"`
+	Branch    bool   `json:"branch"`
+	Exercised bool   `json:"exercised"`
+	Evaluated int    `json:"evaluated"`
+	Passed    int    `json:"passed"`
+	Failed    int    `json:"failed"`
+}
+
+// WriteJSON writes the report as a JSON array of entries. Paths and URIs are
+// encoded as strings.
+func (r CoverageReport) WriteJSON(w io.Writer) error {
+	entries := []jsonCoverageEntry{}
+	for _, entry := range r.Entries {
+		entries = append(entries, jsonCoverageEntry{
+			URI:       entry.Location.URI.String(),
+			Path:      entry.Location.Path.String(),
+			Keyword:   entry.Keyword,
+			Branch:    entry.Branch,
+			Exercised: entry.Exercised(),
+			Evaluated: entry.Evaluated,
+			Passed:    entry.Passed,
+			Failed:    entry.Failed,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+var coverageTemplate = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Schema coverage</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.2em 0.8em; text-align: left; }
+td.count { text-align: right; }
+tr.unevaluated { color: #888; }
+tr.unexercised { background: #fdd; }
+</style>
+</head>
+<body>
+<h1>Schema coverage</h1>
+<p>{{.Exercised}} of {{.Branches}} branches exercised.</p>
+{{range .Documents}}
+<h2>{{if .URI}}{{.URI}}{{else}}(default schema){{end}}</h2>
+<table>
+<tr><th>Path</th><th>Keyword</th><th>Evaluated</th><th>Passed</th><th>Failed</th></tr>
+{{range .Entries}}<tr class="{{.Class}}"><td>#{{.Path}}</td><td>{{.Keyword}}</td><td class="count">{{.Evaluated}}</td><td class="count">{{.Passed}}</td><td class="count">{{.Failed}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+type htmlCoverageDocument struct {
+	URI     string
+	Entries []htmlCoverageEntry
+}
+
+type htmlCoverageEntry struct {
+	Path      string
+	Keyword   string
+	Class     string
+	Evaluated int
+	Passed    int
+	Failed    int
+}
+
+// WriteHTML writes the report as an HTML page, with a table of entries for
+// each schema. Branches which have not been exercised are highlighted, and
+// parts of the schema never evaluated are greyed out.
+func (r CoverageReport) WriteHTML(w io.Writer) error {
+	data := struct {
+		Branches  int
+		Exercised int
+		Documents []*htmlCoverageDocument
+	}{}
+
+	var doc *htmlCoverageDocument
+	for _, entry := range r.Entries {
+		if uri := entry.Location.URI.String(); doc == nil || doc.URI != uri {
+			doc = &htmlCoverageDocument{URI: uri}
+			data.Documents = append(data.Documents, doc)
+		}
+
+		class := ""
+		switch {
+		case entry.Branch && !entry.Exercised():
+			class = "unexercised"
+		case entry.Evaluated == 0:
+			class = "unevaluated"
+		}
+
+		if entry.Branch {
+			data.Branches++
+			if entry.Exercised() {
+				data.Exercised++
+			}
+		}
+
+		doc.Entries = append(doc.Entries, htmlCoverageEntry{
+			Path:      entry.Location.Path.String(),
+			Keyword:   entry.Keyword,
+			Class:     class,
+			Evaluated: entry.Evaluated,
+			Passed:    entry.Passed,
+			Failed:    entry.Failed,
+		})
+	}
+
+	return coverageTemplate.Execute(w, data)
+}
+
+// Coverage returns how often each part of the schemas of the Validator has been
+// evaluated by Validate and its variants since the Validator was constructed,
+// or since ResetCoverage was last called. It is only collected if the Coverage
+// option of the ValidatorConfig was enabled; otherwise, every count is zero.
+//
+// Calls to IsValid and IsValidURI are not counted: they stop evaluating as soon
+// as the outcome is known, so their counts would depend on keyword order rather
+// than on the instances given. To measure the coverage of a set of instances,
+// pass them to Validate.
+func (v *Validator) Coverage() CoverageReport {
+	counts := map[coverageKey]coverageCounts{}
+	if v.coverage != nil {
+		v.coverage.mu.Lock()
+		for key, c := range v.coverage.counts {
+			counts[key] = c
+		}
+		v.coverage.mu.Unlock()
+	}
+
+	entries := map[coverageKey]*CoverageEntry{}
+	entry := func(uri url.URL, tokens []string, keyword string) *CoverageEntry {
+		uri.Fragment = ""
+		path := jsonpointer.Ptr{Tokens: tokens}
+		key := coverageKey{uri: uri.String(), path: path.String(), keyword: keyword}
+
+		e, ok := entries[key]
+		if !ok {
+			c := counts[key]
+			e = &CoverageEntry{
+				Location:  KeywordLocation{URI: uri, Path: path},
+				Keyword:   keyword,
+				Evaluated: c.evaluated,
+				Passed:    c.evaluated - c.failed,
+				Failed:    c.failed,
+			}
+
+			entries[key] = e
+		}
+
+		return e
+	}
+
+	for index, uri := range schemaURIs(v.registry) {
+		ptr, _ := jsonpointer.New(uri.Fragment)
+		tokens := ptr.Tokens
+		s := v.registry.GetIndex(index)
+
+		entry(uri, tokens, "")
+		for _, keyword := range coverageKeywords(s) {
+			child := append(tokens[:len(tokens):len(tokens)], keyword)
+
+			switch keyword {
+			case "then", "else":
+				entry(uri, child, keyword).Branch = true
+			case "anyOf", "oneOf":
+				alternatives := s.AnyOf.Schemas
+				if keyword == "oneOf" {
+					alternatives = s.OneOf.Schemas
+				}
+
+				entry(uri, child, keyword)
+				for i := range alternatives {
+					entry(uri, append(child[:len(child):len(child)], strconv.Itoa(i)), "").Branch = true
+				}
+			case "dependencies":
+				entry(uri, child, keyword)
+				for key := range s.Dependencies.Deps {
+					entry(uri, append(child[:len(child):len(child)], key), "").Branch = true
+				}
+			default:
+				entry(uri, child, keyword)
+			}
+		}
+	}
+
+	report := CoverageReport{Entries: []CoverageEntry{}}
+	for _, e := range entries {
+		report.Entries = append(report.Entries, *e)
+	}
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Location.URI.String() != b.Location.URI.String() {
+			return a.Location.URI.String() < b.Location.URI.String()
+		}
+
+		if a.Location.Path.String() != b.Location.Path.String() {
+			return a.Location.Path.String() < b.Location.Path.String()
+		}
+
+		return a.Keyword < b.Keyword
+	})
+
+	return report
+}
+
+// ResetCoverage sets every count of the coverage collected by the Validator
+// back to zero.
+func (v *Validator) ResetCoverage() {
+	if v.coverage == nil {
+		return
+	}
+
+	v.coverage.mu.Lock()
+	v.coverage.counts = map[coverageKey]coverageCounts{}
+	v.coverage.mu.Unlock()
+}
+
+// coverageKeywords returns the keywords of a schema which the vm evaluates.
+func coverageKeywords(s *schema) []string {
+	keywords := []string{}
+	add := func(isSet bool, keyword string) {
+		if isSet {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	add(s.Ref.IsSet, "$ref")
+	add(s.Not.IsSet, "not")
+	add(s.If.IsSet, "if")
+	add(s.If.IsSet && s.Then.IsSet, "then")
+	add(s.If.IsSet && s.Else.IsSet, "else")
+	add(s.Const.IsSet, "const")
+	add(s.Enum.IsSet, "enum")
+	add(s.AllOf.IsSet, "allOf")
+	add(s.AnyOf.IsSet, "anyOf")
+	add(s.OneOf.IsSet, "oneOf")
+	add(s.Type.IsSet, "type")
+	add(s.MultipleOf.IsSet, "multipleOf")
+	add(s.Maximum.IsSet, "maximum")
+	add(s.Minimum.IsSet, "minimum")
+	add(s.ExclusiveMaximum.IsSet, "exclusiveMaximum")
+	add(s.ExclusiveMinimum.IsSet, "exclusiveMinimum")
+	add(s.MaxLength.IsSet, "maxLength")
+	add(s.MinLength.IsSet, "minLength")
+	add(s.Pattern.IsSet, "pattern")
+	add(s.Items.IsSet, "items")
+	add(s.Items.IsSet && !s.Items.IsSingle && s.AdditionalItems.IsSet, "additionalItems")
+	add(s.MaxItems.IsSet, "maxItems")
+	add(s.MinItems.IsSet, "minItems")
+	add(s.UniqueItems.IsSet, "uniqueItems")
+	add(s.Contains.IsSet, "contains")
+	add(s.MaxProperties.IsSet, "maxProperties")
+	add(s.MinProperties.IsSet, "minProperties")
+	add(s.Required.IsSet, "required")
+	add(s.Properties.IsSet, "properties")
+	add(s.PatternProperties.IsSet, "patternProperties")
+	add(s.AdditionalProperties.IsSet, "additionalProperties")
+	add(s.Dependencies.IsSet, "dependencies")
+	add(s.PropertyNames.IsSet, "propertyNames")
+
+	for _, keyword := range s.Keywords {
+		keywords = append(keywords, keyword.Name)
+	}
+
+	return keywords
+}
+
+// coverage holds the counts collected by a Validator.
+type coverage struct {
+	mu     sync.Mutex
+	counts map[coverageKey]coverageCounts
+}
+
+// newCoverage returns the coverage of a new Validator, or nil if coverage is
+// not enabled.
+func newCoverage(enabled bool) *coverage {
+	if !enabled {
+		return nil
+	}
+
+	return &coverage{counts: map[coverageKey]coverageCounts{}}
+}
+
+// coverageKey identifies a schema or keyword. Its URI has no fragment.
+type coverageKey struct {
+	uri     string
+	path    string
+	keyword string
+}
+
+type coverageCounts struct {
+	evaluated int
+	failed    int
+}
+
+// merge adds the counts collected by a vm during one evaluation.
+func (c *coverage) merge(r *coverageRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, counts := range r.counts {
+		total := c.counts[key]
+		total.evaluated += counts.evaluated
+		total.failed += counts.failed
+		c.counts[key] = total
+	}
+}
+
+// coverageRecorder collects counts as the vm evaluates an instance.
+type coverageRecorder struct {
+	counts map[coverageKey]coverageCounts
+
+	// frames is a stack of the schemas and keywords currently being evaluated
+	frames []coverageFrame
+}
+
+type coverageFrame struct {
+	key coverageKey
+
+	// errors is the number of errors the vm had when the frame was entered
+	errors int
+
+	// failed is whether errors were reported while the frame was entered before
+	// being suspended
+	failed bool
+}
+
+// coverageEnter records the start of the evaluation of the current schema, if
+// keyword is empty, or of the given keyword of the current schema.
+func (vm *vm) coverageEnter(keyword string) {
+	if vm.coverage == nil {
+		return
+	}
+
+	uri := vm.schemaID()
+	uri.Fragment = ""
+
+	schemaPath := vm.schemaPath()
+	if keyword != "" {
+		schemaPath.Tokens = append(schemaPath.Tokens, keyword)
+	}
+
+	vm.coverage.frames = append(vm.coverage.frames, coverageFrame{
+		key:    coverageKey{uri: uri.String(), path: schemaPath.String(), keyword: keyword},
+		errors: vm.errors.count,
+	})
+}
+
+// coverageExit records the end of the most recently entered evaluation, which
+// passed if no errors were reported since it was entered.
+func (vm *vm) coverageExit() {
+	if vm.coverage == nil {
+		return
+	}
+
+	frame := vm.coverage.frames[len(vm.coverage.frames)-1]
+	vm.coverage.frames = vm.coverage.frames[:len(vm.coverage.frames)-1]
+
+	counts := vm.coverage.counts[frame.key]
+	counts.evaluated++
+	if frame.failed || vm.errors.count != frame.errors {
+		counts.failed++
+	}
+
+	vm.coverage.counts[frame.key] = counts
+}
+
+// coverageSuspend leaves the most recently entered evaluation without
+// recording its end. See traceSuspend.
+func (vm *vm) coverageSuspend() coverageFrame {
+	if vm.coverage == nil {
+		return coverageFrame{}
+	}
+
+	frame := vm.coverage.frames[len(vm.coverage.frames)-1]
+	frame.failed = frame.failed || vm.errors.count != frame.errors
+	vm.coverage.frames = vm.coverage.frames[:len(vm.coverage.frames)-1]
+
+	return frame
+}
+
+// coverageResume re-enters an evaluation left by coverageSuspend.
+func (vm *vm) coverageResume(frame coverageFrame) {
+	if vm.coverage == nil {
+		return
+	}
+
+	frame.errors = vm.errors.count
+	vm.coverage.frames = append(vm.coverage.frames, frame)
+}
+
+// coverageAbort records the end of every evaluation not yet exited, as
+// happens when evaluation quits early. Such evaluations are all considered to
+// have failed.
+func (vm *vm) coverageAbort() {
+	if vm.coverage == nil {
+		return
+	}
+
+	for _, frame := range vm.coverage.frames {
+		counts := vm.coverage.counts[frame.key]
+		counts.evaluated++
+		counts.failed++
+		vm.coverage.counts[frame.key] = counts
+	}
+
+	vm.coverage.frames = vm.coverage.frames[:0]
+}
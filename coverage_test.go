@@ -0,0 +1,191 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatorCoverage(t *testing.T) {
+	schema := mustDecode(t, `{
+		"properties": {
+			"kind": {
+				"oneOf": [
+					{"const": "a"},
+					{"const": "b"}
+				]
+			},
+			"size": {
+				"if": {"type": "integer"},
+				"then": {"minimum": 0},
+				"else": {"type": "string"}
+			}
+		},
+		"dependencies": {
+			"size": ["kind"],
+			"kind": {},
+			"other": {}
+		}
+	}`)
+
+	validator, err := NewValidatorWithConfig([]interface{}{schema}, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		Coverage:      true,
+	})
+	assert.NoError(t, err)
+
+	for _, instance := range []string{`{"kind": "a", "size": 1}`, `{"kind": "a", "size": -1}`} {
+		_, err := validator.Validate(mustDecode(t, instance))
+		assert.NoError(t, err)
+	}
+
+	// IsValid is not counted
+	ok, err := validator.IsValid(mustDecode(t, `{"kind": "b", "size": "x"}`))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	counts := map[string][3]int{}
+	for _, entry := range validator.Coverage().Entries {
+		counts["#"+entry.Location.Path.String()] = [3]int{entry.Evaluated, entry.Passed, entry.Failed}
+	}
+
+	assert.Equal(t, [3]int{2, 1, 1}, counts["#"])
+	assert.Equal(t, [3]int{2, 2, 0}, counts["#/properties/kind/oneOf/0"])
+	assert.Equal(t, [3]int{2, 0, 2}, counts["#/properties/kind/oneOf/1"])
+	assert.Equal(t, [3]int{2, 2, 0}, counts["#/properties/size/if"])
+	assert.Equal(t, [3]int{2, 1, 1}, counts["#/properties/size/then"])
+	assert.Equal(t, [3]int{0, 0, 0}, counts["#/properties/size/else"])
+	assert.Equal(t, [3]int{2, 2, 0}, counts["#/dependencies/size"])
+	assert.Equal(t, [3]int{2, 2, 0}, counts["#/dependencies/kind"])
+	assert.Equal(t, [3]int{0, 0, 0}, counts["#/dependencies/other"])
+
+	unexercised := []string{}
+	for _, entry := range validator.Coverage().Unexercised() {
+		unexercised = append(unexercised, "#"+entry.Location.Path.String())
+	}
+
+	assert.Equal(t, []string{"#/dependencies/other", "#/properties/kind/oneOf/1", "#/properties/size/else"}, unexercised)
+
+	validator.ResetCoverage()
+	for _, entry := range validator.Coverage().Entries {
+		assert.Equal(t, 0, entry.Evaluated)
+	}
+
+	assert.Len(t, validator.Coverage().Unexercised(), 7)
+}
+
+func TestValidatorCoverageRefs(t *testing.T) {
+	schemas := []interface{}{
+		mustDecode(t, `{
+			"$id": "http://example.com/root",
+			"items": {"$ref": "http://example.com/item#/definitions/item"}
+		}`),
+		mustDecode(t, `{
+			"$id": "http://example.com/item",
+			"definitions": {
+				"item": {"anyOf": [{"type": "string"}, {"type": "null"}]}
+			}
+		}`),
+	}
+
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		Coverage:      true,
+	})
+	assert.NoError(t, err)
+
+	uri, err := url.Parse("http://example.com/root")
+	assert.NoError(t, err)
+
+	_, err = validator.ValidateURI(*uri, mustDecode(t, `["a", "b", "c"]`))
+	assert.NoError(t, err)
+
+	counts := map[string]int{}
+	for _, entry := range validator.Coverage().Entries {
+		location := entry.Location.URI.String() + "#" + entry.Location.Path.String()
+		if entry.Keyword != "" {
+			location += " " + entry.Keyword
+		}
+
+		counts[location] = entry.Evaluated
+	}
+
+	assert.Equal(t, map[string]int{
+		"http://example.com/root#":                                    1,
+		"http://example.com/root#/items items":                        1,
+		"http://example.com/root#/items":                              3,
+		"http://example.com/root#/items/$ref $ref":                    3,
+		"http://example.com/item#":                                    0,
+		"http://example.com/item#/definitions/item":                   3,
+		"http://example.com/item#/definitions/item/anyOf anyOf":       3,
+		"http://example.com/item#/definitions/item/anyOf/0":           3,
+		"http://example.com/item#/definitions/item/anyOf/0/type type": 3,
+		"http://example.com/item#/definitions/item/anyOf/1":           0,
+		"http://example.com/item#/definitions/item/anyOf/1/type type": 0,
+	}, counts)
+}
+
+func TestValidatorCoverageDisabled(t *testing.T) {
+	validator, err := NewValidator([]interface{}{mustDecode(t, `{"oneOf": [true, false]}`)})
+	assert.NoError(t, err)
+
+	_, err = validator.Validate(nil)
+	assert.NoError(t, err)
+
+	for _, entry := range validator.Coverage().Entries {
+		assert.Equal(t, 0, entry.Evaluated)
+	}
+}
+
+func TestCoverageReportWrite(t *testing.T) {
+	validator, err := NewValidatorWithConfig([]interface{}{mustDecode(t, `{
+		"if": {"type": "string"},
+		"then": {"minLength": 1},
+		"else": {"type": "null"}
+	}`)}, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		Coverage:      true,
+	})
+	assert.NoError(t, err)
+
+	_, err = validator.Validate("abc")
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, validator.Coverage().WriteJSON(&out))
+
+	var entries []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+	assert.Contains(t, entries, map[string]interface{}{
+		"uri":       "",
+		"path":      "/else",
+		"keyword":   "else",
+		"branch":    true,
+		"exercised": false,
+		"evaluated": 0.0,
+		"passed":    0.0,
+		"failed":    0.0,
+	})
+	assert.Contains(t, entries, map[string]interface{}{
+		"uri":       "",
+		"path":      "/then",
+		"keyword":   "then",
+		"branch":    true,
+		"exercised": true,
+		"evaluated": 1.0,
+		"passed":    1.0,
+		"failed":    0.0,
+	})
+
+	out.Reset()
+	assert.NoError(t, validator.Coverage().WriteHTML(&out))
+
+	html := out.String()
+	assert.Contains(t, html, "1 of 2 branches exercised.")
+	assert.Contains(t, html, `<tr class="unexercised"><td>#/else</td><td>else</td>`)
+	assert.Contains(t, html, `<tr class="unevaluated"><td>#/else/type</td><td>type</td>`)
+	assert.Contains(t, html, `<tr class=""><td>#/then</td><td>then</td>`)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+
+	"github.com/ucarion/json-pointer"
 )
 
 // ErrStackOverflow indicates that the evaluator overflowed its internal stack
@@ -18,6 +20,14 @@ var ErrInvalidSchema = errors.New("invalid schema")
 // validator.
 var ErrNoSuchSchema = errors.New("no schema exists with the given URI")
 
+// ErrNoSuchOperation indicates that no operation with the given ID exists in an
+// OpenAPI document.
+var ErrNoSuchOperation = errors.New("no operation exists with the given ID")
+
+// ErrNoSample indicates that a Sampler could not generate a valid instance of
+// a schema.
+var ErrNoSample = errors.New("could not generate an instance of the schema")
+
 // ErrMissingURIs indicates that some schemas were referred to, but were not
 // known to the Validator.
 type ErrMissingURIs struct {
@@ -29,3 +39,44 @@ type ErrMissingURIs struct {
 func (e ErrMissingURIs) Error() string {
 	return fmt.Sprintf("missing schemas with URIs: %v", e.URIs)
 }
+
+// SchemaError is a single problem found in a schema given to a Validator.
+type SchemaError struct {
+	// The URI of the schema containing the problem.
+	URI url.URL
+
+	// A JSON Pointer to the part of the schema which is invalid.
+	Path jsonpointer.Ptr
+
+	// A human-readable description of the problem.
+	Message string
+
+	// Where the invalid part of the schema appears in its source. It is only
+	// known for schemas given to NewValidatorFromJSON.
+	Position Position
+}
+
+// Error fulfills the error interface.
+func (e SchemaError) Error() string {
+	uri := e.URI
+	uri.Fragment = ""
+
+	if e.Position.IsValid() {
+		return fmt.Sprintf("%s: %s#%s: %s", e.Position.String(), uri.String(), e.Path.String(), e.Message)
+	}
+
+	return fmt.Sprintf("%s#%s: %s", uri.String(), e.Path.String(), e.Message)
+}
+
+// ErrSchemaErrors indicates that some schemas did not pass the checks enabled
+// by the ValidateSchemas or StrictKeywords options of ValidatorConfig.
+type ErrSchemaErrors struct {
+	// Errors is a list of every problem found, in the order in which the
+	// schemas were given.
+	Errors []SchemaError
+}
+
+// Error fulfills the error interface.
+func (e ErrSchemaErrors) Error() string {
+	return fmt.Sprintf("invalid schemas: %v", e.Errors)
+}
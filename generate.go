@@ -0,0 +1,894 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// GenerateConfig contains configuration for GenerateGo.
+type GenerateConfig struct {
+	// Package is the name of the package the generated code belongs to.
+	Package string
+
+	// Funcs describes the functions to generate.
+	Funcs []GenerateFunc
+}
+
+// GenerateFunc describes a function to be generated by GenerateGo.
+type GenerateFunc struct {
+	// Name is the name of the function.
+	Name string
+
+	// Validator holds the schemas the function evaluates instances against.
+	Validator *Validator
+
+	// URI identifies the schema in Validator to evaluate instances against. The
+	// empty URI identifies the default schema.
+	URI url.URL
+
+	// Type, if set, is the name of a Go type in the generated package, such as
+	// one generated by GenerateGoTypes, which the function takes instead of
+	// interface{}. Values of the type are evaluated as encoding/json encodes
+	// them.
+	Type string
+}
+
+// GenerateGo writes the source code of a Go file holding plain functions which
+// evaluate instances against schemas, without interpreting the schemas at run
+// time. Each function has the signature:
+//
+//	func(instance interface{}) (jsonschema.ValidationResult, error)
+//
+// and returns the same result as the ValidateURI method of its Validator. A
+// function with a Type instead has the signature:
+//
+//	func(value T) (jsonschema.ValidationResult, error)
+//
+// and returns the same result as ValidateURI does for the value encoded with
+// encoding/json and decoded again. Errors from encoding/json are returned as
+// they are.
+//
+// Only the MaxStackDepth option of the Validator's config is respected;
+// generated functions always return every error, and never record a trace.
+//
+// Schemas using custom keywords cannot be compiled to Go, and cause an error to
+// be returned.
+//
+// The generated code declares unexported identifiers beginning with
+// "jsonschema", so each package may only hold one generated file.
+func GenerateGo(w io.Writer, config GenerateConfig) error {
+	g := codegen{
+		validators:  map[*Validator]int{},
+		methodNames: map[codegenSchema]string{},
+		vars:        map[string]string{},
+		imports:     map[string]bool{},
+	}
+
+	for _, fn := range config.Funcs {
+		if err := g.genFunc(fn); err != nil {
+			return err
+		}
+	}
+
+	for len(g.pending) > 0 {
+		s := g.pending[0]
+		g.pending = g.pending[1:]
+
+		if err := g.genSchema(s); err != nil {
+			return err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by jsonschema. DO NOT EDIT.\n\npackage %s\n\n", config.Package)
+
+	imports := []string{"math", "net/url", "sort"}
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+
+	out.WriteString("import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	out.WriteString("\n\t\"github.com/json-schema-spec/json-schema-go\"\n")
+	out.WriteString("\t\"github.com/ucarion/json-pointer\"\n)\n\n")
+
+	out.Write(g.funcs.Bytes())
+	out.Write(g.methods.Bytes())
+
+	if len(g.varDecls) > 0 {
+		out.WriteString("var (\n")
+		for _, decl := range g.varDecls {
+			out.WriteString(decl)
+		}
+		out.WriteString(")\n\n")
+	}
+
+	out.WriteString(codegenRuntime)
+	if g.imports["encoding/json"] {
+		out.WriteString(codegenTypedRuntime)
+	}
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+// codegen holds the state of GenerateGo.
+type codegen struct {
+	// funcs holds the generated exported functions
+	funcs bytes.Buffer
+
+	// methods holds the generated methods, one for each schema
+	methods bytes.Buffer
+
+	// validators numbers each Validator used, to keep method names distinct
+	validators map[*Validator]int
+
+	// pending holds schemas which are used, but whose methods have not been
+	// generated
+	pending []codegenSchema
+
+	// methods holds the names of the methods of schemas which have been or will
+	// be generated. Methods are numbered in the order they are first used, as
+	// the indices of schemas in the arena vary from run to run.
+	methodNames map[codegenSchema]string
+
+	// vars maps the values of package-level variables to their names
+	vars map[string]string
+
+	// varDecls holds the declarations of package-level variables
+	varDecls []string
+
+	// imports holds the packages used, besides those always used
+	imports map[string]bool
+}
+
+type codegenSchema struct {
+	validator *Validator
+	index     int
+}
+
+func (g *codegen) genFunc(fn GenerateFunc) error {
+	index, ok := fn.Validator.registry.schemas[fn.URI]
+	if !ok {
+		return ErrNoSuchSchema
+	}
+
+	fragPtr := jsonpointer.Ptr{Tokens: []string{}}
+	if fn.URI.Fragment != "" {
+		ptr, err := jsonpointer.New(fn.URI.Fragment)
+		if err != nil {
+			return err
+		}
+
+		fragPtr = ptr
+	}
+
+	subject := "an instance"
+	if fn.Type != "" {
+		subject = "the JSON encoding of a value"
+	}
+
+	if fn.URI == (url.URL{}) {
+		fmt.Fprintf(&g.funcs, "// %s evaluates %s against the default schema.\n", fn.Name, subject)
+	} else {
+		fmt.Fprintf(&g.funcs, "// %s evaluates %s against the schema %s.\n", fn.Name, subject, fn.URI.String())
+	}
+
+	if fn.Type != "" {
+		g.imports["encoding/json"] = true
+		fmt.Fprintf(&g.funcs, "func %s(value %s) (jsonschema.ValidationResult, error) {\n", fn.Name, fn.Type)
+		g.funcs.WriteString("instance, err := jsonschemaInstance(value)\nif err != nil {\nreturn jsonschema.ValidationResult{}, err\n}\n\n")
+	} else {
+		fmt.Fprintf(&g.funcs, "func %s(instance interface{}) (jsonschema.ValidationResult, error) {\n", fn.Name)
+	}
+
+	// as in ValidateURI, errors have the URI of the document, without the
+	// fragment
+	id := fn.URI
+	id.Fragment = ""
+
+	fmt.Fprintf(&g.funcs, "s := jsonschemaState{uri: %s, schema: %s, maxDepth: %d}\n",
+		g.uriVar(id), g.tokensVar(fragPtr.Tokens), fn.Validator.maxStackDepth)
+	fmt.Fprintf(&g.funcs, "return s.run(s.%s, instance)\n}\n\n", g.method(fn.Validator, index))
+
+	return nil
+}
+
+// method returns the name of the method for a schema, and makes sure it will be
+// generated.
+func (g *codegen) method(v *Validator, index int) string {
+	n, ok := g.validators[v]
+	if !ok {
+		n = len(g.validators)
+		g.validators[v] = n
+	}
+
+	key := codegenSchema{validator: v, index: index}
+	if name, ok := g.methodNames[key]; ok {
+		return name
+	}
+
+	name := fmt.Sprintf("v%dschema%d", n, len(g.methodNames))
+	g.methodNames[key] = name
+	g.pending = append(g.pending, key)
+	return name
+}
+
+// variable declares a package-level variable with the given value, and returns
+// its name. Variables with the same value are only declared once.
+func (g *codegen) variable(prefix, value string) string {
+	if name, ok := g.vars[value]; ok {
+		return name
+	}
+
+	name := fmt.Sprintf("jsonschema%s%d", prefix, len(g.varDecls))
+	g.vars[value] = name
+	g.varDecls = append(g.varDecls, fmt.Sprintf("%s = %s\n", name, value))
+	return name
+}
+
+func (g *codegen) uriVar(uri url.URL) string {
+	return g.variable("URI", goURILiteral(uri))
+}
+
+func (g *codegen) tokensVar(tokens []string) string {
+	return g.variable("Tokens", goStringsLiteral(tokens))
+}
+
+func (g *codegen) valueVar(value interface{}) string {
+	return g.variable("Value", goValueLiteral(value))
+}
+
+func (g *codegen) patternVar(pattern string) string {
+	g.imports["regexp"] = true
+	return g.variable("Pattern", fmt.Sprintf("regexp.MustCompile(%q)", pattern))
+}
+
+func (g *codegen) genSchema(key codegenSchema) error {
+	s := key.validator.registry.GetIndex(key.index)
+	if len(s.Keywords) > 0 {
+		return fmt.Errorf("jsonschema: cannot generate code for custom keyword %q", s.Keywords[0].Name)
+	}
+
+	b := &g.methods
+	fmt.Fprintf(b, "func (s *jsonschemaState) %s(instance interface{}) error {\n", g.method(key.validator, key.index))
+
+	// instructions which apply to every kind of instance come first in every
+	// program, followed by those specific to the kind
+	common := []instruction{}
+	for _, inst := range s.Program.code[kindNull] {
+		if !codegenSpecific[inst.op] {
+			common = append(common, inst)
+		}
+	}
+
+	for _, inst := range common {
+		g.genInstruction(key.validator, s, inst)
+	}
+
+	b.WriteString("switch val := instance.(type) {\n")
+	for kind := instanceKind(0); kind < kindCount; kind++ {
+		fmt.Fprintf(b, "case %s:\n", codegenKindTypes[kind])
+
+		for _, inst := range s.Program.code[kind] {
+			if codegenSpecific[inst.op] {
+				g.genInstruction(key.validator, s, inst)
+			}
+		}
+	}
+
+	// val is used here so that it is used in every method, even those of
+	// schemas with no keywords specific to a kind of instance
+	b.WriteString("default:\n_ = val\npanic(\"unexpected non-json input\")\n}\n\nreturn nil\n}\n\n")
+	return nil
+}
+
+// codegenSpecific holds the opcodes which only apply to one kind of instance.
+var codegenSpecific = map[opcode]bool{
+	opMultipleOf: true, opMaximum: true, opMinimum: true,
+	opExclusiveMaximum: true, opExclusiveMinimum: true, opMaxLength: true,
+	opMinLength: true, opPattern: true, opMaxItems: true, opMinItems: true,
+	opUniqueItems: true, opContains: true, opItems: true, opMaxProperties: true,
+	opMinProperties: true, opRequired: true, opProperties: true,
+	opDependencies: true, opPropertyNames: true,
+}
+
+var codegenKindTypes = [kindCount]string{
+	kindNull:    "nil",
+	kindBoolean: "bool",
+	kindNumber:  "float64",
+	kindString:  "string",
+	kindArray:   "[]interface{}",
+	kindObject:  "map[string]interface{}",
+}
+
+var codegenTypeBits = map[jsonType]int{
+	jsonTypeNull:    1 << 0,
+	jsonTypeBoolean: 1 << 1,
+	jsonTypeNumber:  1 << 2,
+	jsonTypeInteger: 1 << 3,
+	jsonTypeString:  1 << 4,
+	jsonTypeArray:   1 << 5,
+	jsonTypeObject:  1 << 6,
+}
+
+func (g *codegen) genInstruction(v *Validator, s *schema, inst instruction) {
+	b := &g.methods
+
+	// call emits a call to the method of a subschema
+	call := func(index int, instance string) {
+		fmt.Fprintf(b, "if err := s.%s(%s); err != nil {\nreturn err\n}\n", g.method(v, index), instance)
+	}
+
+	// pseudo emits a call to the method of a subschema whose errors are stored
+	// in errs, rather than reported
+	pseudo := func(index int, instance string) {
+		b.WriteString("prevErrors := s.errors\ns.errors = []jsonschema.ValidationError{}\n")
+		call(index, instance)
+		b.WriteString("errs := s.errors\ns.errors = prevErrors\n")
+	}
+
+	check := func(keyword, cond string) {
+		fmt.Fprintf(b, "if %s {\ns.reportAt(%q)\n}\n", cond, keyword)
+	}
+
+	float := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	switch inst.op {
+	case opFalse:
+		b.WriteString("s.report(nil, nil)\n")
+	case opRef:
+		fmt.Fprintf(b, "if s.depth == s.maxDepth {\nreturn jsonschema.ErrStackOverflow\n}\n")
+		fmt.Fprintf(b, "{\nprevURI, prevSchema := s.uri, s.schema\n")
+		fmt.Fprintf(b, "s.uri, s.schema = %s, %s\n", g.uriVar(s.Ref.BaseURI), g.tokensVar(s.Ref.Ptr.Tokens))
+		b.WriteString("s.depth++\n")
+		call(s.Ref.Schema, "instance")
+		b.WriteString("s.depth--\ns.uri, s.schema = prevURI, prevSchema\n}\n")
+	case opNot:
+		b.WriteString("{\ns.push(\"not\")\n")
+		pseudo(s.Not.Schema, "instance")
+		b.WriteString("if len(errs) == 0 {\ns.report(nil, nil)\n}\ns.pop()\n}\n")
+	case opIf:
+		b.WriteString("{\ns.push(\"if\")\n")
+		pseudo(s.If.Schema, "instance")
+		b.WriteString("s.pop()\n")
+
+		if s.Then.IsSet {
+			b.WriteString("if len(errs) == 0 {\ns.push(\"then\")\n")
+			call(s.Then.Schema, "instance")
+			b.WriteString("s.pop()\n}\n")
+		}
+
+		if s.Else.IsSet {
+			b.WriteString("if len(errs) != 0 {\ns.push(\"else\")\n")
+			call(s.Else.Schema, "instance")
+			b.WriteString("s.pop()\n}\n")
+		}
+
+		if !s.Then.IsSet && !s.Else.IsSet {
+			b.WriteString("_ = errs\n")
+		}
+
+		b.WriteString("}\n")
+	case opConst:
+		check("const", fmt.Sprintf("!jsonschemaEqual(instance, %s)", g.valueVar(s.Const.Value)))
+	case opEnum:
+		check("enum", fmt.Sprintf("!jsonschemaEnum(instance, %s)", g.valueVar(s.Enum.Values)))
+	case opAllOf:
+		b.WriteString("s.push(\"allOf\")\n")
+		for i, index := range s.AllOf.Schemas {
+			fmt.Fprintf(b, "s.push(%q)\n", strconv.Itoa(i))
+			call(index, "instance")
+			b.WriteString("s.pop()\n")
+		}
+		b.WriteString("s.pop()\n")
+	case opAnyOf:
+		b.WriteString("{\nanyOfOk := false\nanyOfCauses := []jsonschema.ValidationError(nil)\ns.push(\"anyOf\")\n")
+		for i, index := range s.AnyOf.Schemas {
+			fmt.Fprintf(b, "if !anyOfOk {\ns.push(%q)\n", strconv.Itoa(i))
+			pseudo(index, "instance")
+			b.WriteString("s.pop()\nif len(errs) == 0 {\nanyOfOk = true\n} else {\nanyOfCauses = append(anyOfCauses, errs...)\n}\n}\n")
+		}
+		b.WriteString("s.pop()\nif !anyOfOk {\ns.push(\"anyOf\")\ns.report(anyOfCauses, nil)\ns.pop()\n}\n}\n")
+	case opOneOf:
+		// the alternative picked by a discriminator property is evaluated alone,
+		// as by oneOfDispatch.alternative
+		if d := s.Program.oneOf; d != nil {
+			b.WriteString("oneOfDispatched := false\nif object, ok := instance.(map[string]interface{}); ok {\n")
+			fmt.Fprintf(b, "if tag, ok := object[%q].(string); ok {\nswitch tag {\n", d.property)
+			for _, value := range sortedKeysInt(d.alternatives) {
+				i := d.alternatives[value]
+				fmt.Fprintf(b, "case %q:\noneOfDispatched = true\ns.push(\"oneOf\")\ns.push(%q)\n", value, strconv.Itoa(i))
+				call(s.OneOf.Schemas[i], "instance")
+				b.WriteString("s.pop()\ns.pop()\n")
+			}
+			b.WriteString("}\n}\n}\n")
+			b.WriteString("if !oneOfDispatched ")
+		}
+
+		b.WriteString("{\noneOfCauses := []jsonschema.ValidationError(nil)\noneOfMatches := []int(nil)\ns.push(\"oneOf\")\n")
+		for i, index := range s.OneOf.Schemas {
+			fmt.Fprintf(b, "{\ns.push(%q)\n", strconv.Itoa(i))
+			pseudo(index, "instance")
+			fmt.Fprintf(b, "s.pop()\nif len(errs) != 0 {\noneOfCauses = append(oneOfCauses, errs...)\n} else {\noneOfMatches = append(oneOfMatches, %d)\n}\n}\n", i)
+		}
+		b.WriteString("s.pop()\nif len(oneOfMatches) != 1 {\nif len(oneOfMatches) > 1 {\noneOfCauses = nil\n} else {\noneOfMatches = nil\n}\n")
+		b.WriteString("s.push(\"oneOf\")\ns.report(oneOfCauses, oneOfMatches)\ns.pop()\n}\n}\n")
+	case opType:
+		mask := 0
+		for _, t := range s.Type.Types {
+			mask |= codegenTypeBits[t]
+		}
+
+		check("type", fmt.Sprintf("jsonschemaTypes(instance)&%d == 0", mask))
+	case opMultipleOf:
+		g.imports["math"] = true
+		check("multipleOf", fmt.Sprintf("math.Abs(math.Mod(val, %s)) > %s", float(s.MultipleOf.Value), float(epsilon)))
+	case opMaximum:
+		check("maximum", fmt.Sprintf("val > %s", float(s.Maximum.Value)))
+	case opMinimum:
+		check("minimum", fmt.Sprintf("val < %s", float(s.Minimum.Value)))
+	case opExclusiveMaximum:
+		check("exclusiveMaximum", fmt.Sprintf("val > %s", float(s.ExclusiveMaximum.Value-epsilon)))
+	case opExclusiveMinimum:
+		check("exclusiveMinimum", fmt.Sprintf("val < %s", float(s.ExclusiveMinimum.Value+epsilon)))
+	case opMaxLength:
+		g.imports["unicode/utf8"] = true
+		check("maxLength", fmt.Sprintf("utf8.RuneCountInString(val) > %d", s.MaxLength.Value))
+	case opMinLength:
+		g.imports["unicode/utf8"] = true
+		check("minLength", fmt.Sprintf("utf8.RuneCountInString(val) < %d", s.MinLength.Value))
+	case opPattern:
+		check("pattern", fmt.Sprintf("!%s.MatchString(val)", g.patternVar(s.Pattern.Value.String())))
+	case opMaxItems:
+		check("maxItems", fmt.Sprintf("len(val) > %d", s.MaxItems.Value))
+	case opMinItems:
+		check("minItems", fmt.Sprintf("len(val) < %d", s.MinItems.Value))
+	case opUniqueItems:
+		check("uniqueItems", "!jsonschemaUnique(val)")
+	case opContains:
+		g.imports["strconv"] = true
+		b.WriteString("{\ncontainsOk := false\ncontainsCauses := []jsonschema.ValidationError(nil)\ns.push(\"contains\")\n")
+		b.WriteString("for i, elem := range val {\ns.pushInstance(strconv.Itoa(i))\n")
+		pseudo(s.Contains.Schema, "elem")
+		b.WriteString("s.popInstance()\nif len(errs) == 0 {\ncontainsOk = true\nbreak\n}\ncontainsCauses = append(containsCauses, errs...)\n}\n")
+		b.WriteString("s.pop()\nif !containsOk {\ns.push(\"contains\")\ns.report(containsCauses, nil)\ns.pop()\n}\n}\n")
+	case opItems:
+		g.imports["strconv"] = true
+		b.WriteString("s.push(\"items\")\n")
+		if s.Items.IsSingle {
+			b.WriteString("for i, elem := range val {\ns.pushInstance(strconv.Itoa(i))\n")
+			call(s.Items.Schemas[0], "elem")
+			b.WriteString("s.popInstance()\n}\n")
+		} else {
+			for i, index := range s.Items.Schemas {
+				fmt.Fprintf(b, "if len(val) > %d {\ns.pushInstance(%q)\ns.push(%q)\n", i, strconv.Itoa(i), strconv.Itoa(i))
+				call(index, fmt.Sprintf("val[%d]", i))
+				b.WriteString("s.popInstance()\ns.pop()\n}\n")
+			}
+		}
+		b.WriteString("s.pop()\n")
+
+		if !s.Items.IsSingle && s.AdditionalItems.IsSet {
+			fmt.Fprintf(b, "s.push(\"additionalItems\")\nfor i := %d; i < len(val); i++ {\ns.pushInstance(strconv.Itoa(i))\n", len(s.Items.Schemas))
+			call(s.AdditionalItems.Schema, "val[i]")
+			b.WriteString("s.popInstance()\n}\ns.pop()\n")
+		}
+	case opMaxProperties:
+		check("maxProperties", fmt.Sprintf("len(val) > %d", s.MaxProperties.Value))
+	case opMinProperties:
+		check("minProperties", fmt.Sprintf("len(val) < %d", s.MinProperties.Value))
+	case opRequired:
+		g.imports["strconv"] = true
+		fmt.Fprintf(b, "s.push(\"required\")\nfor i, property := range %s {\n", g.tokensVar(s.Required.Properties))
+		b.WriteString("if _, ok := val[property]; !ok {\ns.push(strconv.Itoa(i))\ns.report(nil, nil)\ns.pop()\n}\n}\ns.pop()\n")
+	case opProperties:
+		g.genProperties(v, s)
+	case opDependencies:
+		g.imports["strconv"] = true
+		b.WriteString("s.push(\"dependencies\")\n")
+		for _, key := range sortedDependencies(s.Dependencies.Deps) {
+			dep := s.Dependencies.Deps[key]
+			if dep.IsSchema {
+				fmt.Fprintf(b, "if value, ok := val[%q]; ok {\ns.push(%q)\n", key, key)
+				fmt.Fprintf(b, "s.pushInstance(%q)\n", key)
+				call(dep.Schema, "value")
+				b.WriteString("s.popInstance()\n")
+			} else {
+				fmt.Fprintf(b, "if _, ok := val[%q]; ok {\ns.push(%q)\n", key, key)
+				fmt.Fprintf(b, "for i, property := range %s {\n", g.tokensVar(dep.Properties))
+				b.WriteString("if _, ok := val[property]; !ok {\ns.push(strconv.Itoa(i))\ns.report(nil, nil)\ns.pop()\n}\n}\n")
+			}
+			b.WriteString("s.pop()\n}\n")
+		}
+		b.WriteString("s.pop()\n")
+	case opPropertyNames:
+		b.WriteString("s.push(\"propertyNames\")\nfor _, key := range jsonschemaKeys(val) {\ns.pushInstance(key)\n")
+		call(s.PropertyNames.Schema, "key")
+		b.WriteString("s.popInstance()\n}\ns.pop()\n")
+	}
+}
+
+// genProperties generates code for "properties", "patternProperties" and
+// "additionalProperties".
+func (g *codegen) genProperties(v *Validator, s *schema) {
+	b := &g.methods
+
+	patterns := sortedPatternProperties(s.PatternProperties.Schemas)
+	hasAdditional := s.AdditionalProperties.IsSet
+
+	b.WriteString("for _, key := range jsonschemaKeys(val) {\nvalue := val[key]\n")
+	if hasAdditional {
+		b.WriteString("isAdditional := true\n")
+	}
+
+	call := func(index int) {
+		fmt.Fprintf(b, "if err := s.%s(value); err != nil {\nreturn err\n}\n", g.method(v, index))
+	}
+
+	if s.Properties.IsSet && len(s.Properties.Schemas) > 0 {
+		b.WriteString("switch key {\n")
+		for _, key := range sortedKeysInt(s.Properties.Schemas) {
+			fmt.Fprintf(b, "case %q:\n", key)
+			if hasAdditional {
+				b.WriteString("isAdditional = false\n")
+			}
+
+			fmt.Fprintf(b, "s.push(\"properties\")\ns.push(%q)\ns.pushInstance(key)\n", key)
+			call(s.Properties.Schemas[key])
+			b.WriteString("s.popInstance()\ns.pop()\ns.pop()\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, pattern := range patterns {
+		fmt.Fprintf(b, "if %s.MatchString(key) {\n", g.patternVar(pattern.String()))
+		if hasAdditional {
+			b.WriteString("isAdditional = false\n")
+		}
+
+		fmt.Fprintf(b, "s.push(\"patternProperties\")\ns.push(%q)\ns.pushInstance(key)\n", pattern.String())
+		call(s.PatternProperties.Schemas[pattern])
+		b.WriteString("s.popInstance()\ns.pop()\ns.pop()\n}\n")
+	}
+
+	if hasAdditional {
+		b.WriteString("if isAdditional {\ns.push(\"additionalProperties\")\ns.pushInstance(key)\n")
+		call(s.AdditionalProperties.Schema)
+		b.WriteString("s.popInstance()\ns.pop()\n}\n")
+	}
+
+	b.WriteString("_, _ = key, value\n}\n")
+}
+
+// sortedPatternProperties returns the patterns of "patternProperties", sorted
+// by their source.
+func sortedPatternProperties(schemas map[*regexp.Regexp]int) []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(schemas))
+	for pattern := range schemas {
+		patterns = append(patterns, pattern)
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		return patterns[i].String() < patterns[j].String()
+	})
+
+	return patterns
+}
+
+func sortedDependencies(deps map[string]schemaDependency) []string {
+	keys := make([]string, 0, len(deps))
+	for key := range deps {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeysInt(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// goURILiteral formats a URI as a Go composite literal, such that the value it
+// evaluates to is identical to the URI.
+func goURILiteral(uri url.URL) string {
+	var b bytes.Buffer
+	b.WriteString("url.URL{")
+
+	value := reflect.ValueOf(uri)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		switch f := value.Field(i); f.Kind() {
+		case reflect.String:
+			if f.String() != "" {
+				fmt.Fprintf(&b, "%s: %q, ", field.Name, f.String())
+			}
+		case reflect.Bool:
+			if f.Bool() {
+				fmt.Fprintf(&b, "%s: true, ", field.Name)
+			}
+		}
+	}
+
+	if uri.User != nil {
+		if password, ok := uri.User.Password(); ok {
+			fmt.Fprintf(&b, "User: url.UserPassword(%q, %q), ", uri.User.Username(), password)
+		} else {
+			fmt.Fprintf(&b, "User: url.User(%q), ", uri.User.Username())
+		}
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+func goStringsLiteral(strs []string) string {
+	var b bytes.Buffer
+	b.WriteString("[]string{")
+	for i, str := range strs {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(strconv.Quote(str))
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+// goValueLiteral formats a JSON value as a Go expression.
+func goValueLiteral(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(value)
+	case string:
+		return strconv.Quote(value)
+	case []interface{}:
+		var b bytes.Buffer
+		b.WriteString("[]interface{}{")
+		for i, elem := range value {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(goValueLiteral(elem))
+		}
+
+		b.WriteString("}")
+		return b.String()
+	case map[string]interface{}:
+		var b bytes.Buffer
+		b.WriteString("map[string]interface{}{")
+		for i, key := range sortedKeys(value) {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(&b, "%q: %s", key, goValueLiteral(value[key]))
+		}
+
+		b.WriteString("}")
+		return b.String()
+	}
+
+	if number, ok := jsonNumber(value); ok {
+		return fmt.Sprintf("float64(%s)", strconv.FormatFloat(number, 'g', -1, 64))
+	}
+
+	panic("unexpected non-json input")
+}
+
+// codegenTypedRuntime is included in generated files with functions taking a
+// Type.
+const codegenTypedRuntime = `
+// jsonschemaInstance converts a value into the instance its JSON encoding
+// decodes to.
+func jsonschemaInstance(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var instance interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+`
+
+// codegenRuntime is included in every generated file.
+const codegenRuntime = `// jsonschemaState keeps track of where generated code is in an instance and
+// schema, and the errors it has found.
+type jsonschemaState struct {
+	uri      url.URL
+	schema   []string
+	instance []string
+	errors   []jsonschema.ValidationError
+	depth    int
+	maxDepth int
+}
+
+func (s *jsonschemaState) run(fn func(interface{}) error, instance interface{}) (jsonschema.ValidationResult, error) {
+	s.instance = []string{}
+	s.errors = []jsonschema.ValidationError{}
+	s.depth = 1
+
+	if err := fn(instance); err != nil {
+		return jsonschema.ValidationResult{}, err
+	}
+
+	return jsonschema.ValidationResult{Errors: s.errors}, nil
+}
+
+func (s *jsonschemaState) push(token string) {
+	s.schema = append(s.schema, token)
+}
+
+func (s *jsonschemaState) pop() {
+	s.schema = s.schema[:len(s.schema)-1]
+}
+
+func (s *jsonschemaState) pushInstance(token string) {
+	s.instance = append(s.instance, token)
+}
+
+func (s *jsonschemaState) popInstance() {
+	s.instance = s.instance[:len(s.instance)-1]
+}
+
+func (s *jsonschemaState) report(causes []jsonschema.ValidationError, matches []int) {
+	instancePath := make([]string, len(s.instance))
+	schemaPath := make([]string, len(s.schema))
+
+	copy(instancePath, s.instance)
+	copy(schemaPath, s.schema)
+
+	s.errors = append(s.errors, jsonschema.ValidationError{
+		InstancePath: jsonpointer.Ptr{Tokens: instancePath},
+		SchemaPath:   jsonpointer.Ptr{Tokens: schemaPath},
+		URI:          s.uri,
+		Causes:       causes,
+		Matches:      matches,
+	})
+}
+
+func (s *jsonschemaState) reportAt(keyword string) {
+	s.push(keyword)
+	s.report(nil, nil)
+	s.pop()
+}
+
+// jsonschemaTypes returns a bit mask of the JSON types an instance is of.
+func jsonschemaTypes(instance interface{}) int {
+	switch val := instance.(type) {
+	case nil:
+		return 1 << 0
+	case bool:
+		return 1 << 1
+	case float64:
+		if val == math.Round(val) {
+			return 1<<2 | 1<<3
+		}
+
+		return 1 << 2
+	case string:
+		return 1 << 4
+	case []interface{}:
+		return 1 << 5
+	case map[string]interface{}:
+		return 1 << 6
+	}
+
+	return 0
+}
+
+// jsonschemaKeys returns the keys of an object, sorted, so that properties are
+// validated in the same order as jsonschema.Validator does.
+func jsonschemaKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonschemaEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case float64:
+		b, ok := b.(float64)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if !jsonschemaEqual(a[i], b[i]) {
+				return false
+			}
+		}
+
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for key, aValue := range a {
+			bValue, ok := b[key]
+			if !ok || !jsonschemaEqual(aValue, bValue) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func jsonschemaEnum(instance interface{}, values []interface{}) bool {
+	for _, value := range values {
+		if jsonschemaEqual(instance, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonschemaUnique(values []interface{}) bool {
+	for i := 0; i < len(values); i++ {
+		for j := i + 1; j < len(values); j++ {
+			if jsonschemaEqual(values[i], values[j]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+`
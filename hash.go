@@ -0,0 +1,257 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// smallSetSize is the number of values below which a jsonSet is searched
+// linearly, rather than by hash.
+const smallSetSize = 8
+
+// jsonSet is a set of JSON values, supporting quick lookups of whether an
+// instance is equal to any of its values.
+type jsonSet struct {
+	values []interface{}
+
+	// hashes maps the hash of each value to its indices in values. It is nil for
+	// small sets.
+	hashes map[uint64][]int
+}
+
+func newJSONSet(values []interface{}) jsonSet {
+	set := jsonSet{values: values}
+	if len(values) < smallSetSize {
+		return set
+	}
+
+	set.hashes = make(map[uint64][]int, len(values))
+	for i, value := range values {
+		hash := hashJSON(value)
+		set.hashes[hash] = append(set.hashes[hash], i)
+	}
+
+	return set
+}
+
+func (s jsonSet) contains(instance interface{}) bool {
+	if s.hashes == nil {
+		for _, value := range s.values {
+			if jsonEqual(instance, value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, i := range s.hashes[hashJSON(instance)] {
+		if jsonEqual(instance, s.values[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonUnique determines whether all of the given values are distinct.
+func jsonUnique(values []interface{}) bool {
+	if len(values) < smallSetSize {
+		for i := 0; i < len(values); i++ {
+			for j := i + 1; j < len(values); j++ {
+				if jsonEqual(values[i], values[j]) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	hashes := make([]uint64, len(values))
+	seen := make(map[uint64]int, len(values))
+
+	for i, value := range values {
+		hash := hashJSON(value)
+		hashes[i] = hash
+
+		first, ok := seen[hash]
+		if !ok {
+			seen[hash] = i
+			continue
+		}
+
+		if jsonEqual(value, values[first]) {
+			return false
+		}
+
+		// a genuine hash collision; compare against every other value with the
+		// same hash
+		for j := first + 1; j < i; j++ {
+			if hashes[j] == hash && jsonEqual(value, values[j]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// jsonEqual determines whether two JSON values are equal. Numbers are compared
+// by value, regardless of their Go type, so that 1 and 1.0 are equal.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for key, aValue := range a {
+			bValue, ok := b[key]
+			if !ok || !jsonEqual(aValue, bValue) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if aNumber, ok := jsonNumber(a); ok {
+		bNumber, ok := jsonNumber(b)
+		return ok && aNumber == bNumber
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// hashJSON hashes a JSON value, such that values which are equal according to
+// jsonEqual have equal hashes.
+func hashJSON(value interface{}) uint64 {
+	return hashValue(fnvOffset64, value)
+}
+
+func hashValue(h uint64, value interface{}) uint64 {
+	switch value := value.(type) {
+	case nil:
+		return hashByte(h, 'n')
+	case bool:
+		if value {
+			return hashByte(h, 't')
+		}
+
+		return hashByte(h, 'f')
+	case string:
+		return hashString(hashByte(h, 's'), value)
+	case []interface{}:
+		h = hashUint64(hashByte(h, 'a'), uint64(len(value)))
+		for _, elem := range value {
+			h = hashValue(h, elem)
+		}
+
+		return h
+	case map[string]interface{}:
+		// objects are unordered, so the hashes of their members are combined in
+		// an order-independent way
+		sum := uint64(0)
+		for key, elem := range value {
+			sum += hashValue(hashString(fnvOffset64, key), elem)
+		}
+
+		return hashUint64(hashByte(h, 'o'), sum)
+	}
+
+	if number, ok := jsonNumber(value); ok {
+		if number == 0 {
+			number = 0 // normalize negative zero
+		}
+
+		return hashUint64(hashByte(h, 'd'), math.Float64bits(number))
+	}
+
+	// not a JSON value; jsonEqual falls back to reflect.DeepEqual for these
+	return hashByte(h, '?')
+}
+
+func hashByte(h uint64, b byte) uint64 {
+	return (h ^ uint64(b)) * fnvPrime64
+}
+
+func hashString(h uint64, s string) uint64 {
+	h = hashUint64(h, uint64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h = hashByte(h, s[i])
+	}
+
+	return h
+}
+
+func hashUint64(h uint64, n uint64) uint64 {
+	for i := uint(0); i < 64; i += 8 {
+		h = hashByte(h, byte(n>>i))
+	}
+
+	return h
+}
+
+// jsonNumber converts a number of any Go numeric type to a float64.
+func jsonNumber(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int8:
+		return float64(value), true
+	case int16:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint:
+		return float64(value), true
+	case uint8:
+		return float64(value), true
+	case uint16:
+		return float64(value), true
+	case uint32:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case json.Number:
+		number, err := value.Float64()
+		return number, err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,130 @@
+package jsonschema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONEqual(t *testing.T) {
+	testCases := []struct {
+		a     interface{}
+		b     interface{}
+		equal bool
+	}{
+		{nil, nil, true},
+		{nil, false, false},
+		{true, true, true},
+		{true, false, false},
+		{1.0, 1, true},
+		{1.0, int64(1), true},
+		{0.0, -0.0, true},
+		{1.0, 1.5, false},
+		{"1", 1.0, false},
+		{"foo", "foo", true},
+		{[]interface{}{1.0, "a"}, []interface{}{1, "a"}, true},
+		{[]interface{}{1.0, "a"}, []interface{}{"a", 1.0}, false},
+		{[]interface{}{"ab"}, []interface{}{"a", "b"}, false},
+		{
+			map[string]interface{}{"a": 1.0, "b": []interface{}{}},
+			map[string]interface{}{"b": []interface{}{}, "a": 1},
+			true,
+		},
+		{
+			map[string]interface{}{"a": 1.0},
+			map[string]interface{}{"a": 1.0, "b": 2.0},
+			false,
+		},
+		{
+			map[string]interface{}{"a": 1.0, "b": 2.0},
+			map[string]interface{}{"a": 2.0, "b": 1.0},
+			false,
+		},
+	}
+
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			assert.Equal(t, tt.equal, jsonEqual(tt.a, tt.b))
+			assert.Equal(t, tt.equal, jsonEqual(tt.b, tt.a))
+
+			if tt.equal {
+				assert.Equal(t, hashJSON(tt.a), hashJSON(tt.b))
+			}
+		})
+	}
+}
+
+func TestJSONSet(t *testing.T) {
+	values := []interface{}{}
+	for i := 0; i < 100; i++ {
+		values = append(values, float64(i), fmt.Sprintf("%d", i))
+	}
+
+	values = append(values, map[string]interface{}{"a": []interface{}{nil}})
+
+	set := newJSONSet(values)
+	assert.True(t, set.contains(50.0))
+	assert.True(t, set.contains(50))
+	assert.True(t, set.contains("99"))
+	assert.True(t, set.contains(map[string]interface{}{"a": []interface{}{nil}}))
+	assert.False(t, set.contains(100.0))
+	assert.False(t, set.contains(map[string]interface{}{"a": []interface{}{}}))
+	assert.False(t, set.contains(nil))
+}
+
+func TestJSONUnique(t *testing.T) {
+	for _, n := range []int{2, smallSetSize, 1000} {
+		values := []interface{}{}
+		for i := 0; i < n; i++ {
+			values = append(values, []interface{}{float64(i)})
+		}
+
+		assert.True(t, jsonUnique(values))
+
+		values = append(values, []interface{}{int64(n - 1)})
+		assert.False(t, jsonUnique(values))
+	}
+}
+
+func BenchmarkUniqueItems(b *testing.B) {
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{"uniqueItems": true},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	instance := []interface{}{}
+	for i := 0; i < 50000; i++ {
+		instance = append(instance, map[string]interface{}{"id": float64(i)})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := validator.Validate(instance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEnum(b *testing.B) {
+	values := []interface{}{}
+	for i := 0; i < 2000; i++ {
+		values = append(values, fmt.Sprintf("value-%d", i))
+	}
+
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{"enum": values},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := validator.Validate("value-1999"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package fixtures loads the test cases of the tests directory at the root of
+// the repository, and generates validators for them with jsonschema.GenerateGo.
+// It is used by package gentest, and by the program generating its code.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/json-schema-spec/json-schema-go"
+)
+
+// Case is a single test case of the tests directory.
+type Case struct {
+	Path      string
+	Name      string
+	Schemas   []interface{}
+	Instances []interface{}
+}
+
+type testCase struct {
+	Name      string        `json:"name"`
+	Registry  []interface{} `json:"registry"`
+	Schema    interface{}   `json:"schema"`
+	Instances []struct {
+		Instance interface{} `json:"instance"`
+	} `json:"instances"`
+}
+
+// Load reads every test case in the given directory, in a stable order.
+func Load(dir string) ([]Case, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+
+	cases := []Case{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var testCases []testCase
+		if err := json.Unmarshal(data, &testCases); err != nil {
+			return nil, err
+		}
+
+		for _, tt := range testCases {
+			c := Case{
+				Path:      filepath.Base(path),
+				Name:      tt.Name,
+				Schemas:   append([]interface{}{tt.Schema}, tt.Registry...),
+				Instances: []interface{}{},
+			}
+
+			for _, instance := range tt.Instances {
+				c.Instances = append(c.Instances, instance.Instance)
+			}
+
+			cases = append(cases, c)
+		}
+	}
+
+	return cases, nil
+}
+
+// PersonSchema is the schema of the Person type generated by GenerateTypes.
+const PersonSchema = `{
+	"title": "person",
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"age": {"type": "integer", "minimum": 0},
+		"pet": {"oneOf": [
+			{"title": "cat", "properties": {"kind": {"const": "cat"}, "lives": {"type": "integer"}}, "required": ["kind"]},
+			{"title": "dog", "properties": {"kind": {"const": "dog"}}, "required": ["kind"]}
+		]}
+	},
+	"required": ["name"]
+}`
+
+// PersonValidator returns a Validator holding PersonSchema.
+func PersonValidator() (jsonschema.Validator, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(PersonSchema), &schema); err != nil {
+		return jsonschema.Validator{}, err
+	}
+
+	return jsonschema.NewValidator([]interface{}{schema})
+}
+
+// GenerateTypes generates the Person type from PersonSchema.
+func GenerateTypes() ([]byte, error) {
+	validator, err := PersonValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	var types bytes.Buffer
+	err = jsonschema.GenerateGoTypes(&types, jsonschema.GenerateTypesConfig{
+		Package:   "gentest",
+		Validator: &validator,
+		Types:     []jsonschema.GenerateType{{Name: "Person"}},
+	})
+
+	return types.Bytes(), err
+}
+
+// Generate generates a validator for each test case, and validatePerson, which
+// takes a Person. The first file returned holds the validators; the second
+// holds a table of those of the test cases, in the order of cases.
+func Generate(cases []Case) ([]byte, []byte, error) {
+	config := jsonschema.GenerateConfig{Package: "gentest"}
+
+	var table bytes.Buffer
+	table.WriteString("// Code generated by generate.go. DO NOT EDIT.\n\npackage gentest\n\n")
+	table.WriteString("import \"github.com/json-schema-spec/json-schema-go\"\n\n")
+	table.WriteString("var generated = []func(interface{}) (jsonschema.ValidationResult, error){\n")
+
+	for i, c := range cases {
+		validator, err := jsonschema.NewValidator(c.Schemas)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s: %v", c.Path, c.Name, err)
+		}
+
+		name := fmt.Sprintf("fixture%d", i)
+		config.Funcs = append(config.Funcs, jsonschema.GenerateFunc{
+			Name:      name,
+			Validator: &validator,
+		})
+
+		fmt.Fprintf(&table, "\t%s,\n", name)
+	}
+
+	table.WriteString("}\n")
+
+	person, err := PersonValidator()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config.Funcs = append(config.Funcs, jsonschema.GenerateFunc{
+		Name:      "validatePerson",
+		Validator: &person,
+		Type:      "Person",
+	})
+
+	var validators bytes.Buffer
+	if err := jsonschema.GenerateGo(&validators, config); err != nil {
+		return nil, nil, err
+	}
+
+	return validators.Bytes(), table.Bytes(), nil
+}
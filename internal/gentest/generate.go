@@ -0,0 +1,42 @@
+//go:build ignore
+// +build ignore
+
+// This program generates validators_gen.go, table_gen.go and types_gen.go. It
+// is invoked by running go generate.
+package main
+
+import (
+	"io/ioutil"
+	"log"
+
+	"github.com/json-schema-spec/json-schema-go/internal/gentest/fixtures"
+)
+
+func main() {
+	cases, err := fixtures.Load("../../tests")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	validators, table, err := fixtures.Generate(cases)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("validators_gen.go", validators, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("table_gen.go", table, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	types, err := fixtures.GenerateTypes()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("types_gen.go", types, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,11 @@
+// Package gentest checks the code generated by jsonschema.GenerateGo against
+// the Validator it was generated from, using the schemas and instances in the
+// tests directory at the root of the repository.
+//
+// The generated code is committed. After changing the code generator or the
+// tests, run:
+//
+//	go generate ./internal/gentest
+package gentest
+
+//go:generate go run generate.go
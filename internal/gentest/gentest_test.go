@@ -0,0 +1,91 @@
+package gentest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/json-schema-spec/json-schema-go"
+	"github.com/json-schema-spec/json-schema-go/internal/gentest/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerated(t *testing.T) {
+	cases, err := fixtures.Load("../../tests")
+	assert.NoError(t, err)
+	assert.Equal(t, len(cases), len(generated))
+
+	for i, c := range cases {
+		validator, err := jsonschema.NewValidator(c.Schemas)
+		assert.NoError(t, err)
+
+		for j, instance := range c.Instances {
+			name := fmt.Sprintf("%s/%s/%d", c.Path, c.Name, j)
+
+			expected, err := validator.Validate(instance)
+			assert.NoError(t, err, name)
+
+			actual, err := generated[i](instance)
+			assert.NoError(t, err, name)
+
+			assert.Equal(t, expected, actual, name)
+		}
+	}
+}
+
+func TestGeneratedUpToDate(t *testing.T) {
+	cases, err := fixtures.Load("../../tests")
+	assert.NoError(t, err)
+
+	validators, table, err := fixtures.Generate(cases)
+	assert.NoError(t, err)
+
+	committed, err := ioutil.ReadFile("validators_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(validators), string(committed), "validators_gen.go is stale; run go generate")
+
+	committed, err = ioutil.ReadFile("table_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(table), string(committed), "table_gen.go is stale; run go generate")
+
+	types, err := fixtures.GenerateTypes()
+	assert.NoError(t, err)
+
+	committed, err = ioutil.ReadFile("types_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(types), string(committed), "types_gen.go is stale; run go generate")
+}
+
+func TestGeneratedTyped(t *testing.T) {
+	validator, err := fixtures.PersonValidator()
+	assert.NoError(t, err)
+
+	age := int64(-1)
+	lives := int64(9)
+	for _, person := range []Person{
+		{Name: "x"},
+		{Name: "", Age: &age},
+		{Name: "x", Pet: Cat{}},
+		{Name: "x", Pet: Cat{Lives: &lives}},
+		{Name: "x", Pet: Dog{}},
+	} {
+		data, err := json.Marshal(person)
+		assert.NoError(t, err)
+
+		var decoded Person
+		assert.NoError(t, json.Unmarshal(data, &decoded), string(data))
+		assert.Equal(t, person, decoded, string(data))
+
+		var instance interface{}
+		assert.NoError(t, json.Unmarshal(data, &instance))
+
+		expected, err := validator.Validate(instance)
+		assert.NoError(t, err)
+
+		actual, err := validatePerson(person)
+		assert.NoError(t, err)
+
+		assert.Equal(t, expected, actual, string(data))
+	}
+}
@@ -0,0 +1,106 @@
+// Code generated by jsonschema. DO NOT EDIT.
+
+package gentest
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Person is an object, generated from #.
+type Person struct {
+	Age  *int64    `json:"age,omitempty"`
+	Name string    `json:"name"`
+	Pet  PersonPet `json:"pet,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Person) UnmarshalJSON(data []byte) error {
+	type plain Person
+	var raw struct {
+		*plain
+		Pet json.RawMessage `json:"pet"`
+	}
+
+	raw.plain = (*plain)(v)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if len(raw.Pet) > 0 && string(raw.Pet) != "null" {
+		decoded, err := UnmarshalPersonPet(raw.Pet)
+		if err != nil {
+			return err
+		}
+
+		v.Pet = decoded
+	}
+
+	return nil
+}
+
+// PersonPet is one of Cat or Dog, generated from #/properties/pet.
+type PersonPet interface {
+	isPersonPet()
+}
+
+// UnmarshalPersonPet decodes JSON data holding one of the types of PersonPet.
+func UnmarshalPersonPet(data []byte) (PersonPet, error) {
+	var probe struct {
+		Value string `json:"kind"`
+	}
+
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	switch probe.Value {
+	case "cat":
+		var value Cat
+		err := json.Unmarshal(data, &value)
+		return value, err
+	case "dog":
+		var value Dog
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+
+	return nil, fmt.Errorf("unknown kind %q of PersonPet", probe.Value)
+}
+
+// Cat is an object, generated from #/properties/pet/oneOf/0.
+type Cat struct {
+	Lives *int64 `json:"lives,omitempty"`
+}
+
+func (Cat) isPersonPet() {}
+
+// MarshalJSON implements json.Marshaler, setting "kind" to "cat".
+func (v Cat) MarshalJSON() ([]byte, error) {
+	type plain Cat
+	return json.Marshal(struct {
+		Tag0 string `json:"kind"`
+		plain
+	}{
+		Tag0:  "cat",
+		plain: plain(v),
+	})
+}
+
+// Dog is an object, generated from #/properties/pet/oneOf/1.
+type Dog struct {
+}
+
+func (Dog) isPersonPet() {}
+
+// MarshalJSON implements json.Marshaler, setting "kind" to "dog".
+func (v Dog) MarshalJSON() ([]byte, error) {
+	type plain Dog
+	return json.Marshal(struct {
+		Tag0 string `json:"kind"`
+		plain
+	}{
+		Tag0:  "dog",
+		plain: plain(v),
+	})
+}
@@ -0,0 +1,4430 @@
+// Code generated by jsonschema. DO NOT EDIT.
+
+package gentest
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/json-schema-spec/json-schema-go"
+	"github.com/ucarion/json-pointer"
+)
+
+// fixture0 evaluates an instance against the default schema.
+func fixture0(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v0schema0, instance)
+}
+
+// fixture1 evaluates an instance against the default schema.
+func fixture1(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v1schema1, instance)
+}
+
+// fixture2 evaluates an instance against the default schema.
+func fixture2(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v2schema2, instance)
+}
+
+// fixture3 evaluates an instance against the default schema.
+func fixture3(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v3schema3, instance)
+}
+
+// fixture4 evaluates an instance against the default schema.
+func fixture4(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v4schema4, instance)
+}
+
+// fixture5 evaluates an instance against the default schema.
+func fixture5(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v5schema5, instance)
+}
+
+// fixture6 evaluates an instance against the default schema.
+func fixture6(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v6schema6, instance)
+}
+
+// fixture7 evaluates an instance against the default schema.
+func fixture7(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v7schema7, instance)
+}
+
+// fixture8 evaluates an instance against the default schema.
+func fixture8(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v8schema8, instance)
+}
+
+// fixture9 evaluates an instance against the default schema.
+func fixture9(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v9schema9, instance)
+}
+
+// fixture10 evaluates an instance against the default schema.
+func fixture10(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v10schema10, instance)
+}
+
+// fixture11 evaluates an instance against the default schema.
+func fixture11(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v11schema11, instance)
+}
+
+// fixture12 evaluates an instance against the default schema.
+func fixture12(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v12schema12, instance)
+}
+
+// fixture13 evaluates an instance against the default schema.
+func fixture13(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v13schema13, instance)
+}
+
+// fixture14 evaluates an instance against the default schema.
+func fixture14(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v14schema14, instance)
+}
+
+// fixture15 evaluates an instance against the default schema.
+func fixture15(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v15schema15, instance)
+}
+
+// fixture16 evaluates an instance against the default schema.
+func fixture16(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v16schema16, instance)
+}
+
+// fixture17 evaluates an instance against the default schema.
+func fixture17(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v17schema17, instance)
+}
+
+// fixture18 evaluates an instance against the default schema.
+func fixture18(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v18schema18, instance)
+}
+
+// fixture19 evaluates an instance against the default schema.
+func fixture19(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v19schema19, instance)
+}
+
+// fixture20 evaluates an instance against the default schema.
+func fixture20(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v20schema20, instance)
+}
+
+// fixture21 evaluates an instance against the default schema.
+func fixture21(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v21schema21, instance)
+}
+
+// fixture22 evaluates an instance against the default schema.
+func fixture22(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v22schema22, instance)
+}
+
+// fixture23 evaluates an instance against the default schema.
+func fixture23(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v23schema23, instance)
+}
+
+// fixture24 evaluates an instance against the default schema.
+func fixture24(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v24schema24, instance)
+}
+
+// fixture25 evaluates an instance against the default schema.
+func fixture25(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v25schema25, instance)
+}
+
+// fixture26 evaluates an instance against the default schema.
+func fixture26(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v26schema26, instance)
+}
+
+// fixture27 evaluates an instance against the default schema.
+func fixture27(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v27schema27, instance)
+}
+
+// fixture28 evaluates an instance against the default schema.
+func fixture28(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v28schema28, instance)
+}
+
+// fixture29 evaluates an instance against the default schema.
+func fixture29(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v29schema29, instance)
+}
+
+// fixture30 evaluates an instance against the default schema.
+func fixture30(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v30schema30, instance)
+}
+
+// fixture31 evaluates an instance against the default schema.
+func fixture31(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v31schema31, instance)
+}
+
+// fixture32 evaluates an instance against the default schema.
+func fixture32(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v32schema32, instance)
+}
+
+// fixture33 evaluates an instance against the default schema.
+func fixture33(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v33schema33, instance)
+}
+
+// fixture34 evaluates an instance against the default schema.
+func fixture34(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v34schema34, instance)
+}
+
+// fixture35 evaluates an instance against the default schema.
+func fixture35(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v35schema35, instance)
+}
+
+// fixture36 evaluates an instance against the default schema.
+func fixture36(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v36schema36, instance)
+}
+
+// fixture37 evaluates an instance against the default schema.
+func fixture37(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v37schema37, instance)
+}
+
+// fixture38 evaluates an instance against the default schema.
+func fixture38(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v38schema38, instance)
+}
+
+// fixture39 evaluates an instance against the default schema.
+func fixture39(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v39schema39, instance)
+}
+
+// fixture40 evaluates an instance against the default schema.
+func fixture40(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v40schema40, instance)
+}
+
+// fixture41 evaluates an instance against the default schema.
+func fixture41(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v41schema41, instance)
+}
+
+// fixture42 evaluates an instance against the default schema.
+func fixture42(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v42schema42, instance)
+}
+
+// fixture43 evaluates an instance against the default schema.
+func fixture43(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v43schema43, instance)
+}
+
+// fixture44 evaluates an instance against the default schema.
+func fixture44(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v44schema44, instance)
+}
+
+// fixture45 evaluates an instance against the default schema.
+func fixture45(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v45schema45, instance)
+}
+
+// fixture46 evaluates an instance against the default schema.
+func fixture46(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v46schema46, instance)
+}
+
+// fixture47 evaluates an instance against the default schema.
+func fixture47(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v47schema47, instance)
+}
+
+// fixture48 evaluates an instance against the default schema.
+func fixture48(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v48schema48, instance)
+}
+
+// fixture49 evaluates an instance against the default schema.
+func fixture49(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v49schema49, instance)
+}
+
+// fixture50 evaluates an instance against the default schema.
+func fixture50(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v50schema50, instance)
+}
+
+// fixture51 evaluates an instance against the default schema.
+func fixture51(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v51schema51, instance)
+}
+
+// fixture52 evaluates an instance against the default schema.
+func fixture52(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v52schema52, instance)
+}
+
+// fixture53 evaluates an instance against the default schema.
+func fixture53(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v53schema53, instance)
+}
+
+// fixture54 evaluates an instance against the default schema.
+func fixture54(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v54schema54, instance)
+}
+
+// fixture55 evaluates an instance against the default schema.
+func fixture55(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v55schema55, instance)
+}
+
+// fixture56 evaluates an instance against the default schema.
+func fixture56(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v56schema56, instance)
+}
+
+// fixture57 evaluates an instance against the default schema.
+func fixture57(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v57schema57, instance)
+}
+
+// fixture58 evaluates an instance against the default schema.
+func fixture58(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v58schema58, instance)
+}
+
+// fixture59 evaluates an instance against the default schema.
+func fixture59(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v59schema59, instance)
+}
+
+// fixture60 evaluates an instance against the default schema.
+func fixture60(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v60schema60, instance)
+}
+
+// fixture61 evaluates an instance against the default schema.
+func fixture61(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v61schema61, instance)
+}
+
+// fixture62 evaluates an instance against the default schema.
+func fixture62(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v62schema62, instance)
+}
+
+// fixture63 evaluates an instance against the default schema.
+func fixture63(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v63schema63, instance)
+}
+
+// fixture64 evaluates an instance against the default schema.
+func fixture64(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v64schema64, instance)
+}
+
+// fixture65 evaluates an instance against the default schema.
+func fixture65(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v65schema65, instance)
+}
+
+// fixture66 evaluates an instance against the default schema.
+func fixture66(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v66schema66, instance)
+}
+
+// fixture67 evaluates an instance against the default schema.
+func fixture67(instance interface{}) (jsonschema.ValidationResult, error) {
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v67schema67, instance)
+}
+
+// validatePerson evaluates the JSON encoding of a value against the default schema.
+func validatePerson(value Person) (jsonschema.ValidationResult, error) {
+	instance, err := jsonschemaInstance(value)
+	if err != nil {
+		return jsonschema.ValidationResult{}, err
+	}
+
+	s := jsonschemaState{uri: jsonschemaURI0, schema: jsonschemaTokens1, maxDepth: 128}
+	return s.run(s.v68schema68, instance)
+}
+
+func (s *jsonschemaState) v0schema0(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v1schema1(instance interface{}) error {
+	s.report(nil, nil)
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v2schema2(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v3schema3(instance interface{}) error {
+	if jsonschemaTypes(instance)&2 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v4schema4(instance interface{}) error {
+	if jsonschemaTypes(instance)&4 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v5schema5(instance interface{}) error {
+	if jsonschemaTypes(instance)&8 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v6schema6(instance interface{}) error {
+	if jsonschemaTypes(instance)&16 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v7schema7(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v8schema8(instance interface{}) error {
+	if jsonschemaTypes(instance)&64 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v9schema9(instance interface{}) error {
+	if jsonschemaTypes(instance)&41 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v10schema10(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v10schema69(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v11schema11(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		if len(val) > 0 {
+			s.pushInstance("0")
+			s.push("0")
+			if err := s.v11schema70(val[0]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		if len(val) > 1 {
+			s.pushInstance("1")
+			s.push("1")
+			if err := s.v11schema71(val[1]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		if len(val) > 2 {
+			s.pushInstance("2")
+			s.push("2")
+			if err := s.v11schema72(val[2]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v12schema12(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema13(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		if len(val) > 0 {
+			s.pushInstance("0")
+			s.push("0")
+			if err := s.v13schema73(val[0]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		if len(val) > 1 {
+			s.pushInstance("1")
+			s.push("1")
+			if err := s.v13schema74(val[1]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v14schema14(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens2
+		s.depth++
+		if err := s.v14schema75(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v15schema15(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens2
+		s.depth++
+		if err := s.v15schema76(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v16schema16(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI3, jsonschemaTokens1
+		s.depth++
+		if err := s.v16schema77(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v17schema17(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI3, jsonschemaTokens1
+		s.depth++
+		if err := s.v17schema78(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v18schema18(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v18schema79(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v19schema19(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI4, jsonschemaTokens1
+		s.depth++
+		if err := s.v19schema80(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v20schema20(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI5, jsonschemaTokens1
+		s.depth++
+		if err := s.v20schema81(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v21schema21(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI6, jsonschemaTokens1
+		s.depth++
+		if err := s.v21schema82(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v22schema22(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens7
+		s.depth++
+		if err := s.v22schema83(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v23schema23(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens8
+		s.depth++
+		if err := s.v23schema84(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v24schema24(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens9
+		s.depth++
+		if err := s.v24schema85(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v25schema25(instance interface{}) error {
+	{
+		s.push("not")
+		prevErrors := s.errors
+		s.errors = []jsonschema.ValidationError{}
+		if err := s.v25schema86(instance); err != nil {
+			return err
+		}
+		errs := s.errors
+		s.errors = prevErrors
+		if len(errs) == 0 {
+			s.report(nil, nil)
+		}
+		s.pop()
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v26schema26(instance interface{}) error {
+	{
+		s.push("not")
+		prevErrors := s.errors
+		s.errors = []jsonschema.ValidationError{}
+		if err := s.v26schema87(instance); err != nil {
+			return err
+		}
+		errs := s.errors
+		s.errors = prevErrors
+		if len(errs) == 0 {
+			s.report(nil, nil)
+		}
+		s.pop()
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v27schema27(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v27schema88(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v28schema28(instance interface{}) error {
+	{
+		s.push("if")
+		prevErrors := s.errors
+		s.errors = []jsonschema.ValidationError{}
+		if err := s.v28schema89(instance); err != nil {
+			return err
+		}
+		errs := s.errors
+		s.errors = prevErrors
+		s.pop()
+		if len(errs) == 0 {
+			s.push("then")
+			if err := s.v28schema90(instance); err != nil {
+				return err
+			}
+			s.pop()
+		}
+		if len(errs) != 0 {
+			s.push("else")
+			if err := s.v28schema91(instance); err != nil {
+				return err
+			}
+			s.pop()
+		}
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v29schema29(instance interface{}) error {
+	{
+		s.push("if")
+		prevErrors := s.errors
+		s.errors = []jsonschema.ValidationError{}
+		if err := s.v29schema92(instance); err != nil {
+			return err
+		}
+		errs := s.errors
+		s.errors = prevErrors
+		s.pop()
+		if len(errs) != 0 {
+			s.push("else")
+			if err := s.v29schema93(instance); err != nil {
+				return err
+			}
+			s.pop()
+		}
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v30schema30(instance interface{}) error {
+	{
+		s.push("if")
+		prevErrors := s.errors
+		s.errors = []jsonschema.ValidationError{}
+		if err := s.v30schema94(instance); err != nil {
+			return err
+		}
+		errs := s.errors
+		s.errors = prevErrors
+		s.pop()
+		if len(errs) == 0 {
+			s.push("then")
+			if err := s.v30schema95(instance); err != nil {
+				return err
+			}
+			s.pop()
+		}
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v31schema31(instance interface{}) error {
+	if !jsonschemaEqual(instance, jsonschemaValue10) {
+		s.reportAt("const")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v32schema32(instance interface{}) error {
+	if !jsonschemaEnum(instance, jsonschemaValue11) {
+		s.reportAt("enum")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v33schema33(instance interface{}) error {
+	if !jsonschemaEnum(instance, jsonschemaValue12) {
+		s.reportAt("enum")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v34schema34(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+		if math.Abs(math.Mod(val, 3.14)) > 0.001 {
+			s.reportAt("multipleOf")
+		}
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v35schema35(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+		if val > 3.14 {
+			s.reportAt("maximum")
+		}
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v36schema36(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+		if val < 3.14 {
+			s.reportAt("minimum")
+		}
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v37schema37(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+		if val > 3.1390000000000002 {
+			s.reportAt("exclusiveMaximum")
+		}
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v38schema38(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+		if val < 3.141 {
+			s.reportAt("exclusiveMinimum")
+		}
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v39schema39(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) > 3 {
+			s.reportAt("maxLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v40schema40(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) > 0 {
+			s.reportAt("maxLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v41schema41(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) < 3 {
+			s.reportAt("minLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v42schema42(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) < 0 {
+			s.reportAt("minLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v43schema43(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if !jsonschemaPattern13.MatchString(val) {
+			s.reportAt("pattern")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v44schema44(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		if len(val) > 0 {
+			s.pushInstance("0")
+			s.push("0")
+			if err := s.v44schema96(val[0]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		if len(val) > 1 {
+			s.pushInstance("1")
+			s.push("1")
+			if err := s.v44schema97(val[1]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		if len(val) > 2 {
+			s.pushInstance("2")
+			s.push("2")
+			if err := s.v44schema98(val[2]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		s.pop()
+		s.push("additionalItems")
+		for i := 3; i < len(val); i++ {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v44schema99(val[i]); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v45schema45(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v46schema46(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		if len(val) > 3 {
+			s.reportAt("maxItems")
+		}
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v47schema47(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		if len(val) > 0 {
+			s.reportAt("maxItems")
+		}
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v48schema48(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		if len(val) < 3 {
+			s.reportAt("minItems")
+		}
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v49schema49(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		if len(val) < 0 {
+			s.reportAt("minItems")
+		}
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v50schema50(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		if !jsonschemaUnique(val) {
+			s.reportAt("uniqueItems")
+		}
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v51schema51(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v52schema52(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		{
+			containsOk := false
+			containsCauses := []jsonschema.ValidationError(nil)
+			s.push("contains")
+			for i, elem := range val {
+				s.pushInstance(strconv.Itoa(i))
+				prevErrors := s.errors
+				s.errors = []jsonschema.ValidationError{}
+				if err := s.v52schema100(elem); err != nil {
+					return err
+				}
+				errs := s.errors
+				s.errors = prevErrors
+				s.popInstance()
+				if len(errs) == 0 {
+					containsOk = true
+					break
+				}
+				containsCauses = append(containsCauses, errs...)
+			}
+			s.pop()
+			if !containsOk {
+				s.push("contains")
+				s.report(containsCauses, nil)
+				s.pop()
+			}
+		}
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v53schema53(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		if len(val) > 3 {
+			s.reportAt("maxProperties")
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v54schema54(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		if len(val) > 0 {
+			s.reportAt("maxProperties")
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v55schema55(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		if len(val) < 3 {
+			s.reportAt("minProperties")
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v56schema56(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		if len(val) < 0 {
+			s.reportAt("minProperties")
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v57schema57(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("required")
+		for i, property := range jsonschemaTokens14 {
+			if _, ok := val[property]; !ok {
+				s.push(strconv.Itoa(i))
+				s.report(nil, nil)
+				s.pop()
+			}
+		}
+		s.pop()
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v58schema58(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("required")
+		for i, property := range jsonschemaTokens1 {
+			if _, ok := val[property]; !ok {
+				s.push(strconv.Itoa(i))
+				s.report(nil, nil)
+				s.pop()
+			}
+		}
+		s.pop()
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v59schema59(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			switch key {
+			case "bar":
+				s.push("properties")
+				s.push("bar")
+				s.pushInstance(key)
+				if err := s.v59schema101(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			case "foo":
+				s.push("properties")
+				s.push("foo")
+				s.pushInstance(key)
+				if err := s.v59schema102(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v60schema60(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			if jsonschemaPattern15.MatchString(key) {
+				s.push("patternProperties")
+				s.push("ba+r")
+				s.pushInstance(key)
+				if err := s.v60schema103(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			if jsonschemaPattern16.MatchString(key) {
+				s.push("patternProperties")
+				s.push("baa+r")
+				s.pushInstance(key)
+				if err := s.v60schema104(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			if jsonschemaPattern17.MatchString(key) {
+				s.push("patternProperties")
+				s.push("fo+")
+				s.pushInstance(key)
+				if err := s.v60schema105(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v61schema61(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			isAdditional := true
+			switch key {
+			case "foo":
+				isAdditional = false
+				s.push("properties")
+				s.push("foo")
+				s.pushInstance(key)
+				if err := s.v61schema106(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			if jsonschemaPattern15.MatchString(key) {
+				isAdditional = false
+				s.push("patternProperties")
+				s.push("ba+r")
+				s.pushInstance(key)
+				if err := s.v61schema107(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			if isAdditional {
+				s.push("additionalProperties")
+				s.pushInstance(key)
+				if err := s.v61schema108(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v62schema62(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("dependencies")
+		if _, ok := val["bar"]; ok {
+			s.push("bar")
+			for i, property := range jsonschemaTokens18 {
+				if _, ok := val[property]; !ok {
+					s.push(strconv.Itoa(i))
+					s.report(nil, nil)
+					s.pop()
+				}
+			}
+			s.pop()
+		}
+		if value, ok := val["foo"]; ok {
+			s.push("foo")
+			s.pushInstance("foo")
+			if err := s.v62schema109(value); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		s.pop()
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v63schema63(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("propertyNames")
+		for _, key := range jsonschemaKeys(val) {
+			s.pushInstance(key)
+			if err := s.v63schema110(key); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v64schema64(instance interface{}) error {
+	s.push("allOf")
+	s.push("0")
+	if err := s.v64schema111(instance); err != nil {
+		return err
+	}
+	s.pop()
+	s.push("1")
+	if err := s.v64schema112(instance); err != nil {
+		return err
+	}
+	s.pop()
+	s.pop()
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v65schema65(instance interface{}) error {
+	{
+		anyOfOk := false
+		anyOfCauses := []jsonschema.ValidationError(nil)
+		s.push("anyOf")
+		if !anyOfOk {
+			s.push("0")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v65schema113(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) == 0 {
+				anyOfOk = true
+			} else {
+				anyOfCauses = append(anyOfCauses, errs...)
+			}
+		}
+		if !anyOfOk {
+			s.push("1")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v65schema114(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) == 0 {
+				anyOfOk = true
+			} else {
+				anyOfCauses = append(anyOfCauses, errs...)
+			}
+		}
+		s.pop()
+		if !anyOfOk {
+			s.push("anyOf")
+			s.report(anyOfCauses, nil)
+			s.pop()
+		}
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v66schema66(instance interface{}) error {
+	{
+		oneOfCauses := []jsonschema.ValidationError(nil)
+		oneOfMatches := []int(nil)
+		s.push("oneOf")
+		{
+			s.push("0")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v66schema115(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) != 0 {
+				oneOfCauses = append(oneOfCauses, errs...)
+			} else {
+				oneOfMatches = append(oneOfMatches, 0)
+			}
+		}
+		{
+			s.push("1")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v66schema116(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) != 0 {
+				oneOfCauses = append(oneOfCauses, errs...)
+			} else {
+				oneOfMatches = append(oneOfMatches, 1)
+			}
+		}
+		s.pop()
+		if len(oneOfMatches) != 1 {
+			if len(oneOfMatches) > 1 {
+				oneOfCauses = nil
+			} else {
+				oneOfMatches = nil
+			}
+			s.push("oneOf")
+			s.report(oneOfCauses, oneOfMatches)
+			s.pop()
+		}
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema67(instance interface{}) error {
+	oneOfDispatched := false
+	if object, ok := instance.(map[string]interface{}); ok {
+		if tag, ok := object["kind"].(string); ok {
+			switch tag {
+			case "circle":
+				oneOfDispatched = true
+				s.push("oneOf")
+				s.push("0")
+				if err := s.v67schema117(instance); err != nil {
+					return err
+				}
+				s.pop()
+				s.pop()
+			case "square":
+				oneOfDispatched = true
+				s.push("oneOf")
+				s.push("1")
+				if err := s.v67schema118(instance); err != nil {
+					return err
+				}
+				s.pop()
+				s.pop()
+			}
+		}
+	}
+	if !oneOfDispatched {
+		oneOfCauses := []jsonschema.ValidationError(nil)
+		oneOfMatches := []int(nil)
+		s.push("oneOf")
+		{
+			s.push("0")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v67schema117(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) != 0 {
+				oneOfCauses = append(oneOfCauses, errs...)
+			} else {
+				oneOfMatches = append(oneOfMatches, 0)
+			}
+		}
+		{
+			s.push("1")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v67schema118(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) != 0 {
+				oneOfCauses = append(oneOfCauses, errs...)
+			} else {
+				oneOfMatches = append(oneOfMatches, 1)
+			}
+		}
+		s.pop()
+		if len(oneOfMatches) != 1 {
+			if len(oneOfMatches) > 1 {
+				oneOfCauses = nil
+			} else {
+				oneOfMatches = nil
+			}
+			s.push("oneOf")
+			s.report(oneOfCauses, oneOfMatches)
+			s.pop()
+		}
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema68(instance interface{}) error {
+	if jsonschemaTypes(instance)&64 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("required")
+		for i, property := range jsonschemaTokens19 {
+			if _, ok := val[property]; !ok {
+				s.push(strconv.Itoa(i))
+				s.report(nil, nil)
+				s.pop()
+			}
+		}
+		s.pop()
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			switch key {
+			case "age":
+				s.push("properties")
+				s.push("age")
+				s.pushInstance(key)
+				if err := s.v68schema119(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			case "name":
+				s.push("properties")
+				s.push("name")
+				s.pushInstance(key)
+				if err := s.v68schema120(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			case "pet":
+				s.push("properties")
+				s.push("pet")
+				s.pushInstance(key)
+				if err := s.v68schema121(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v10schema69(instance interface{}) error {
+	if jsonschemaTypes(instance)&8 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v11schema70(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v11schema71(instance interface{}) error {
+	if jsonschemaTypes(instance)&8 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v11schema72(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema73(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v13schema122(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema74(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		if len(val) > 0 {
+			s.pushInstance("0")
+			s.push("0")
+			if err := s.v13schema123(val[0]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		if len(val) > 1 {
+			s.pushInstance("1")
+			s.push("1")
+			if err := s.v13schema124(val[1]); err != nil {
+				return err
+			}
+			s.popInstance()
+			s.pop()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v14schema75(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v15schema76(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens20
+		s.depth++
+		if err := s.v15schema125(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v16schema77(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v17schema78(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI4, jsonschemaTokens1
+		s.depth++
+		if err := s.v17schema126(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v18schema79(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens1
+		s.depth++
+		if err := s.v18schema18(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v19schema80(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI3, jsonschemaTokens1
+		s.depth++
+		if err := s.v19schema127(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v20schema81(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI5, jsonschemaTokens2
+		s.depth++
+		if err := s.v20schema128(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v21schema82(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI21, jsonschemaTokens2
+		s.depth++
+		if err := s.v21schema129(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v22schema83(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v23schema84(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v24schema85(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v25schema86(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v26schema87(instance interface{}) error {
+	{
+		s.push("not")
+		prevErrors := s.errors
+		s.errors = []jsonschema.ValidationError{}
+		if err := s.v26schema130(instance); err != nil {
+			return err
+		}
+		errs := s.errors
+		s.errors = prevErrors
+		if len(errs) == 0 {
+			s.report(nil, nil)
+		}
+		s.pop()
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v27schema88(instance interface{}) error {
+	{
+		s.push("not")
+		prevErrors := s.errors
+		s.errors = []jsonschema.ValidationError{}
+		if err := s.v27schema131(instance); err != nil {
+			return err
+		}
+		errs := s.errors
+		s.errors = prevErrors
+		if len(errs) == 0 {
+			s.report(nil, nil)
+		}
+		s.pop()
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v28schema89(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v28schema90(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v28schema132(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v28schema91(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v29schema92(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v29schema93(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v30schema94(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v30schema95(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v30schema133(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v44schema96(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v44schema97(instance interface{}) error {
+	if jsonschemaTypes(instance)&2 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v44schema98(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v44schema99(instance interface{}) error {
+	if jsonschemaTypes(instance)&4 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v52schema100(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v59schema101(instance interface{}) error {
+	if jsonschemaTypes(instance)&2 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v59schema102(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v60schema103(instance interface{}) error {
+	if jsonschemaTypes(instance)&2 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v60schema104(instance interface{}) error {
+	if jsonschemaTypes(instance)&2 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v60schema105(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v61schema106(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v61schema107(instance interface{}) error {
+	if jsonschemaTypes(instance)&2 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v61schema108(instance interface{}) error {
+	if jsonschemaTypes(instance)&16 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v62schema109(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v63schema110(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) > 3 {
+			s.reportAt("maxLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v64schema111(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) > 5 {
+			s.reportAt("maxLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v64schema112(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) < 3 {
+			s.reportAt("minLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v65schema113(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) > 3 {
+			s.reportAt("maxLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v65schema114(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) < 5 {
+			s.reportAt("minLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v66schema115(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) < 3 {
+			s.reportAt("minLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v66schema116(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) < 5 {
+			s.reportAt("minLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema117(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("required")
+		for i, property := range jsonschemaTokens22 {
+			if _, ok := val[property]; !ok {
+				s.push(strconv.Itoa(i))
+				s.report(nil, nil)
+				s.pop()
+			}
+		}
+		s.pop()
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			switch key {
+			case "kind":
+				s.push("properties")
+				s.push("kind")
+				s.pushInstance(key)
+				if err := s.v67schema134(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			case "radius":
+				s.push("properties")
+				s.push("radius")
+				s.pushInstance(key)
+				if err := s.v67schema135(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema118(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens23
+		s.depth++
+		if err := s.v67schema136(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema119(instance interface{}) error {
+	if jsonschemaTypes(instance)&8 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+		if val < 0 {
+			s.reportAt("minimum")
+		}
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema120(instance interface{}) error {
+	if jsonschemaTypes(instance)&16 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+		if utf8.RuneCountInString(val) < 1 {
+			s.reportAt("minLength")
+		}
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema121(instance interface{}) error {
+	oneOfDispatched := false
+	if object, ok := instance.(map[string]interface{}); ok {
+		if tag, ok := object["kind"].(string); ok {
+			switch tag {
+			case "cat":
+				oneOfDispatched = true
+				s.push("oneOf")
+				s.push("0")
+				if err := s.v68schema137(instance); err != nil {
+					return err
+				}
+				s.pop()
+				s.pop()
+			case "dog":
+				oneOfDispatched = true
+				s.push("oneOf")
+				s.push("1")
+				if err := s.v68schema138(instance); err != nil {
+					return err
+				}
+				s.pop()
+				s.pop()
+			}
+		}
+	}
+	if !oneOfDispatched {
+		oneOfCauses := []jsonschema.ValidationError(nil)
+		oneOfMatches := []int(nil)
+		s.push("oneOf")
+		{
+			s.push("0")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v68schema137(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) != 0 {
+				oneOfCauses = append(oneOfCauses, errs...)
+			} else {
+				oneOfMatches = append(oneOfMatches, 0)
+			}
+		}
+		{
+			s.push("1")
+			prevErrors := s.errors
+			s.errors = []jsonschema.ValidationError{}
+			if err := s.v68schema138(instance); err != nil {
+				return err
+			}
+			errs := s.errors
+			s.errors = prevErrors
+			s.pop()
+			if len(errs) != 0 {
+				oneOfCauses = append(oneOfCauses, errs...)
+			} else {
+				oneOfMatches = append(oneOfMatches, 1)
+			}
+		}
+		s.pop()
+		if len(oneOfMatches) != 1 {
+			if len(oneOfMatches) > 1 {
+				oneOfCauses = nil
+			} else {
+				oneOfMatches = nil
+			}
+			s.push("oneOf")
+			s.report(oneOfCauses, oneOfMatches)
+			s.pop()
+		}
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema122(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema123(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v13schema139(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema124(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v13schema140(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v15schema125(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v17schema126(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v19schema127(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+		s.push("items")
+		for i, elem := range val {
+			s.pushInstance(strconv.Itoa(i))
+			if err := s.v19schema141(elem); err != nil {
+				return err
+			}
+			s.popInstance()
+		}
+		s.pop()
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v20schema128(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v21schema129(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v26schema130(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v27schema131(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI0, jsonschemaTokens1
+		s.depth++
+		if err := s.v27schema27(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v28schema132(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v30schema133(instance interface{}) error {
+	if jsonschemaTypes(instance)&1 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema134(instance interface{}) error {
+	if !jsonschemaEqual(instance, jsonschemaValue24) {
+		s.reportAt("const")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema135(instance interface{}) error {
+	if jsonschemaTypes(instance)&4 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema136(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("required")
+		for i, property := range jsonschemaTokens25 {
+			if _, ok := val[property]; !ok {
+				s.push(strconv.Itoa(i))
+				s.report(nil, nil)
+				s.pop()
+			}
+		}
+		s.pop()
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			switch key {
+			case "kind":
+				s.push("properties")
+				s.push("kind")
+				s.pushInstance(key)
+				if err := s.v67schema142(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			case "side":
+				s.push("properties")
+				s.push("side")
+				s.pushInstance(key)
+				if err := s.v67schema143(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema137(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("required")
+		for i, property := range jsonschemaTokens26 {
+			if _, ok := val[property]; !ok {
+				s.push(strconv.Itoa(i))
+				s.report(nil, nil)
+				s.pop()
+			}
+		}
+		s.pop()
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			switch key {
+			case "kind":
+				s.push("properties")
+				s.push("kind")
+				s.pushInstance(key)
+				if err := s.v68schema144(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			case "lives":
+				s.push("properties")
+				s.push("lives")
+				s.pushInstance(key)
+				if err := s.v68schema145(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema138(instance interface{}) error {
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+		s.push("required")
+		for i, property := range jsonschemaTokens26 {
+			if _, ok := val[property]; !ok {
+				s.push(strconv.Itoa(i))
+				s.report(nil, nil)
+				s.pop()
+			}
+		}
+		s.pop()
+		for _, key := range jsonschemaKeys(val) {
+			value := val[key]
+			switch key {
+			case "kind":
+				s.push("properties")
+				s.push("kind")
+				s.pushInstance(key)
+				if err := s.v68schema146(value); err != nil {
+					return err
+				}
+				s.popInstance()
+				s.pop()
+				s.pop()
+			}
+			_, _ = key, value
+		}
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema139(instance interface{}) error {
+	if jsonschemaTypes(instance)&16 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v13schema140(instance interface{}) error {
+	if jsonschemaTypes(instance)&32 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v19schema141(instance interface{}) error {
+	if s.depth == s.maxDepth {
+		return jsonschema.ErrStackOverflow
+	}
+	{
+		prevURI, prevSchema := s.uri, s.schema
+		s.uri, s.schema = jsonschemaURI4, jsonschemaTokens1
+		s.depth++
+		if err := s.v19schema80(instance); err != nil {
+			return err
+		}
+		s.depth--
+		s.uri, s.schema = prevURI, prevSchema
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema142(instance interface{}) error {
+	if !jsonschemaEnum(instance, jsonschemaValue27) {
+		s.reportAt("enum")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v67schema143(instance interface{}) error {
+	if jsonschemaTypes(instance)&4 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema144(instance interface{}) error {
+	if !jsonschemaEqual(instance, jsonschemaValue28) {
+		s.reportAt("const")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema145(instance interface{}) error {
+	if jsonschemaTypes(instance)&8 == 0 {
+		s.reportAt("type")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+func (s *jsonschemaState) v68schema146(instance interface{}) error {
+	if !jsonschemaEqual(instance, jsonschemaValue29) {
+		s.reportAt("const")
+	}
+	switch val := instance.(type) {
+	case nil:
+	case bool:
+	case float64:
+	case string:
+	case []interface{}:
+	case map[string]interface{}:
+	default:
+		_ = val
+		panic("unexpected non-json input")
+	}
+
+	return nil
+}
+
+var (
+	jsonschemaURI0      = url.URL{}
+	jsonschemaTokens1   = []string{}
+	jsonschemaTokens2   = []string{"foobar", "baz"}
+	jsonschemaURI3      = url.URL{Scheme: "urn", Opaque: "example:foo"}
+	jsonschemaURI4      = url.URL{Scheme: "urn", Opaque: "example:bar"}
+	jsonschemaURI5      = url.URL{Scheme: "http", Host: "example.com", Path: "/foo"}
+	jsonschemaURI6      = url.URL{Scheme: "http", Host: "example.com", Path: "/foo/bar/baz"}
+	jsonschemaTokens7   = []string{"////"}
+	jsonschemaTokens8   = []string{"/~~/"}
+	jsonschemaTokens9   = []string{"/", "~", "~/", "", "", "/"}
+	jsonschemaValue10   = map[string]interface{}{"3.14": float64(3.14), "foo": "bar"}
+	jsonschemaValue11   = []interface{}{nil, map[string]interface{}{"foo": "bar"}, map[string]interface{}{"foo": "bar"}, float64(3.14)}
+	jsonschemaValue12   = []interface{}{}
+	jsonschemaPattern13 = regexp.MustCompile("fo+ba[rz]")
+	jsonschemaTokens14  = []string{"foo", "bar"}
+	jsonschemaPattern15 = regexp.MustCompile("ba+r")
+	jsonschemaPattern16 = regexp.MustCompile("baa+r")
+	jsonschemaPattern17 = regexp.MustCompile("fo+")
+	jsonschemaTokens18  = []string{"baz", "quux"}
+	jsonschemaTokens19  = []string{"name"}
+	jsonschemaTokens20  = []string{"foobar2", "baz"}
+	jsonschemaURI21     = url.URL{Scheme: "http", Host: "example.com", Path: "/foo/"}
+	jsonschemaTokens22  = []string{"radius"}
+	jsonschemaTokens23  = []string{"definitions", "square"}
+	jsonschemaValue24   = "circle"
+	jsonschemaTokens25  = []string{"side"}
+	jsonschemaTokens26  = []string{"kind"}
+	jsonschemaValue27   = []interface{}{"square"}
+	jsonschemaValue28   = "cat"
+	jsonschemaValue29   = "dog"
+)
+
+// jsonschemaState keeps track of where generated code is in an instance and
+// schema, and the errors it has found.
+type jsonschemaState struct {
+	uri      url.URL
+	schema   []string
+	instance []string
+	errors   []jsonschema.ValidationError
+	depth    int
+	maxDepth int
+}
+
+func (s *jsonschemaState) run(fn func(interface{}) error, instance interface{}) (jsonschema.ValidationResult, error) {
+	s.instance = []string{}
+	s.errors = []jsonschema.ValidationError{}
+	s.depth = 1
+
+	if err := fn(instance); err != nil {
+		return jsonschema.ValidationResult{}, err
+	}
+
+	return jsonschema.ValidationResult{Errors: s.errors}, nil
+}
+
+func (s *jsonschemaState) push(token string) {
+	s.schema = append(s.schema, token)
+}
+
+func (s *jsonschemaState) pop() {
+	s.schema = s.schema[:len(s.schema)-1]
+}
+
+func (s *jsonschemaState) pushInstance(token string) {
+	s.instance = append(s.instance, token)
+}
+
+func (s *jsonschemaState) popInstance() {
+	s.instance = s.instance[:len(s.instance)-1]
+}
+
+func (s *jsonschemaState) report(causes []jsonschema.ValidationError, matches []int) {
+	instancePath := make([]string, len(s.instance))
+	schemaPath := make([]string, len(s.schema))
+
+	copy(instancePath, s.instance)
+	copy(schemaPath, s.schema)
+
+	s.errors = append(s.errors, jsonschema.ValidationError{
+		InstancePath: jsonpointer.Ptr{Tokens: instancePath},
+		SchemaPath:   jsonpointer.Ptr{Tokens: schemaPath},
+		URI:          s.uri,
+		Causes:       causes,
+		Matches:      matches,
+	})
+}
+
+func (s *jsonschemaState) reportAt(keyword string) {
+	s.push(keyword)
+	s.report(nil, nil)
+	s.pop()
+}
+
+// jsonschemaTypes returns a bit mask of the JSON types an instance is of.
+func jsonschemaTypes(instance interface{}) int {
+	switch val := instance.(type) {
+	case nil:
+		return 1 << 0
+	case bool:
+		return 1 << 1
+	case float64:
+		if val == math.Round(val) {
+			return 1<<2 | 1<<3
+		}
+
+		return 1 << 2
+	case string:
+		return 1 << 4
+	case []interface{}:
+		return 1 << 5
+	case map[string]interface{}:
+		return 1 << 6
+	}
+
+	return 0
+}
+
+// jsonschemaKeys returns the keys of an object, sorted, so that properties are
+// validated in the same order as jsonschema.Validator does.
+func jsonschemaKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonschemaEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case float64:
+		b, ok := b.(float64)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if !jsonschemaEqual(a[i], b[i]) {
+				return false
+			}
+		}
+
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for key, aValue := range a {
+			bValue, ok := b[key]
+			if !ok || !jsonschemaEqual(aValue, bValue) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func jsonschemaEnum(instance interface{}, values []interface{}) bool {
+	for _, value := range values {
+		if jsonschemaEqual(instance, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonschemaUnique(values []interface{}) bool {
+	for i := 0; i < len(values); i++ {
+		for j := i + 1; j < len(values); j++ {
+			if jsonschemaEqual(values[i], values[j]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// jsonschemaInstance converts a value into the instance its JSON encoding
+// decodes to.
+func jsonschemaInstance(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var instance interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
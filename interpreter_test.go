@@ -0,0 +1,956 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucarion/json-pointer"
+)
+
+// This file holds the tree-walking interpreter which evaluated schemas before
+// they were compiled into programs. It is kept as an oracle: compiled programs
+// must produce exactly the results the interpreter does, including the order of
+// errors and the steps of traces. The exception is a MaxErrors of one, with
+// which compiled programs evaluate cheap keywords first; the error they report
+// need only be one of those the interpreter reports.
+
+func TestProgramMatchesInterpreter(t *testing.T) {
+	configs := []ValidatorConfig{
+		{MaxStackDepth: DefaultMaxStackDepth},
+		{MaxStackDepth: DefaultMaxStackDepth, MaxErrors: 2},
+		{MaxStackDepth: DefaultMaxStackDepth, MaxErrorsPerInstanceLocation: 1},
+		{MaxStackDepth: DefaultMaxStackDepth, Trace: true},
+	}
+
+	err := filepath.Walk("tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var testCases []testCase
+		if err := json.Unmarshal(data, &testCases); err != nil {
+			return err
+		}
+
+		for _, tt := range testCases {
+			schemas := []interface{}{tt.Schema}
+			schemas = append(schemas, tt.Registry...)
+
+			for i, config := range configs {
+				validator, err := NewValidatorWithConfig(schemas, config)
+				if err != nil {
+					return err
+				}
+
+				for j, instance := range tt.Instances {
+					name := fmt.Sprintf("%s/%s/%d/%d", path, tt.Name, i, j)
+
+					expected, expectedErr := validator.interpret(url.URL{}, instance.Instance)
+					actual, actualErr := validator.Validate(instance.Instance)
+
+					assert.Equal(t, expectedErr, actualErr, name)
+					assert.Equal(t, expected, actual, name)
+				}
+			}
+
+			all, err := NewValidatorWithConfig(schemas, configs[0])
+			if err != nil {
+				return err
+			}
+
+			first, err := NewValidatorWithConfig(schemas, ValidatorConfig{MaxStackDepth: DefaultMaxStackDepth, MaxErrors: 1})
+			if err != nil {
+				return err
+			}
+
+			for j, instance := range tt.Instances {
+				name := fmt.Sprintf("%s/%s/first/%d", path, tt.Name, j)
+
+				expected, expectedErr := all.interpret(url.URL{}, instance.Instance)
+				actual, actualErr := first.Validate(instance.Instance)
+				if expectedErr != nil {
+					// quitting at a second error may avoid a stack overflow
+					continue
+				}
+
+				assert.NoError(t, actualErr, name)
+				assert.Len(t, actual.Errors, minInt(1, len(expected.Errors)), name)
+				assert.Equal(t, len(expected.Errors) > 1, actual.Overflowed, name)
+				for _, actualError := range actual.Errors {
+					assert.Contains(t, expected.Errors, actualError, name)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	assert.Nil(t, err)
+}
+
+// interpret is the interpreter's counterpart to ValidateURI.
+func (v *Validator) interpret(uri url.URL, instance interface{}) (ValidationResult, error) {
+	vm := newVM(v.registry, v.maxStackDepth, v.maxErrors, v.maxErrorsPerInstance, v.maxErrorsPerKeyword)
+	if v.trace {
+		vm.trace = &tracer{}
+	}
+
+	schema, ok := vm.registry.Get(uri)
+	if !ok {
+		return ValidationResult{}, ErrNoSuchSchema
+	}
+
+	fragPtr, err := jsonpointer.New(uri.Fragment)
+	if err != nil {
+		return ValidationResult{}, err
+	}
+
+	vm.pushNewSchema(uri, fragPtr.Tokens)
+	err = vm.interpretSchema(schema, instance)
+	if err == errMaxErrors {
+		vm.limits.overflowed = true
+		vm.traceAbort()
+	} else if err != nil {
+		return ValidationResult{}, err
+	}
+
+	return vm.ValidationResult(), nil
+}
+
+func (vm *vm) interpretSchema(schema *schema, instance interface{}) error {
+	vm.traceEnter("")
+
+	if schema.Bool.IsSet {
+		if !schema.Bool.Value {
+			if err := vm.reportError(); err != nil {
+				return err
+			}
+		}
+
+		vm.traceExit()
+		return nil
+	}
+
+	if schema.Ref.IsSet {
+		vm.traceEnter("$ref")
+
+		if len(vm.stack.schemas) == vm.maxStackDepth {
+			return ErrStackOverflow
+		}
+
+		refSchema := vm.registry.GetIndex(schema.Ref.Schema)
+
+		vm.pushNewSchema(schema.Ref.BaseURI, schema.Ref.Ptr.Tokens)
+		if err := vm.interpretSchema(refSchema, instance); err != nil {
+			return err
+		}
+		vm.popSchema()
+
+		vm.traceExit()
+	}
+
+	if schema.Not.IsSet {
+		vm.traceEnter("not")
+
+		notSchema := vm.registry.GetIndex(schema.Not.Schema)
+
+		vm.pushSchemaToken("not")
+		notErrors, _, err := vm.interpretPseudo(notSchema, instance)
+		if err != nil {
+			return err
+		}
+		vm.popSchemaToken()
+
+		if !notErrors {
+			vm.pushSchemaToken("not")
+			if err := vm.reportError(); err != nil {
+				return err
+			}
+			vm.popSchemaToken()
+		}
+
+		vm.traceExit()
+	}
+
+	if schema.If.IsSet {
+		vm.traceEnter("if")
+
+		ifSchema := vm.registry.GetIndex(schema.If.Schema)
+
+		vm.pushSchemaToken("if")
+		ifErrors, _, err := vm.interpretPseudo(ifSchema, instance)
+		if err != nil {
+			return err
+		}
+		vm.popSchemaToken()
+
+		vm.traceExit()
+
+		if !ifErrors {
+			if schema.Then.IsSet {
+				vm.traceEnter("then")
+
+				thenSchema := vm.registry.GetIndex(schema.Then.Schema)
+
+				vm.pushSchemaToken("then")
+				if err := vm.interpretSchema(thenSchema, instance); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+
+				vm.traceExit()
+			}
+		} else {
+			if schema.Else.IsSet {
+				vm.traceEnter("else")
+
+				elseSchema := vm.registry.GetIndex(schema.Else.Schema)
+
+				vm.pushSchemaToken("else")
+				if err := vm.interpretSchema(elseSchema, instance); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+
+				vm.traceExit()
+			}
+		}
+	}
+
+	if schema.Const.IsSet {
+		vm.traceEnter("const")
+
+		// a single comparison gains nothing from hashing, as jsonEqual quits at
+		// the first difference it finds
+		if !jsonEqual(instance, schema.Const.Value) {
+			vm.pushSchemaToken("const")
+			if err := vm.reportError(); err != nil {
+				return err
+			}
+			vm.popSchemaToken()
+		}
+
+		vm.traceExit()
+	}
+
+	if schema.Enum.IsSet {
+		vm.traceEnter("enum")
+
+		if !schema.Enum.Set.contains(instance) {
+			vm.pushSchemaToken("enum")
+			if err := vm.reportError(); err != nil {
+				return err
+			}
+			vm.popSchemaToken()
+		}
+
+		vm.traceExit()
+	}
+
+	if schema.AllOf.IsSet {
+		vm.traceEnter("allOf")
+
+		vm.pushSchemaToken("allOf")
+
+		for i, index := range schema.AllOf.Schemas {
+			allOfSchema := vm.registry.GetIndex(index)
+
+			vm.pushSchemaIndex(i)
+			if err := vm.interpretSchema(allOfSchema, instance); err != nil {
+				return err
+			}
+			vm.popSchemaToken()
+		}
+
+		vm.popSchemaToken()
+
+		vm.traceExit()
+	}
+
+	if schema.AnyOf.IsSet {
+		vm.traceEnter("anyOf")
+
+		anyOfOk := false
+		anyOfCauses := []ValidationError(nil)
+
+		vm.pushSchemaToken("anyOf")
+		for i, index := range schema.AnyOf.Schemas {
+			anyOfSchema := vm.registry.GetIndex(index)
+
+			vm.pushSchemaIndex(i)
+			anyOfErrors, anyOfSchemaErrors, err := vm.interpretPseudo(anyOfSchema, instance)
+			if err != nil {
+				return err
+			}
+			vm.popSchemaToken()
+
+			if !anyOfErrors {
+				anyOfOk = true
+				break
+			}
+
+			anyOfCauses = append(anyOfCauses, anyOfSchemaErrors...)
+		}
+		vm.popSchemaToken()
+
+		if !anyOfOk {
+			vm.pushSchemaToken("anyOf")
+			if err := vm.reportErrorWithCauses(anyOfCauses, nil); err != nil {
+				return err
+			}
+			vm.popSchemaToken()
+		}
+
+		vm.traceExit()
+	}
+
+	if schema.OneOf.IsSet {
+		vm.traceEnter("oneOf")
+
+		// the alternative picked by a discriminator property is evaluated alone
+		oneOfDispatched := false
+		if schema.Program.oneOf != nil {
+			if i, ok := schema.Program.oneOf.alternative(instance); ok {
+				oneOfDispatched = true
+
+				vm.pushSchemaToken("oneOf")
+				vm.pushSchemaIndex(i)
+				if err := vm.interpretSchema(vm.registry.GetIndex(schema.OneOf.Schemas[i]), instance); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+				vm.popSchemaToken()
+			}
+		}
+
+		if !oneOfDispatched {
+			oneOfCauses := []ValidationError(nil)
+			oneOfMatches := []int(nil)
+
+			vm.pushSchemaToken("oneOf")
+			for i, index := range schema.OneOf.Schemas {
+				oneOfSchema := vm.registry.GetIndex(index)
+
+				vm.pushSchemaIndex(i)
+				oneOfErrors, oneOfSchemaErrors, err := vm.interpretPseudo(oneOfSchema, instance)
+				if err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+
+				if oneOfErrors {
+					oneOfCauses = append(oneOfCauses, oneOfSchemaErrors...)
+				} else {
+					oneOfMatches = append(oneOfMatches, i)
+				}
+			}
+			vm.popSchemaToken()
+
+			if len(oneOfMatches) != 1 {
+				if len(oneOfMatches) > 1 {
+					// the errors of the other subschemas are not why oneOf failed
+					oneOfCauses = nil
+				} else {
+					oneOfMatches = nil
+				}
+
+				vm.pushSchemaToken("oneOf")
+				if err := vm.reportErrorWithCauses(oneOfCauses, oneOfMatches); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+		}
+
+		vm.traceExit()
+	}
+
+	switch val := instance.(type) {
+	case nil:
+		if schema.Type.IsSet {
+			vm.traceEnter("type")
+
+			if !schema.Type.contains(jsonTypeNull) {
+				vm.pushSchemaToken("type")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+	case bool:
+		if schema.Type.IsSet {
+			vm.traceEnter("type")
+
+			if !schema.Type.contains(jsonTypeBoolean) {
+				vm.pushSchemaToken("type")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+	case float64:
+		if schema.Type.IsSet {
+			vm.traceEnter("type")
+
+			typeOk := false
+			if schema.Type.contains(jsonTypeInteger) {
+				typeOk = val == math.Round(val)
+			}
+
+			if !typeOk && !schema.Type.contains(jsonTypeNumber) {
+				vm.pushSchemaToken("type")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.MultipleOf.IsSet {
+			vm.traceEnter("multipleOf")
+
+			if math.Abs(math.Mod(val, schema.MultipleOf.Value)) > epsilon {
+				vm.pushSchemaToken("multipleOf")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.Maximum.IsSet {
+			vm.traceEnter("maximum")
+
+			if val > schema.Maximum.Value {
+				vm.pushSchemaToken("maximum")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.Minimum.IsSet {
+			vm.traceEnter("minimum")
+
+			if val < schema.Minimum.Value {
+				vm.pushSchemaToken("minimum")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.ExclusiveMaximum.IsSet {
+			vm.traceEnter("exclusiveMaximum")
+
+			if val > schema.ExclusiveMaximum.Value-epsilon {
+				vm.pushSchemaToken("exclusiveMaximum")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.ExclusiveMinimum.IsSet {
+			vm.traceEnter("exclusiveMinimum")
+
+			if val < schema.ExclusiveMinimum.Value+epsilon {
+				vm.pushSchemaToken("exclusiveMinimum")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+	case string:
+		if schema.Type.IsSet {
+			vm.traceEnter("type")
+
+			if !schema.Type.contains(jsonTypeString) {
+				vm.pushSchemaToken("type")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.MaxLength.IsSet {
+			vm.traceEnter("maxLength")
+
+			if utf8.RuneCountInString(val) > schema.MaxLength.Value {
+				vm.pushSchemaToken("maxLength")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.MinLength.IsSet {
+			vm.traceEnter("minLength")
+
+			if utf8.RuneCountInString(val) < schema.MinLength.Value {
+				vm.pushSchemaToken("minLength")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.Pattern.IsSet {
+			vm.traceEnter("pattern")
+
+			if !schema.Pattern.Value.MatchString(val) {
+				vm.pushSchemaToken("pattern")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+	case []interface{}:
+		if schema.Type.IsSet {
+			vm.traceEnter("type")
+
+			if !schema.Type.contains(jsonTypeArray) {
+				vm.pushSchemaToken("type")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.MaxItems.IsSet {
+			vm.traceEnter("maxItems")
+
+			if len(val) > schema.MaxItems.Value {
+				vm.pushSchemaToken("maxItems")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.MinItems.IsSet {
+			vm.traceEnter("minItems")
+
+			if len(val) < schema.MinItems.Value {
+				vm.pushSchemaToken("minItems")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.UniqueItems.IsSet && schema.UniqueItems.Value {
+			vm.traceEnter("uniqueItems")
+
+			if !jsonUnique(val) {
+				vm.pushSchemaToken("uniqueItems")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.Contains.IsSet {
+			vm.traceEnter("contains")
+
+			containsOk := false
+			containsCauses := []ValidationError(nil)
+
+			vm.pushSchemaToken("contains")
+			for i, elem := range val {
+				containsSchema := vm.registry.GetIndex(schema.Contains.Schema)
+
+				vm.pushInstanceIndex(i)
+				containsErrors, containsElemErrors, err := vm.interpretPseudo(containsSchema, elem)
+				if err != nil {
+					return err
+				}
+				vm.popInstanceToken()
+
+				if !containsErrors {
+					containsOk = true
+					break
+				}
+
+				containsCauses = append(containsCauses, containsElemErrors...)
+			}
+			vm.popSchemaToken()
+
+			if !containsOk {
+				vm.pushSchemaToken("contains")
+				if err := vm.reportErrorWithCauses(containsCauses, nil); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.Items.IsSet {
+			vm.traceEnter("items")
+
+			if schema.Items.IsSingle {
+				vm.pushSchemaToken("items")
+
+				itemSchema := vm.registry.GetIndex(schema.Items.Schemas[0])
+				for i, elem := range val {
+					vm.pushInstanceIndex(i)
+					if err := vm.interpretSchema(itemSchema, elem); err != nil {
+						return err
+					}
+					vm.popInstanceToken()
+				}
+				vm.popSchemaToken()
+			} else {
+				vm.pushSchemaToken("items")
+				for i := 0; i < len(schema.Items.Schemas) && i < len(val); i++ {
+					itemSchema := vm.registry.GetIndex(schema.Items.Schemas[i])
+
+					vm.pushInstanceIndex(i)
+					vm.pushSchemaIndex(i)
+					if err := vm.interpretSchema(itemSchema, val[i]); err != nil {
+						return err
+					}
+					vm.popInstanceToken()
+					vm.popSchemaToken()
+				}
+				vm.popSchemaToken()
+
+				if schema.AdditionalItems.IsSet {
+					vm.traceEnter("additionalItems")
+
+					vm.pushSchemaToken("additionalItems")
+
+					additionalItemSchema := vm.registry.GetIndex(schema.AdditionalItems.Schema)
+					for i := len(schema.Items.Schemas); i < len(val); i++ {
+						vm.pushInstanceIndex(i)
+						if err := vm.interpretSchema(additionalItemSchema, val[i]); err != nil {
+							return err
+						}
+						vm.popInstanceToken()
+					}
+					vm.popSchemaToken()
+
+					vm.traceExit()
+				}
+			}
+
+			vm.traceExit()
+		}
+	case map[string]interface{}:
+		if schema.Type.IsSet {
+			vm.traceEnter("type")
+
+			if !schema.Type.contains(jsonTypeObject) {
+				vm.pushSchemaToken("type")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.MaxProperties.IsSet {
+			vm.traceEnter("maxProperties")
+
+			if len(val) > schema.MaxProperties.Value {
+				vm.pushSchemaToken("maxProperties")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.MinProperties.IsSet {
+			vm.traceEnter("minProperties")
+
+			if len(val) < schema.MinProperties.Value {
+				vm.pushSchemaToken("minProperties")
+				if err := vm.reportError(); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+			}
+
+			vm.traceExit()
+		}
+
+		if schema.Required.IsSet {
+			vm.traceEnter("required")
+
+			vm.pushSchemaToken("required")
+
+			for i, property := range schema.Required.Properties {
+				if _, ok := val[property]; !ok {
+					vm.pushSchemaIndex(i)
+					if err := vm.reportError(); err != nil {
+						return err
+					}
+					vm.popSchemaToken()
+				}
+			}
+
+			vm.popSchemaToken()
+
+			vm.traceExit()
+		}
+
+		// each keyword is a single step of the trace, suspended while the others
+		// are evaluated for the same property
+		var properties, patternProperties, additionalProperties traceStep
+		if schema.Properties.IsSet {
+			vm.traceEnter("properties")
+			properties = vm.traceSuspend()
+		}
+
+		if schema.PatternProperties.IsSet {
+			vm.traceEnter("patternProperties")
+			patternProperties = vm.traceSuspend()
+		}
+
+		if schema.AdditionalProperties.IsSet {
+			vm.traceEnter("additionalProperties")
+			additionalProperties = vm.traceSuspend()
+		}
+
+		for _, key := range sortedKeys(val) {
+			value := val[key]
+			isAdditional := true
+
+			if schema.Properties.IsSet {
+				if index, ok := schema.Properties.Schemas[key]; ok {
+					isAdditional = false
+					propertySchema := vm.registry.GetIndex(index)
+
+					vm.traceResume(properties)
+					vm.pushSchemaToken("properties")
+					vm.pushSchemaToken(key)
+					vm.pushInstanceToken(key)
+					if err := vm.interpretSchema(propertySchema, value); err != nil {
+						return err
+					}
+					vm.popInstanceToken()
+					vm.popSchemaToken()
+					vm.popSchemaToken()
+					properties = vm.traceSuspend()
+				}
+			}
+
+			if schema.PatternProperties.IsSet {
+				for _, pattern := range sortedPatternProperties(schema.PatternProperties.Schemas) {
+					index := schema.PatternProperties.Schemas[pattern]
+					if pattern.MatchString(key) {
+						isAdditional = false
+						propertySchema := vm.registry.GetIndex(index)
+
+						vm.traceResume(patternProperties)
+						vm.pushSchemaToken("patternProperties")
+						vm.pushSchemaToken(pattern.String())
+						vm.pushInstanceToken(key)
+						if err := vm.interpretSchema(propertySchema, value); err != nil {
+							return err
+						}
+						vm.popInstanceToken()
+						vm.popSchemaToken()
+						vm.popSchemaToken()
+						patternProperties = vm.traceSuspend()
+					}
+				}
+			}
+
+			if schema.AdditionalProperties.IsSet && isAdditional {
+				propertySchema := vm.registry.GetIndex(schema.AdditionalProperties.Schema)
+
+				vm.traceResume(additionalProperties)
+				vm.pushSchemaToken("additionalProperties")
+				vm.pushInstanceToken(key)
+				if err := vm.interpretSchema(propertySchema, value); err != nil {
+					return err
+				}
+				vm.popInstanceToken()
+				vm.popSchemaToken()
+				additionalProperties = vm.traceSuspend()
+			}
+		}
+
+		if schema.Properties.IsSet {
+			vm.traceResume(properties)
+			vm.traceExit()
+		}
+
+		if schema.PatternProperties.IsSet {
+			vm.traceResume(patternProperties)
+			vm.traceExit()
+		}
+
+		if schema.AdditionalProperties.IsSet {
+			vm.traceResume(additionalProperties)
+			vm.traceExit()
+		}
+
+		if schema.Dependencies.IsSet {
+			vm.traceEnter("dependencies")
+
+			vm.pushSchemaToken("dependencies")
+
+			for _, key := range sortedDependencies(schema.Dependencies.Deps) {
+				dep := schema.Dependencies.Deps[key]
+				vm.pushSchemaToken(key)
+
+				if value, ok := val[key]; ok {
+					if dep.IsSchema {
+						propertySchema := vm.registry.GetIndex(dep.Schema)
+
+						vm.pushInstanceToken(key)
+						if err := vm.interpretSchema(propertySchema, value); err != nil {
+							return err
+						}
+						vm.popInstanceToken()
+					} else {
+						for i, property := range dep.Properties {
+							if _, ok := val[property]; !ok {
+								vm.pushSchemaIndex(i)
+								if err := vm.reportError(); err != nil {
+									return err
+								}
+								vm.popSchemaToken()
+							}
+						}
+					}
+				}
+
+				vm.popSchemaToken()
+			}
+
+			vm.popSchemaToken()
+
+			vm.traceExit()
+		}
+
+		if schema.PropertyNames.IsSet {
+			vm.traceEnter("propertyNames")
+
+			vm.pushSchemaToken("propertyNames")
+
+			propertyNameSchema := vm.registry.GetIndex(schema.PropertyNames.Schema)
+			for _, key := range sortedKeys(val) {
+				vm.pushInstanceToken(key)
+				if err := vm.interpretSchema(propertyNameSchema, key); err != nil {
+					return err
+				}
+				vm.popInstanceToken()
+			}
+
+			vm.popSchemaToken()
+
+			vm.traceExit()
+		}
+	default:
+		// TODO a better error here
+		panic("unexpected non-json input")
+	}
+
+	for _, keyword := range schema.Keywords {
+		reporter := KeywordReporter{vm: vm}
+
+		vm.traceEnter(keyword.Name)
+		vm.pushSchemaToken(keyword.Name)
+		if err := keyword.Evaluator(instance, &reporter); err != nil {
+			return err
+		}
+		vm.popSchemaToken()
+		vm.traceExit()
+	}
+
+	vm.traceExit()
+	return nil
+}
+
+// interpretPseudo is the interpreter's counterpart to pseudoExec.
+func (vm *vm) interpretPseudo(schema *schema, instance interface{}) (bool, []ValidationError, error) {
+	prevErrors := vm.errors
+	vm.errors = vmErrors{
+		hasErrors: false,
+		errors:    []ValidationError{},
+	}
+
+	vm.pseudoDepth++
+	if err := vm.interpretSchema(schema, instance); err != nil {
+		return false, nil, err
+	}
+	vm.pseudoDepth--
+
+	pseudoErrors := vm.errors
+	vm.errors = prevErrors
+
+	return pseudoErrors.hasErrors, pseudoErrors.errors, nil
+}
@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"net/url"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// KeywordCompiler compiles the value of a custom keyword. The parser calls it
+// once for every schema in which the keyword appears, passing the raw value of
+// the keyword and the location of the keyword within its schema.
+//
+// If a KeywordCompiler returns an error, the schema is rejected with a
+// SchemaError describing the keyword's location.
+type KeywordCompiler func(value interface{}, location KeywordLocation) (KeywordEvaluator, error)
+
+// KeywordEvaluator evaluates a compiled custom keyword against an instance.
+//
+// Problems with the instance are reported through the given KeywordReporter.
+// Any error returned by the reporter must be returned by the evaluator; other
+// errors abort validation and are returned to the caller of Validate.
+type KeywordEvaluator func(instance interface{}, reporter *KeywordReporter) error
+
+// KeywordLocation describes where a custom keyword appears.
+type KeywordLocation struct {
+	// The URI of the schema containing the keyword.
+	URI url.URL
+
+	// A JSON Pointer to the keyword within the schema.
+	Path jsonpointer.Ptr
+}
+
+// KeywordReporter reports validation errors on behalf of a custom keyword.
+//
+// Errors are reported in the same way as errors from the built-in keywords: the
+// schema path of each error points to the custom keyword, and errors reported
+// within "anyOf", "oneOf", "not" and similar keywords are treated as failures
+// of the relevant subschema rather than being returned directly.
+type KeywordReporter struct {
+	vm *vm
+}
+
+// Report reports that the instance being evaluated is invalid.
+func (r *KeywordReporter) Report() error {
+	return r.vm.reportError()
+}
+
+// ReportAt reports that a part of the instance being evaluated is invalid. The
+// given tokens are relative to the instance being evaluated; for instance,
+// ReportAt("foo") reports an error at the "foo" property of an object.
+func (r *KeywordReporter) ReportAt(tokens ...string) error {
+	for _, token := range tokens {
+		r.vm.pushInstanceToken(token)
+	}
+
+	if err := r.vm.reportError(); err != nil {
+		return err
+	}
+
+	for range tokens {
+		r.vm.popInstanceToken()
+	}
+
+	return nil
+}
+
+type schemaKeyword struct {
+	Name      string
+	Evaluator KeywordEvaluator
+}
@@ -0,0 +1,153 @@
+package jsonschema
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucarion/json-pointer"
+)
+
+var testKeywords = map[string]KeywordCompiler{
+	"x-currency-code": func(value interface{}, location KeywordLocation) (KeywordEvaluator, error) {
+		if value != true {
+			return nil, errors.New("must be true")
+		}
+
+		return func(instance interface{}, reporter *KeywordReporter) error {
+			if str, ok := instance.(string); ok && (len(str) != 3 || str != strings.ToUpper(str)) {
+				return reporter.Report()
+			}
+
+			return nil
+		}, nil
+	},
+	"x-less-than": func(value interface{}, location KeywordLocation) (KeywordEvaluator, error) {
+		fields, ok := value.([]interface{})
+		if !ok || len(fields) != 2 {
+			return nil, errors.New("must be a pair of property names")
+		}
+
+		low, _ := fields[0].(string)
+		high, _ := fields[1].(string)
+
+		return func(instance interface{}, reporter *KeywordReporter) error {
+			object, ok := instance.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+
+			lowValue, lowOk := object[low].(float64)
+			highValue, highOk := object[high].(float64)
+			if lowOk && highOk && lowValue >= highValue {
+				return reporter.ReportAt(low)
+			}
+
+			return nil
+		}, nil
+	},
+}
+
+func TestValidatorKeywords(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"currency": map[string]interface{}{
+					"type":            "string",
+					"x-currency-code": true,
+				},
+				"range": map[string]interface{}{
+					"x-less-than": []interface{}{"min", "max"},
+				},
+				"either": map[string]interface{}{
+					"anyOf": []interface{}{
+						map[string]interface{}{"type": "null"},
+						map[string]interface{}{"x-currency-code": true},
+					},
+				},
+			},
+		},
+	}
+
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxStackDepth:  DefaultMaxStackDepth,
+		StrictKeywords: true,
+		Keywords:       testKeywords,
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate(map[string]interface{}{
+		"currency": "USD",
+		"range":    map[string]interface{}{"min": 1.0, "max": 2.0},
+		"either":   "EUR",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{}, result.Errors)
+
+	result, err = validator.Validate(map[string]interface{}{
+		"currency": "usd",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{"currency"}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"properties", "currency", "x-currency-code"}},
+		},
+	}, result.Errors)
+
+	result, err = validator.Validate(map[string]interface{}{
+		"range": map[string]interface{}{"min": 3.0, "max": 2.0},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{"range", "min"}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"properties", "range", "x-less-than"}},
+		},
+	}, result.Errors)
+
+	result, err = validator.Validate(map[string]interface{}{
+		"either": "euro",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{"either"}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"properties", "either", "anyOf"}},
+			Causes: []ValidationError{
+				{
+					InstancePath: jsonpointer.Ptr{Tokens: []string{"either"}},
+					SchemaPath:   jsonpointer.Ptr{Tokens: []string{"properties", "either", "anyOf", "0", "type"}},
+				},
+				{
+					InstancePath: jsonpointer.Ptr{Tokens: []string{"either"}},
+					SchemaPath:   jsonpointer.Ptr{Tokens: []string{"properties", "either", "anyOf", "1", "x-currency-code"}},
+				},
+			},
+		},
+	}, result.Errors)
+}
+
+func TestValidatorKeywordsCompileError(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"$id": "http://example.com/money",
+			"items": map[string]interface{}{
+				"x-currency-code": "yes",
+			},
+		},
+	}
+
+	_, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		Keywords:      testKeywords,
+	})
+
+	assert.Equal(t, SchemaError{
+		URI:     url.URL{Scheme: "http", Host: "example.com", Path: "/money"},
+		Path:    jsonpointer.Ptr{Tokens: []string{"items", "x-currency-code"}},
+		Message: `invalid value for keyword "x-currency-code": must be true`,
+	}, err)
+}
@@ -0,0 +1,409 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// LintWarning is a likely mistake in a schema, found by Lint. Schemas with
+// such mistakes are valid, but probably do not mean what their authors
+// intended.
+type LintWarning struct {
+	// The URI of the schema containing the mistake.
+	URI url.URL
+
+	// A JSON Pointer to the part of the schema which is likely a mistake.
+	Path jsonpointer.Ptr
+
+	// Check names the check which found the mistake, such as
+	// "unused-definition".
+	Check string
+
+	// A human-readable description of the mistake.
+	Message string
+
+	// Where the part of the schema appears in its source. It is only known for
+	// schemas given to NewValidatorFromJSON.
+	Position Position
+}
+
+// String describes the warning in the same form as SchemaError.
+func (w LintWarning) String() string {
+	uri := w.URI
+	uri.Fragment = ""
+
+	if w.Position.IsValid() {
+		return fmt.Sprintf("%s: %s#%s: %s (%s)", w.Position.String(), uri.String(), w.Path.String(), w.Message, w.Check)
+	}
+
+	return fmt.Sprintf("%s#%s: %s (%s)", uri.String(), w.Path.String(), w.Message, w.Check)
+}
+
+// Lint inspects the schemas of the Validator for common authoring mistakes.
+// The checks are:
+//
+//	required-undeclared   "required" names a property which
+//	                      "additionalProperties": false forbids
+//	min-exceeds-max       a lower bound, such as "minimum", exceeds its upper
+//	                      bound, such as "maximum"
+//	type-mismatch         a keyword only applies to values "type" excludes,
+//	                      such as "minLength" alongside "type": "integer"
+//	missing-if            "then" or "else" appears without "if"
+//	unused-definition     a definition is never referred to
+//	enum-type             a value in "enum" is not of a type "type" allows
+//	unmatchable-pattern   a regular expression can never match anything
+//
+// Warnings are listed by schema URI, and then in the order in which they appear
+// in each source, if known, or else by path.
+func (v *Validator) Lint() []LintWarning {
+	l := linter{validator: v, warnings: []LintWarning{}}
+
+	used := map[url.URL]map[string]bool{}
+	for uri, index := range v.registry.schemas {
+		baseURI := uri
+		baseURI.Fragment = ""
+
+		if used[baseURI] == nil {
+			used[baseURI] = map[string]bool{}
+		}
+
+		used[baseURI][uri.Fragment] = true
+
+		// fragments are known to be valid JSON Pointers, as they were parsed
+		ptr, _ := jsonpointer.New(uri.Fragment)
+		l.lintSchema(baseURI, ptr.Tokens, v.registry.GetIndex(index))
+	}
+
+	for baseURI, rawSchema := range v.rawSchemas {
+		l.lintDefinitions(baseURI, rawSchema, used[baseURI])
+	}
+
+	warnings := l.warnings
+	sort.SliceStable(warnings, func(i, j int) bool {
+		a, b := warnings[i], warnings[j]
+		if a.URI.String() != b.URI.String() {
+			return a.URI.String() < b.URI.String()
+		}
+
+		if a.Position.IsValid() && b.Position.IsValid() && a.Position.Offset != b.Position.Offset {
+			return a.Position.Offset < b.Position.Offset
+		}
+
+		if a.Path.String() != b.Path.String() {
+			return a.Path.String() < b.Path.String()
+		}
+
+		return a.Check < b.Check
+	})
+
+	return warnings
+}
+
+type linter struct {
+	validator *Validator
+	warnings  []LintWarning
+}
+
+func (l *linter) warn(uri url.URL, tokens []string, keywords []string, check, format string, args ...interface{}) {
+	path := jsonpointer.Ptr{Tokens: append(append([]string{}, tokens...), keywords...)}
+	position, _ := l.validator.SchemaPosition(uri, path)
+
+	l.warnings = append(l.warnings, LintWarning{
+		URI:      uri,
+		Path:     path,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+		Position: position,
+	})
+}
+
+func (l *linter) lintSchema(uri url.URL, tokens []string, s *schema) {
+	l.lintRequired(uri, tokens, s)
+	l.lintBounds(uri, tokens, s)
+	l.lintTypes(uri, tokens, s)
+	l.lintConditionals(uri, tokens, s)
+	l.lintEnum(uri, tokens, s)
+	l.lintPatterns(uri, tokens, s)
+}
+
+func (l *linter) lintRequired(uri url.URL, tokens []string, s *schema) {
+	if !s.Required.IsSet || !s.AdditionalProperties.IsSet {
+		return
+	}
+
+	additional := l.validator.registry.GetIndex(s.AdditionalProperties.Schema)
+	if !additional.Bool.IsSet || additional.Bool.Value {
+		return
+	}
+
+	for i, property := range s.Required.Properties {
+		if _, ok := s.Properties.Schemas[property]; ok {
+			continue
+		}
+
+		matched := false
+		for pattern := range s.PatternProperties.Schemas {
+			matched = matched || pattern.MatchString(property)
+		}
+
+		if !matched {
+			l.warn(uri, tokens, []string{"required", strconv.Itoa(i)}, "required-undeclared",
+				"property %q is required, but \"additionalProperties\" forbids it", property)
+		}
+	}
+}
+
+func (l *linter) lintBounds(uri url.URL, tokens []string, s *schema) {
+	bounds := []struct {
+		isSet      bool
+		min, max   float64
+		minKeyword string
+		maxKeyword string
+		inclusive  bool
+	}{
+		{s.Minimum.IsSet && s.Maximum.IsSet, s.Minimum.Value, s.Maximum.Value, "minimum", "maximum", true},
+		{s.ExclusiveMinimum.IsSet && s.ExclusiveMaximum.IsSet, s.ExclusiveMinimum.Value, s.ExclusiveMaximum.Value, "exclusiveMinimum", "exclusiveMaximum", false},
+		{s.MinLength.IsSet && s.MaxLength.IsSet, float64(s.MinLength.Value), float64(s.MaxLength.Value), "minLength", "maxLength", true},
+		{s.MinItems.IsSet && s.MaxItems.IsSet, float64(s.MinItems.Value), float64(s.MaxItems.Value), "minItems", "maxItems", true},
+		{s.MinProperties.IsSet && s.MaxProperties.IsSet, float64(s.MinProperties.Value), float64(s.MaxProperties.Value), "minProperties", "maxProperties", true},
+	}
+
+	for _, bound := range bounds {
+		if !bound.isSet {
+			continue
+		}
+
+		if bound.min > bound.max || (!bound.inclusive && bound.min == bound.max) {
+			l.warn(uri, tokens, []string{bound.minKeyword}, "min-exceeds-max",
+				"%q of %v leaves no room below %q of %v", bound.minKeyword, bound.min, bound.maxKeyword, bound.max)
+		}
+	}
+}
+
+// typeKeywords lists the keywords which only apply to values of certain types.
+var typeKeywords = []struct {
+	types    []jsonType
+	name     string
+	keywords []string
+}{
+	{[]jsonType{jsonTypeString}, "strings", []string{"maxLength", "minLength", "pattern"}},
+	{[]jsonType{jsonTypeNumber, jsonTypeInteger}, "numbers", []string{"multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum"}},
+	{[]jsonType{jsonTypeArray}, "arrays", []string{"items", "additionalItems", "maxItems", "minItems", "uniqueItems", "contains"}},
+	{[]jsonType{jsonTypeObject}, "objects", []string{"maxProperties", "minProperties", "required", "properties", "patternProperties", "additionalProperties", "dependencies", "propertyNames"}},
+}
+
+func (l *linter) lintTypes(uri url.URL, tokens []string, s *schema) {
+	if !s.Type.IsSet {
+		return
+	}
+
+	set := map[string]bool{
+		"maxLength": s.MaxLength.IsSet, "minLength": s.MinLength.IsSet, "pattern": s.Pattern.IsSet,
+		"multipleOf": s.MultipleOf.IsSet, "maximum": s.Maximum.IsSet, "exclusiveMaximum": s.ExclusiveMaximum.IsSet,
+		"minimum": s.Minimum.IsSet, "exclusiveMinimum": s.ExclusiveMinimum.IsSet,
+		"items": s.Items.IsSet, "additionalItems": s.AdditionalItems.IsSet, "maxItems": s.MaxItems.IsSet,
+		"minItems": s.MinItems.IsSet, "uniqueItems": s.UniqueItems.IsSet, "contains": s.Contains.IsSet,
+		"maxProperties": s.MaxProperties.IsSet, "minProperties": s.MinProperties.IsSet, "required": s.Required.IsSet,
+		"properties": s.Properties.IsSet, "patternProperties": s.PatternProperties.IsSet,
+		"additionalProperties": s.AdditionalProperties.IsSet, "dependencies": s.Dependencies.IsSet,
+		"propertyNames": s.PropertyNames.IsSet,
+	}
+
+	for _, group := range typeKeywords {
+		applies := false
+		for _, typ := range group.types {
+			applies = applies || s.Type.contains(typ)
+		}
+
+		if applies {
+			continue
+		}
+
+		for _, keyword := range group.keywords {
+			if set[keyword] {
+				l.warn(uri, tokens, []string{keyword}, "type-mismatch",
+					"%q has no effect, as \"type\" does not allow %s", keyword, group.name)
+			}
+		}
+	}
+}
+
+func (l *linter) lintConditionals(uri url.URL, tokens []string, s *schema) {
+	if s.If.IsSet {
+		return
+	}
+
+	if s.Then.IsSet {
+		l.warn(uri, tokens, []string{"then"}, "missing-if", "\"then\" has no effect without \"if\"")
+	}
+
+	if s.Else.IsSet {
+		l.warn(uri, tokens, []string{"else"}, "missing-if", "\"else\" has no effect without \"if\"")
+	}
+}
+
+func (l *linter) lintEnum(uri url.URL, tokens []string, s *schema) {
+	if !s.Type.IsSet || !s.Enum.IsSet {
+		return
+	}
+
+	for i, value := range s.Enum.Values {
+		if !s.Type.accepts(value) {
+			encoded, _ := json.Marshal(value)
+			l.warn(uri, tokens, []string{"enum", strconv.Itoa(i)}, "enum-type",
+				"enum value %s is not of a type \"type\" allows", encoded)
+		}
+	}
+}
+
+func (l *linter) lintPatterns(uri url.URL, tokens []string, s *schema) {
+	if s.Pattern.IsSet && !canMatch(s.Pattern.Value.String()) {
+		l.warn(uri, tokens, []string{"pattern"}, "unmatchable-pattern",
+			"pattern %q can never match", s.Pattern.Value.String())
+	}
+
+	for pattern := range s.PatternProperties.Schemas {
+		if !canMatch(pattern.String()) {
+			l.warn(uri, tokens, []string{"patternProperties", pattern.String()}, "unmatchable-pattern",
+				"pattern %q can never match", pattern.String())
+		}
+	}
+}
+
+// lintDefinitions finds definitions within a schema which were never parsed,
+// and so are never referred to. used holds the fragments of the URIs of the
+// schema's parsed subschemas. Definitions within schemas which were never
+// parsed are not reported, as their parent already is.
+func (l *linter) lintDefinitions(uri url.URL, rawSchema interface{}, used map[string]bool) {
+	walkRawSchema(rawSchema, []string{}, func(tokens []string, input map[string]interface{}) {
+		if !used[jsonpointer.Ptr{Tokens: tokens}.String()] {
+			return
+		}
+
+		definitions, ok := input["definitions"].(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		for _, name := range sortedKeys(definitions) {
+			path := jsonpointer.Ptr{Tokens: append(append([]string{}, tokens...), "definitions", name)}.String()
+			if isUsed(used, path) {
+				continue
+			}
+
+			l.warn(uri, tokens, []string{"definitions", name}, "unused-definition",
+				"definition %q is never referred to", name)
+		}
+	})
+}
+
+// isUsed determines whether the subschema at path, or any subschema within it,
+// was parsed.
+func isUsed(used map[string]bool, path string) bool {
+	for fragment := range used {
+		if fragment == path || strings.HasPrefix(fragment, path+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canMatch determines whether a regular expression, in the syntax accepted by
+// the "pattern" keyword, can match any string. It catches empty character
+// classes, and anchors which can only be satisfied in the middle of a string.
+func canMatch(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return true
+	}
+
+	return canMatchRegexp(re.Simplify())
+}
+
+func canMatchRegexp(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return false
+	case syntax.OpCharClass:
+		return len(re.Rune) > 0
+	case syntax.OpCapture, syntax.OpPlus:
+		return canMatchRegexp(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min == 0 || canMatchRegexp(re.Sub[0])
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if canMatchRegexp(sub) {
+				return true
+			}
+		}
+
+		return false
+	case syntax.OpConcat:
+		consumed, ended := false, false
+		for _, sub := range re.Sub {
+			if !canMatchRegexp(sub) {
+				return false
+			}
+
+			if sub.Op == syntax.OpBeginText && consumed {
+				return false
+			}
+
+			if sub.Op == syntax.OpEndText {
+				ended = true
+			}
+
+			if minMatchLength(sub) > 0 {
+				if ended {
+					return false
+				}
+
+				consumed = true
+			}
+		}
+
+		return true
+	}
+
+	return true
+}
+
+// minMatchLength returns the fewest runes a regular expression can match.
+func minMatchLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return 1
+	case syntax.OpCapture, syntax.OpPlus:
+		return minMatchLength(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min * minMatchLength(re.Sub[0])
+	case syntax.OpConcat:
+		n := 0
+		for _, sub := range re.Sub {
+			n += minMatchLength(sub)
+		}
+
+		return n
+	case syntax.OpAlternate:
+		n := -1
+		for _, sub := range re.Sub {
+			if m := minMatchLength(sub); n == -1 || m < n {
+				n = m
+			}
+		}
+
+		return n
+	}
+
+	return 0
+}
@@ -0,0 +1,98 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	testCases := []struct {
+		schema   string
+		warnings []string
+	}{
+		{`{"type": "string", "minLength": 1, "pattern": "^a"}`, []string{}},
+		{
+			`{"properties": {"a": true}, "patternProperties": {"^x-": true}, "additionalProperties": false, "required": ["a", "x-b", "c"]}`,
+			[]string{`#/required/2: property "c" is required, but "additionalProperties" forbids it (required-undeclared)`},
+		},
+		{
+			`{"minimum": 5, "maximum": 3, "exclusiveMinimum": 1, "exclusiveMaximum": 1, "minLength": 2, "maxLength": 2}`,
+			[]string{
+				`#/exclusiveMinimum: "exclusiveMinimum" of 1 leaves no room below "exclusiveMaximum" of 1 (min-exceeds-max)`,
+				`#/minimum: "minimum" of 5 leaves no room below "maximum" of 3 (min-exceeds-max)`,
+			},
+		},
+		{
+			`{"type": ["integer", "null"], "minimum": 0, "minLength": 1, "items": true, "properties": {}}`,
+			[]string{
+				`#/items: "items" has no effect, as "type" does not allow arrays (type-mismatch)`,
+				`#/minLength: "minLength" has no effect, as "type" does not allow strings (type-mismatch)`,
+				`#/properties: "properties" has no effect, as "type" does not allow objects (type-mismatch)`,
+			},
+		},
+		{
+			`{"then": true, "else": {"if": true, "then": true}}`,
+			[]string{
+				`#/else: "else" has no effect without "if" (missing-if)`,
+				`#/then: "then" has no effect without "if" (missing-if)`,
+			},
+		},
+		{
+			`{"type": "string", "enum": ["a", 1, null]}`,
+			[]string{
+				`#/enum/1: enum value 1 is not of a type "type" allows (enum-type)`,
+				`#/enum/2: enum value null is not of a type "type" allows (enum-type)`,
+			},
+		},
+		{
+			`{"pattern": "a^b", "patternProperties": {"[^\\x00-\\x{10FFFF}]": true, "$x": true, "^a|b$": true, "a$\\n?": true}}`,
+			[]string{
+				`#/pattern: pattern "a^b" can never match (unmatchable-pattern)`,
+				`#/patternProperties/$x: pattern "$x" can never match (unmatchable-pattern)`,
+				`#/patternProperties/[^\x00-\x{10FFFF}]: pattern "[^\\x00-\\x{10FFFF}]" can never match (unmatchable-pattern)`,
+			},
+		},
+		{
+			`{
+				"definitions": {
+					"used": {"$ref": "#/definitions/partly/properties/a"},
+					"partly": {"properties": {"a": true}},
+					"unused": {"definitions": {"nested": true}}
+				},
+				"properties": {"x": {"$ref": "#/definitions/used"}}
+			}`,
+			[]string{`#/definitions/unused: definition "unused" is never referred to (unused-definition)`},
+		},
+	}
+
+	for _, tt := range testCases {
+		validator, err := NewValidator([]interface{}{mustDecode(t, tt.schema)})
+		assert.NoError(t, err, tt.schema)
+
+		warnings := []string{}
+		for _, warning := range validator.Lint() {
+			warnings = append(warnings, warning.String())
+		}
+
+		assert.Equal(t, tt.warnings, warnings, tt.schema)
+	}
+}
+
+func TestLintPositions(t *testing.T) {
+	validator, err := NewValidatorFromJSON([]Source{{
+		Name: "schema.json",
+		Data: []byte(`{"$id": "http://example.com/a", "then": true, "definitions": {"x": true}}`),
+	}}, ValidatorConfig{MaxStackDepth: DefaultMaxStackDepth})
+	assert.NoError(t, err)
+
+	warnings := []string{}
+	for _, warning := range validator.Lint() {
+		warnings = append(warnings, warning.String())
+	}
+
+	assert.Equal(t, []string{
+		`schema.json:1:41: http://example.com/a#/then: "then" has no effect without "if" (missing-if)`,
+		`schema.json:1:68: http://example.com/a#/definitions/x: definition "x" is never referred to (unused-definition)`,
+	}, warnings)
+}
@@ -0,0 +1,367 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// draft07URI is the URI of the draft-07 meta-schema. It is also the dialect
+// assumed for schemas that lack a "$schema" keyword.
+var draft07URI = url.URL{Scheme: "http", Host: "json-schema.org", Path: "/draft-07/schema"}
+
+// draft07Keywords are the keywords defined by draft-07. In strict mode, any
+// other keyword is rejected.
+var draft07Keywords = map[string]bool{
+	"$id": true, "$schema": true, "$ref": true, "$comment": true,
+	"title": true, "description": true, "default": true, "readOnly": true,
+	"writeOnly": true, "examples": true, "multipleOf": true, "maximum": true,
+	"exclusiveMaximum": true, "minimum": true, "exclusiveMinimum": true,
+	"maxLength": true, "minLength": true, "pattern": true,
+	"additionalItems": true, "items": true, "maxItems": true, "minItems": true,
+	"uniqueItems": true, "contains": true, "maxProperties": true,
+	"minProperties": true, "required": true, "additionalProperties": true,
+	"definitions": true, "properties": true, "patternProperties": true,
+	"dependencies": true, "propertyNames": true, "const": true, "enum": true,
+	"type": true, "format": true, "contentMediaType": true,
+	"contentEncoding": true, "if": true, "then": true, "else": true,
+	"allOf": true, "anyOf": true, "oneOf": true, "not": true,
+}
+
+// draft07MetaSchema is the draft-07 meta-schema, as published at draft07URI.
+const draft07MetaSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "http://json-schema.org/draft-07/schema#",
+  "title": "Core schema meta-schema",
+  "definitions": {
+    "schemaArray": {
+      "type": "array",
+      "minItems": 1,
+      "items": { "$ref": "#" }
+    },
+    "nonNegativeInteger": {
+      "type": "integer",
+      "minimum": 0
+    },
+    "nonNegativeIntegerDefault0": {
+      "allOf": [
+        { "$ref": "#/definitions/nonNegativeInteger" },
+        { "default": 0 }
+      ]
+    },
+    "simpleTypes": {
+      "enum": ["array", "boolean", "integer", "null", "number", "object", "string"]
+    },
+    "stringArray": {
+      "type": "array",
+      "items": { "type": "string" },
+      "uniqueItems": true,
+      "default": []
+    }
+  },
+  "type": ["object", "boolean"],
+  "properties": {
+    "$id": { "type": "string", "format": "uri-reference" },
+    "$schema": { "type": "string", "format": "uri" },
+    "$ref": { "type": "string", "format": "uri-reference" },
+    "$comment": { "type": "string" },
+    "title": { "type": "string" },
+    "description": { "type": "string" },
+    "default": true,
+    "readOnly": { "type": "boolean", "default": false },
+    "writeOnly": { "type": "boolean", "default": false },
+    "examples": { "type": "array", "items": true },
+    "multipleOf": { "type": "number", "exclusiveMinimum": 0 },
+    "maximum": { "type": "number" },
+    "exclusiveMaximum": { "type": "number" },
+    "minimum": { "type": "number" },
+    "exclusiveMinimum": { "type": "number" },
+    "maxLength": { "$ref": "#/definitions/nonNegativeInteger" },
+    "minLength": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+    "pattern": { "type": "string", "format": "regex" },
+    "additionalItems": { "$ref": "#" },
+    "items": {
+      "anyOf": [{ "$ref": "#" }, { "$ref": "#/definitions/schemaArray" }],
+      "default": true
+    },
+    "maxItems": { "$ref": "#/definitions/nonNegativeInteger" },
+    "minItems": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+    "uniqueItems": { "type": "boolean", "default": false },
+    "contains": { "$ref": "#" },
+    "maxProperties": { "$ref": "#/definitions/nonNegativeInteger" },
+    "minProperties": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+    "required": { "$ref": "#/definitions/stringArray" },
+    "additionalProperties": { "$ref": "#" },
+    "definitions": {
+      "type": "object",
+      "additionalProperties": { "$ref": "#" },
+      "default": {}
+    },
+    "properties": {
+      "type": "object",
+      "additionalProperties": { "$ref": "#" },
+      "default": {}
+    },
+    "patternProperties": {
+      "type": "object",
+      "additionalProperties": { "$ref": "#" },
+      "propertyNames": { "format": "regex" },
+      "default": {}
+    },
+    "dependencies": {
+      "type": "object",
+      "additionalProperties": {
+        "anyOf": [{ "$ref": "#" }, { "$ref": "#/definitions/stringArray" }]
+      }
+    },
+    "propertyNames": { "$ref": "#" },
+    "const": true,
+    "enum": { "type": "array", "items": true },
+    "type": {
+      "anyOf": [
+        { "$ref": "#/definitions/simpleTypes" },
+        {
+          "type": "array",
+          "items": { "$ref": "#/definitions/simpleTypes" },
+          "minItems": 1,
+          "uniqueItems": true
+        }
+      ]
+    },
+    "format": { "type": "string" },
+    "contentMediaType": { "type": "string" },
+    "contentEncoding": { "type": "string" },
+    "if": { "$ref": "#" },
+    "then": { "$ref": "#" },
+    "else": { "$ref": "#" },
+    "allOf": { "$ref": "#/definitions/schemaArray" },
+    "anyOf": { "$ref": "#/definitions/schemaArray" },
+    "oneOf": { "$ref": "#/definitions/schemaArray" },
+    "not": { "$ref": "#" }
+  },
+  "default": true
+}`
+
+var (
+	metaValidatorOnce sync.Once
+	metaValidator     Validator
+)
+
+// getMetaValidator returns a Validator holding every bundled meta-schema. It is
+// constructed on first use.
+func getMetaValidator() *Validator {
+	metaValidatorOnce.Do(func() {
+		var draft07 interface{}
+		if err := json.Unmarshal([]byte(draft07MetaSchema), &draft07); err != nil {
+			panic(err)
+		}
+
+		v, err := NewValidator([]interface{}{draft07})
+		if err != nil {
+			panic(err)
+		}
+
+		metaValidator = v
+	})
+
+	return &metaValidator
+}
+
+// checkSchemas runs the checks enabled by ValidateSchemas and StrictKeywords
+// against raw, not-yet-parsed schemas.
+func (v *Validator) checkSchemas(schemas []interface{}, sources []*SourceMap) error {
+	schemaErrors := []SchemaError{}
+
+	for i, schema := range schemas {
+		id := rawSchemaID(schema)
+		errs := []SchemaError{}
+
+		if v.validateSchemas {
+			metaErrs, err := checkMetaSchema(id, schema)
+			if err != nil {
+				return err
+			}
+
+			errs = append(errs, metaErrs...)
+		}
+
+		if v.strictKeywords {
+			errs = append(errs, checkKeywords(id, schema, v.keywords)...)
+		}
+
+		// for schemas with a known source, list errors in source order
+		if source := sources[i]; source != nil {
+			for j := range errs {
+				errs[j].Position, _ = source.Position(errs[j].Path)
+			}
+
+			sort.SliceStable(errs, func(i, j int) bool {
+				return errs[i].Position.Offset < errs[j].Position.Offset
+			})
+		}
+
+		schemaErrors = append(schemaErrors, errs...)
+	}
+
+	if len(schemaErrors) > 0 {
+		return ErrSchemaErrors{Errors: schemaErrors}
+	}
+
+	return nil
+}
+
+// checkMetaSchema validates a schema against the meta-schema of its dialect.
+func checkMetaSchema(id url.URL, schema interface{}) ([]SchemaError, error) {
+	dialect := draft07URI
+	if object, ok := schema.(map[string]interface{}); ok {
+		if value, ok := object["$schema"]; ok {
+			uri, err := parseDialect(value)
+			if err != nil {
+				return []SchemaError{{
+					URI:     id,
+					Path:    jsonpointer.Ptr{Tokens: []string{"$schema"}},
+					Message: err.Error(),
+				}}, nil
+			}
+
+			dialect = uri
+		}
+	}
+
+	// the meta-schema can only evaluate values of the sort produced by
+	// encoding/json
+	if schemaErrors := checkJSONValues(id, []string{}, schema); len(schemaErrors) > 0 {
+		return schemaErrors, nil
+	}
+
+	result, err := getMetaValidator().ValidateURI(dialect, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaErrors := make([]SchemaError, len(result.Errors))
+	for i, validationError := range result.Errors {
+		metaURI := validationError.URI
+		metaURI.Fragment = validationError.SchemaPath.String()
+
+		schemaErrors[i] = SchemaError{
+			URI:     id,
+			Path:    validationError.InstancePath,
+			Message: fmt.Sprintf("rejected by meta-schema at %s", metaURI.String()),
+		}
+	}
+
+	return schemaErrors, nil
+}
+
+// checkJSONValues finds every part of a schema which is not a value of the
+// sort produced by encoding/json, such as a Go int.
+func checkJSONValues(id url.URL, tokens []string, value interface{}) []SchemaError {
+	switch value := value.(type) {
+	case nil, bool, float64, string:
+		return nil
+	case []interface{}:
+		schemaErrors := []SchemaError{}
+		for i, elem := range value {
+			schemaErrors = append(schemaErrors, checkJSONValues(id, appendToken(tokens, strconv.Itoa(i)), elem)...)
+		}
+
+		return schemaErrors
+	case map[string]interface{}:
+		schemaErrors := []SchemaError{}
+		for _, key := range sortedKeys(value) {
+			schemaErrors = append(schemaErrors, checkJSONValues(id, appendToken(tokens, key), value[key])...)
+		}
+
+		return schemaErrors
+	}
+
+	return []SchemaError{{
+		URI:     id,
+		Path:    jsonpointer.Ptr{Tokens: tokens},
+		Message: fmt.Sprintf("value of type %T is not JSON", value),
+	}}
+}
+
+func appendToken(tokens []string, token string) []string {
+	out := make([]string, len(tokens), len(tokens)+1)
+	copy(out, tokens)
+	return append(out, token)
+}
+
+// checkKeywords finds every keyword in a schema which is neither a draft-07
+// keyword nor one of the given custom keywords.
+func checkKeywords(id url.URL, schema interface{}, keywords map[string]KeywordCompiler) []SchemaError {
+	schemaErrors := []SchemaError{}
+
+	walkRawSchema(schema, []string{}, func(tokens []string, input map[string]interface{}) {
+		for _, keyword := range sortedKeys(input) {
+			if _, ok := keywords[keyword]; ok || draft07Keywords[keyword] {
+				continue
+			}
+
+			path := make([]string, len(tokens), len(tokens)+1)
+			copy(path, tokens)
+
+			schemaErrors = append(schemaErrors, SchemaError{
+				URI:     id,
+				Path:    jsonpointer.Ptr{Tokens: append(path, keyword)},
+				Message: fmt.Sprintf("unknown keyword %q", keyword),
+			})
+		}
+	})
+
+	return schemaErrors
+}
+
+// parseDialect parses the value of a "$schema" keyword, and returns the URI of
+// a bundled meta-schema for it.
+func parseDialect(value interface{}) (url.URL, error) {
+	str, ok := value.(string)
+	if !ok {
+		return url.URL{}, fmt.Errorf("$schema is not a string")
+	}
+
+	uri, err := url.Parse(str)
+	if err != nil {
+		return url.URL{}, fmt.Errorf("$schema is not a valid URI")
+	}
+
+	dialect := *uri
+	dialect.Fragment = ""
+	if dialect.Scheme == "https" {
+		dialect.Scheme = "http"
+	}
+
+	if dialect != draft07URI {
+		return url.URL{}, fmt.Errorf("unsupported $schema %q", str)
+	}
+
+	return dialect, nil
+}
+
+// rawSchemaID returns the "$id" of a schema which has not yet been parsed. If
+// the schema has no usable "$id", the empty URI is returned.
+func rawSchemaID(schema interface{}) url.URL {
+	object, ok := schema.(map[string]interface{})
+	if !ok {
+		return url.URL{}
+	}
+
+	idStr, ok := object["$id"].(string)
+	if !ok {
+		return url.URL{}
+	}
+
+	uri, err := url.Parse(idStr)
+	if err != nil {
+		return url.URL{}
+	}
+
+	return *uri
+}
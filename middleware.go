@@ -0,0 +1,387 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBodySize is the default value for MaxRequestBodySize and
+// MaxResponseBodySize in MiddlewareConfig.
+const DefaultMaxBodySize = 1 << 20
+
+// HTTPRoute holds the schemas the parts of requests to a route are validated
+// against. A nil URI means that part is not validated.
+type HTTPRoute struct {
+	// Request identifies the schema of the request body, which must be JSON.
+	Request *url.URL
+
+	// Query identifies the schema of the query parameters. They are validated
+	// as an object whose properties are the parameters. A parameter given once
+	// is a string, and a parameter given several times is an array of strings.
+	Query *url.URL
+
+	// Response identifies the schema of the body of successful responses. Only
+	// responses with a 2xx status and a JSON body are validated.
+	Response *url.URL
+}
+
+// MiddlewareConfig contains configuration for Middleware.
+type MiddlewareConfig struct {
+	// Validator holds the schemas referred to by routes.
+	Validator *Validator
+
+	// Route returns the schemas to validate a request and its response against.
+	// If ok is false, the request is passed on without validation.
+	Route func(r *http.Request) (route HTTPRoute, ok bool)
+
+	// MaxRequestBodySize is the largest request body, in bytes, which will be
+	// read for validation. Larger bodies are rejected. A value of zero indicates
+	// to use DefaultMaxBodySize.
+	MaxRequestBodySize int64
+
+	// MaxResponseBodySize is the largest response body, in bytes, which will be
+	// held back for validation. Larger bodies are passed on as they are written,
+	// without validation. A value of zero indicates to use DefaultMaxBodySize.
+	MaxResponseBodySize int64
+
+	// ErrorHandler writes the response for a request or response which failed
+	// validation. If nil, WriteProblem is used.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, problem Problem)
+}
+
+// Problem describes why a request or response failed validation, in the
+// format of RFC 7807 "problem details".
+type Problem struct {
+	// Title is a short summary of the problem. It is the text of Status, as
+	// the type of the problem is "about:blank".
+	Title string
+
+	// Status is the HTTP status code of the response.
+	Status int
+
+	// Detail explains what went wrong.
+	Detail string
+
+	// Errors holds the reasons the request or response was rejected by its
+	// schema, if it was.
+	Errors []ValidationError
+}
+
+// MarshalJSON fulfills the json.Marshaler interface. Paths and URIs are encoded
+// as strings.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string                `json:"type"`
+		Title  string                `json:"title"`
+		Status int                   `json:"status"`
+		Detail string                `json:"detail,omitempty"`
+		Errors []jsonValidationError `json:"errors,omitempty"`
+	}{
+		Type:   "about:blank",
+		Title:  p.Title,
+		Status: p.Status,
+		Detail: p.Detail,
+		Errors: jsonValidationErrors(p.Errors),
+	})
+}
+
+type jsonValidationError struct {
+	InstancePath string                `json:"instancePath"`
+	SchemaPath   string                `json:"schemaPath"`
+	URI          string                `json:"uri"`
+	Causes       []jsonValidationError `json:"causes,omitempty"`
+	Matches      []int                 `json:"matches,omitempty"`
+}
+
+func jsonValidationErrors(errors []ValidationError) []jsonValidationError {
+	if len(errors) == 0 {
+		return nil
+	}
+
+	result := make([]jsonValidationError, len(errors))
+	for i, err := range errors {
+		result[i] = jsonValidationError{
+			InstancePath: err.InstancePath.String(),
+			SchemaPath:   err.SchemaPath.String(),
+			URI:          err.URI.String(),
+			Causes:       jsonValidationErrors(err.Causes),
+			Matches:      err.Matches,
+		}
+	}
+
+	return result
+}
+
+// WriteProblem writes a problem as an "application/problem+json" response.
+func WriteProblem(w http.ResponseWriter, problem Problem) {
+	data, err := json.Marshal(problem)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(problem.Status)
+	w.Write(data)
+}
+
+func newProblem(status int, detail string, errors []ValidationError) Problem {
+	return Problem{Title: http.StatusText(status), Status: status, Detail: detail, Errors: errors}
+}
+
+// Middleware returns middleware which validates requests, and the responses to
+// them, against the schemas of their route.
+//
+// Request bodies are read in full before validation, and restored so that the
+// next handler can read them. A request is rejected with:
+//
+//   - 413 if its body is larger than MaxRequestBodySize,
+//   - 415 if its body is not JSON, as indicated by its Content-Type,
+//   - 400 if its body is empty or malformed, or its query is invalid, and
+//   - 422 if its body is rejected by its schema.
+//
+// Responses are held back until the handler returns, and replaced with a 500
+// if rejected by their schema. The responses are RFC 7807 problem details
+// written by ErrorHandler.
+func Middleware(config MiddlewareConfig) func(http.Handler) http.Handler {
+	maxRequest := config.MaxRequestBodySize
+	if maxRequest == 0 {
+		maxRequest = DefaultMaxBodySize
+	}
+
+	maxResponse := config.MaxResponseBodySize
+	if maxResponse == 0 {
+		maxResponse = DefaultMaxBodySize
+	}
+
+	handleError := config.ErrorHandler
+	if handleError == nil {
+		handleError = func(w http.ResponseWriter, r *http.Request, problem Problem) {
+			WriteProblem(w, problem)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, ok := config.Route(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if route.Query != nil {
+				if problem, ok := validateQuery(config.Validator, *route.Query, r.URL.Query()); !ok {
+					handleError(w, r, problem)
+					return
+				}
+			}
+
+			if route.Request != nil {
+				if problem, ok := validateRequestBody(config.Validator, *route.Request, r, maxRequest); !ok {
+					handleError(w, r, problem)
+					return
+				}
+			}
+
+			if route.Response == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buf := &bufferedResponse{w: w, header: http.Header{}, max: maxResponse}
+			next.ServeHTTP(buf, r)
+
+			if buf.passthrough {
+				return
+			}
+
+			if problem, ok := validateResponseBody(config.Validator, *route.Response, buf); !ok {
+				handleError(w, r, problem)
+				return
+			}
+
+			buf.flush()
+		})
+	}
+}
+
+// queryInstance converts query parameters into the instance validated against
+// the Query schema of a route.
+func queryInstance(query url.Values) map[string]interface{} {
+	instance := map[string]interface{}{}
+	for name, values := range query {
+		if len(values) == 1 {
+			instance[name] = values[0]
+			continue
+		}
+
+		array := make([]interface{}, len(values))
+		for i, value := range values {
+			array[i] = value
+		}
+
+		instance[name] = array
+	}
+
+	return instance
+}
+
+func validateQuery(v *Validator, uri url.URL, query url.Values) (Problem, bool) {
+	result, err := v.ValidateURI(uri, queryInstance(query))
+	if err != nil {
+		return newProblem(http.StatusInternalServerError, err.Error(), nil), false
+	}
+
+	if !result.IsValid() {
+		return newProblem(http.StatusBadRequest, "query parameters do not match their schema", result.Errors), false
+	}
+
+	return Problem{}, true
+}
+
+func validateRequestBody(v *Validator, uri url.URL, r *http.Request, max int64) (Problem, bool) {
+	if !isJSONMediaType(r.Header.Get("Content-Type")) {
+		return newProblem(http.StatusUnsupportedMediaType, "request body must be JSON", nil), false
+	}
+
+	if r.Body == nil {
+		return newProblem(http.StatusBadRequest, "request body is empty", nil), false
+	}
+
+	// one byte more than the limit is read, to tell whether there is more
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, max+1))
+	if err != nil {
+		return newProblem(http.StatusBadRequest, err.Error(), nil), false
+	}
+
+	if int64(len(data)) > max {
+		return newProblem(http.StatusRequestEntityTooLarge, "request body is too large", nil), false
+	}
+
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return newProblem(http.StatusBadRequest, "request body is empty", nil), false
+	}
+
+	instance, _, err := DecodeJSON("request body", data)
+	if err != nil {
+		return newProblem(http.StatusBadRequest, err.Error(), nil), false
+	}
+
+	result, err := v.ValidateURI(uri, instance)
+	if err != nil {
+		return newProblem(http.StatusInternalServerError, err.Error(), nil), false
+	}
+
+	if !result.IsValid() {
+		return newProblem(http.StatusUnprocessableEntity, "request body does not match its schema", result.Errors), false
+	}
+
+	return Problem{}, true
+}
+
+func validateResponseBody(v *Validator, uri url.URL, buf *bufferedResponse) (Problem, bool) {
+	if buf.status < 200 || buf.status > 299 || buf.body.Len() == 0 || !isJSONMediaType(buf.header.Get("Content-Type")) {
+		return Problem{}, true
+	}
+
+	instance, _, err := DecodeJSON("response body", buf.body.Bytes())
+	if err != nil {
+		return newProblem(http.StatusInternalServerError, err.Error(), nil), false
+	}
+
+	result, err := v.ValidateURI(uri, instance)
+	if err != nil {
+		return newProblem(http.StatusInternalServerError, err.Error(), nil), false
+	}
+
+	if !result.IsValid() {
+		return newProblem(http.StatusInternalServerError, "response body does not match its schema", result.Errors), false
+	}
+
+	return Problem{}, true
+}
+
+// isJSONMediaType determines whether a Content-Type header denotes JSON, either
+// as "application/json" or a type with a "+json" suffix.
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// bufferedResponse holds back a response until it can be validated. Once the
+// body grows beyond max, what has been held back is written, and the rest of
+// the response passes through.
+type bufferedResponse struct {
+	w      http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+	max    int64
+
+	// passthrough indicates that the response is no longer being held back
+	passthrough bool
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+
+	if b.passthrough {
+		return b.w.Write(data)
+	}
+
+	if int64(b.body.Len()+len(data)) > b.max {
+		b.flush()
+		return b.w.Write(data)
+	}
+
+	return b.body.Write(data)
+}
+
+// flush writes what has been held back, and lets the rest of the response pass
+// through.
+func (b *bufferedResponse) flush() {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+
+	header := b.w.Header()
+	for key, values := range b.header {
+		header[key] = values
+	}
+
+	b.w.WriteHeader(b.status)
+	b.w.Write(b.body.Bytes())
+	b.body.Reset()
+
+	// changes to the header from now on go straight to the response, as for
+	// trailers
+	b.header = header
+	b.passthrough = true
+}
@@ -0,0 +1,200 @@
+package jsonschema
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	validator, err := NewValidator([]interface{}{
+		mustDecode(t, `{
+			"$id": "http://example.com/user",
+			"type": "object",
+			"properties": {"name": {"type": "string", "minLength": 1}},
+			"required": ["name"]
+		}`),
+		mustDecode(t, `{
+			"$id": "http://example.com/query",
+			"properties": {
+				"limit": {"type": "string", "pattern": "^[0-9]+$"},
+				"tag": {"type": "array"}
+			}
+		}`),
+	})
+	assert.NoError(t, err)
+
+	user, _ := url.Parse("http://example.com/user")
+	query, _ := url.Parse("http://example.com/query")
+
+	middleware := Middleware(MiddlewareConfig{
+		Validator: &validator,
+		Route: func(r *http.Request) (HTTPRoute, bool) {
+			switch r.URL.Path {
+			case "/users":
+				return HTTPRoute{Request: user, Query: query, Response: user}, true
+			case "/any":
+				return HTTPRoute{Response: user}, true
+			}
+
+			return HTTPRoute{}, false
+		},
+		MaxRequestBodySize: 64,
+	})
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// echo the request body, which the middleware restored
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		if ct := r.Header.Get("Content-Type"); ct != "" {
+			w.Header().Set("Content-Type", ct)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+
+		w.Write(body)
+	}))
+
+	testCases := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		status      int
+		response    string
+	}{
+		{"valid", "/users?limit=10", "application/json", `{"name": "x"}`, 200, `{"name": "x"}`},
+		{"unrouted", "/other", "", "not json", 200, "not json"},
+		{"invalid body", "/users", "application/json", `{"name": ""}`, 422, `{
+			"type": "about:blank",
+			"title": "Unprocessable Entity",
+			"status": 422,
+			"detail": "request body does not match its schema",
+			"errors": [
+				{"instancePath": "/name", "schemaPath": "/properties/name/minLength", "uri": "http://example.com/user"}
+			]
+		}`},
+		{"invalid query", "/users?limit=ten&tag=a&tag=b", "application/json", `{"name": "x"}`, 400, `{
+			"type": "about:blank",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "query parameters do not match their schema",
+			"errors": [
+				{"instancePath": "/limit", "schemaPath": "/properties/limit/pattern", "uri": "http://example.com/query"}
+			]
+		}`},
+		{"repeated query", "/users?limit=10&tag=a&tag=b", "application/json", `{"name": "x"}`, 200, `{"name": "x"}`},
+		{"malformed", "/users", "application/json", `{"name":`, 400, `{
+			"type": "about:blank",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "request body:1:9: unexpected end of input"
+		}`},
+		{"empty", "/users", "application/json", ``, 400, `{
+			"type": "about:blank",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "request body is empty"
+		}`},
+		{"not json", "/users", "text/plain", `{"name": "x"}`, 415, `{
+			"type": "about:blank",
+			"title": "Unsupported Media Type",
+			"status": 415,
+			"detail": "request body must be JSON"
+		}`},
+		{"json suffix", "/users", "application/vnd.user+json; charset=utf-8", `{"name": "x"}`, 200, `{"name": "x"}`},
+		{"too large", "/users", "application/json", `{"name": "` + strings.Repeat("x", 64) + `"}`, 413, `{
+			"type": "about:blank",
+			"title": "Request Entity Too Large",
+			"status": 413,
+			"detail": "request body is too large"
+		}`},
+		{"invalid response", "/any", "", `{}`, 500, `{
+			"type": "about:blank",
+			"title": "Internal Server Error",
+			"status": 500,
+			"detail": "response body does not match its schema",
+			"errors": [
+				{"instancePath": "", "schemaPath": "/required/0", "uri": "http://example.com/user"}
+			]
+		}`},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status >= 400 {
+				assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+				assert.JSONEq(t, tt.response, w.Body.String())
+			} else {
+				assert.Equal(t, tt.response, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddlewareResponse(t *testing.T) {
+	validator, err := NewValidator([]interface{}{mustDecode(t, `{"type": "array", "maxItems": 2}`)})
+	assert.NoError(t, err)
+
+	respond := func(status int, contentType, body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("X-Handler", "yes")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		})
+	}
+
+	middleware := Middleware(MiddlewareConfig{
+		Validator: &validator,
+		Route: func(r *http.Request) (HTTPRoute, bool) {
+			return HTTPRoute{Response: &url.URL{}}, true
+		},
+		MaxResponseBodySize: 16,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, problem Problem) {
+			w.WriteHeader(problem.Status)
+			w.Write([]byte(problem.Detail))
+		},
+	})
+
+	testCases := []struct {
+		name    string
+		handler http.Handler
+		status  int
+		body    string
+		header  string
+	}{
+		{"valid", respond(201, "application/json", `[1, 2]`), 201, `[1, 2]`, "yes"},
+		{"invalid", respond(200, "application/json", `[1, 2, 3]`), 500, "response body does not match its schema", ""},
+		{"malformed", respond(200, "application/json", `[1,`), 500, "response body:1:4: unexpected end of input", ""},
+		{"error status", respond(404, "application/json", `{}`), 404, `{}`, "yes"},
+		{"not json", respond(200, "text/plain", `{}`), 200, `{}`, "yes"},
+		{"too large", respond(200, "application/json", `[1, 2, 3, 4, 5, 6, 7, 8, 9]`), 200, `[1, 2, 3, 4, 5, 6, 7, 8, 9]`, "yes"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			middleware(tt.handler).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+			assert.Equal(t, tt.header, w.Header().Get("X-Handler"))
+		})
+	}
+}
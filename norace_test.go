@@ -0,0 +1,6 @@
+//go:build !race
+// +build !race
+
+package jsonschema
+
+const raceEnabled = false
@@ -0,0 +1,967 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// OpenAPIConfig contains configuration for an OpenAPI.
+type OpenAPIConfig struct {
+	// URI identifies the document. The schemas of the document have URIs
+	// relative to it, such as "#/components/schemas/Pet" for an empty URI.
+	URI url.URL
+
+	// Validator configures the Validators compiled from the document. A
+	// MaxStackDepth of zero indicates to use DefaultMaxStackDepth.
+	// ValidateSchemas and StrictKeywords are ignored, as the document is not
+	// itself a schema.
+	Validator ValidatorConfig
+}
+
+// OpenAPI validates HTTP requests and responses against the operations of an
+// OpenAPI 3.0 or 3.1 document.
+//
+// The document is compiled as a single schema document, so every schema has a
+// URI from its location in the document, such as
+// "#/paths/~1pets/post/requestBody/content/application~1json/schema". Errors
+// point into the document in the same way.
+//
+// OpenAPI 3.0 differs from JSON Schema in a few ways, which are accounted for:
+//
+//   - "nullable" adds "null" to the "type" of a schema, if it has one.
+//   - "exclusiveMinimum" and "exclusiveMaximum" are booleans which modify
+//     "minimum" and "maximum".
+//   - "example" is treated as "examples" holding a single example.
+//
+// In both versions, properties marked "readOnly" must not appear in requests,
+// and those marked "writeOnly" must not appear in responses; neither is
+// required where it must not appear. So the document is compiled twice, once
+// for each context. See RequestValidator and ResponseValidator.
+//
+// A "discriminator" beside "oneOf" or "anyOf" requires its property, and
+// requires it to be one of the values of its "mapping" or the name of one of the
+// schemas referred to by the alternatives.
+type OpenAPI struct {
+	uri        url.URL
+	document   map[string]interface{}
+	v30        bool
+	operations map[string]openAPIOperation
+	request    Validator
+	response   Validator
+}
+
+// openAPIOperation is an operation of the document, with its references
+// resolved. Tokens point to objects within the document.
+type openAPIOperation struct {
+	path       string
+	tokens     []string
+	parameters []openAPIParameter
+	body       *openAPIBody
+	responses  map[string]openAPIResponse
+}
+
+// openAPIParameter is a parameter of an operation, or a header of a response.
+type openAPIParameter struct {
+	name      string
+	in        string
+	required  bool
+	tokens    []string
+	hasSchema bool
+}
+
+type openAPIBody struct {
+	tokens   []string
+	required bool
+	content  map[string]interface{}
+}
+
+type openAPIResponse struct {
+	tokens  []string
+	headers []openAPIParameter
+	content map[string]interface{}
+}
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// LoadOpenAPI constructs an OpenAPI from a document encoded as YAML, if the name
+// of the source ends in ".yaml" or ".yml", or JSON otherwise. Positions in the
+// document are remembered, as with NewValidatorFromJSON.
+func LoadOpenAPI(source Source, config OpenAPIConfig) (*OpenAPI, error) {
+	decode := DecodeJSON
+	switch strings.ToLower(filepath.Ext(source.Name)) {
+	case ".yaml", ".yml":
+		decode = DecodeYAML
+	}
+
+	document, sourceMap, err := decode(source.Name, source.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpenAPI(document, sourceMap, config)
+}
+
+// NewOpenAPI constructs an OpenAPI from a decoded document.
+func NewOpenAPI(document interface{}, config OpenAPIConfig) (*OpenAPI, error) {
+	return newOpenAPI(document, nil, config)
+}
+
+func newOpenAPI(document interface{}, sourceMap *SourceMap, config OpenAPIConfig) (*OpenAPI, error) {
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: OpenAPI document is not an object")
+	}
+
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.0.") && !strings.HasPrefix(version, "3.1.") {
+		return nil, fmt.Errorf("jsonschema: unsupported OpenAPI version %q", version)
+	}
+
+	uri := config.URI
+	uri.Fragment = ""
+
+	o := &OpenAPI{
+		uri:        uri,
+		document:   root,
+		v30:        strings.HasPrefix(version, "3.0."),
+		operations: map[string]openAPIOperation{},
+	}
+
+	if err := o.readOperations(); err != nil {
+		return nil, err
+	}
+
+	locations := o.schemaLocations()
+	entries := make([]url.URL, len(locations))
+	for i, tokens := range locations {
+		entries[i] = o.uriOf(tokens)
+	}
+
+	validatorConfig := config.Validator
+	validatorConfig.ValidateSchemas = false
+	validatorConfig.StrictKeywords = false
+	if validatorConfig.MaxStackDepth == 0 {
+		validatorConfig.MaxStackDepth = DefaultMaxStackDepth
+	}
+
+	for _, context := range []string{"readOnly", "writeOnly"} {
+		v := newValidator(validatorConfig)
+		v.entries = entries
+		v.discriminators = true
+
+		if err := v.seal([]interface{}{o.compile(locations, context)}, []*SourceMap{sourceMap}); err != nil {
+			return nil, err
+		}
+
+		if context == "readOnly" {
+			o.request = v
+		} else {
+			o.response = v
+		}
+	}
+
+	return o, nil
+}
+
+// RequestValidator returns the Validator which evaluates instances as parts of
+// requests. It holds every schema of the document.
+func (o *OpenAPI) RequestValidator() *Validator {
+	return &o.request
+}
+
+// ResponseValidator returns the Validator which evaluates instances as parts of
+// responses. It holds every schema of the document.
+func (o *OpenAPI) ResponseValidator() *Validator {
+	return &o.response
+}
+
+// SchemaURI returns the URI of the schema with the given name in
+// "components/schemas".
+func (o *OpenAPI) SchemaURI(name string) url.URL {
+	return o.uriOf([]string{"components", "schemas", name})
+}
+
+func (o *OpenAPI) uriOf(tokens []string) url.URL {
+	uri := o.uri
+	uri.Fragment = jsonpointer.Ptr{Tokens: tokens}.String()
+	return uri
+}
+
+// resolve follows references within the document, starting from the value at
+// tokens.
+func (o *OpenAPI) resolve(tokens []string) ([]string, map[string]interface{}, error) {
+	for i := 0; i < DefaultMaxStackDepth; i++ {
+		ptr := jsonpointer.Ptr{Tokens: tokens}
+		value, err := ptr.Eval(o.document)
+		if err != nil {
+			return nil, nil, fmt.Errorf("jsonschema: cannot resolve %q in OpenAPI document: %v", ptr.String(), err)
+		}
+
+		object, ok := (*value).(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("jsonschema: %q in OpenAPI document is not an object", ptr.String())
+		}
+
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return tokens, object, nil
+		}
+
+		if !strings.HasPrefix(ref, "#") {
+			return nil, nil, fmt.Errorf("jsonschema: cannot resolve %q in OpenAPI document: only references within the document are supported", ref)
+		}
+
+		refPtr, err := jsonpointer.New(ref[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tokens = refPtr.Tokens
+	}
+
+	return nil, nil, ErrStackOverflow
+}
+
+// readOperations finds every operation of the document with an operationId.
+func (o *OpenAPI) readOperations() error {
+	paths, _ := o.document["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(paths) {
+		itemTokens, item, err := o.resolve([]string{"paths", path})
+		if err != nil {
+			return err
+		}
+
+		common, err := o.readParameters(itemTokens, item)
+		if err != nil {
+			return err
+		}
+
+		for _, method := range openAPIMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			id, ok := operation["operationId"].(string)
+			if !ok {
+				continue
+			}
+
+			if _, ok := o.operations[id]; ok {
+				return fmt.Errorf("jsonschema: duplicate operationId %q in OpenAPI document", id)
+			}
+
+			op, err := o.readOperation(path, append(itemTokens[:len(itemTokens):len(itemTokens)], method), operation, common)
+			if err != nil {
+				return err
+			}
+
+			o.operations[id] = op
+		}
+	}
+
+	return nil
+}
+
+func (o *OpenAPI) readOperation(path string, tokens []string, operation map[string]interface{}, common []openAPIParameter) (openAPIOperation, error) {
+	op := openAPIOperation{path: path, tokens: tokens, responses: map[string]openAPIResponse{}}
+
+	parameters, err := o.readParameters(tokens, operation)
+	if err != nil {
+		return openAPIOperation{}, err
+	}
+
+	// parameters of the operation override those of its path
+	for _, p := range common {
+		overridden := false
+		for _, q := range parameters {
+			overridden = overridden || (p.name == q.name && p.in == q.in)
+		}
+
+		if !overridden {
+			op.parameters = append(op.parameters, p)
+		}
+	}
+
+	op.parameters = append(op.parameters, parameters...)
+
+	if _, ok := operation["requestBody"]; ok {
+		bodyTokens, body, err := o.resolve(append(tokens[:len(tokens):len(tokens)], "requestBody"))
+		if err != nil {
+			return openAPIOperation{}, err
+		}
+
+		content, _ := body["content"].(map[string]interface{})
+		required, _ := body["required"].(bool)
+		op.body = &openAPIBody{tokens: bodyTokens, required: required, content: content}
+	}
+
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, key := range sortedKeys(responses) {
+		responseTokens, response, err := o.resolve(append(tokens[:len(tokens):len(tokens)], "responses", key))
+		if err != nil {
+			return openAPIOperation{}, err
+		}
+
+		content, _ := response["content"].(map[string]interface{})
+		r := openAPIResponse{tokens: responseTokens, content: content}
+
+		headers, _ := response["headers"].(map[string]interface{})
+		for _, name := range sortedKeys(headers) {
+			headerTokens, header, err := o.resolve(append(responseTokens[:len(responseTokens):len(responseTokens)], "headers", name))
+			if err != nil {
+				return openAPIOperation{}, err
+			}
+
+			required, _ := header["required"].(bool)
+			_, hasSchema := header["schema"]
+			r.headers = append(r.headers, openAPIParameter{
+				name:      name,
+				in:        "header",
+				required:  required,
+				tokens:    headerTokens,
+				hasSchema: hasSchema,
+			})
+		}
+
+		op.responses[key] = r
+	}
+
+	return op, nil
+}
+
+func (o *OpenAPI) readParameters(tokens []string, object map[string]interface{}) ([]openAPIParameter, error) {
+	values, _ := object["parameters"].([]interface{})
+
+	parameters := []openAPIParameter{}
+	for i := range values {
+		paramTokens, param, err := o.resolve(append(tokens[:len(tokens):len(tokens)], "parameters", strconv.Itoa(i)))
+		if err != nil {
+			return nil, err
+		}
+
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		required, _ := param["required"].(bool)
+		_, hasSchema := param["schema"]
+
+		parameters = append(parameters, openAPIParameter{
+			name:      name,
+			in:        in,
+			required:  required || in == "path",
+			tokens:    paramTokens,
+			hasSchema: hasSchema,
+		})
+	}
+
+	return parameters, nil
+}
+
+// schemaLocations returns the location of every schema in "components/schemas",
+// and of every schema of the operations, without duplicates.
+func (o *OpenAPI) schemaLocations() [][]string {
+	locations := [][]string{}
+	seen := map[string]bool{}
+	add := func(tokens ...string) {
+		key := jsonpointer.Ptr{Tokens: tokens}.String()
+		if !seen[key] {
+			seen[key] = true
+			locations = append(locations, tokens)
+		}
+	}
+
+	components, _ := o.document["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for _, name := range sortedKeys(schemas) {
+		add("components", "schemas", name)
+	}
+
+	addContent := func(tokens []string, content map[string]interface{}) {
+		for _, mediaType := range sortedKeys(content) {
+			if media, ok := content[mediaType].(map[string]interface{}); ok {
+				if _, ok := media["schema"]; ok {
+					add(append(tokens[:len(tokens):len(tokens)], "content", mediaType, "schema")...)
+				}
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(o.operations))
+	for id := range o.operations {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		op := o.operations[id]
+		for _, p := range op.parameters {
+			if p.hasSchema {
+				add(append(p.tokens[:len(p.tokens):len(p.tokens)], "schema")...)
+			}
+		}
+
+		if op.body != nil {
+			addContent(op.body.tokens, op.body.content)
+		}
+
+		for _, key := range sortedResponseKeys(op.responses) {
+			r := op.responses[key]
+			for _, h := range r.headers {
+				if h.hasSchema {
+					add(append(h.tokens[:len(h.tokens):len(h.tokens)], "schema")...)
+				}
+			}
+
+			addContent(r.tokens, r.content)
+		}
+	}
+
+	return locations
+}
+
+func sortedResponseKeys(responses map[string]openAPIResponse) []string {
+	keys := make([]string, 0, len(responses))
+	for key := range responses {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// compile returns a copy of the document as a schema document, with the
+// schemas at locations rewritten as JSON Schema. Properties marked with the
+// hidden keyword, "readOnly" or "writeOnly", are forbidden.
+func (o *OpenAPI) compile(locations [][]string, hidden string) map[string]interface{} {
+	document := copyRawValue(o.document).(map[string]interface{})
+	if o.uri != (url.URL{}) {
+		document["$id"] = o.uri.String()
+	}
+
+	for _, tokens := range locations {
+		value, err := jsonpointer.Ptr{Tokens: tokens}.Eval(document)
+		if err != nil {
+			continue
+		}
+
+		walkRawSchema(*value, tokens, func(tokens []string, schema map[string]interface{}) {
+			o.rewriteSchema(schema, hidden)
+		})
+	}
+
+	return document
+}
+
+// rewriteSchema rewrites a schema of the document as JSON Schema.
+func (o *OpenAPI) rewriteSchema(schema map[string]interface{}, hidden string) {
+	if o.v30 {
+		if nullable, _ := schema["nullable"].(bool); nullable {
+			switch typ := schema["type"].(type) {
+			case string:
+				schema["type"] = []interface{}{typ, "null"}
+			case []interface{}:
+				if !containsValue(typ, "null") {
+					schema["type"] = append(typ, "null")
+				}
+			}
+		}
+
+		for _, keywords := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+			exclusive, ok := schema[keywords[0]].(bool)
+			if !ok {
+				continue
+			}
+
+			delete(schema, keywords[0])
+			if bound, ok := schema[keywords[1]]; ok && exclusive {
+				schema[keywords[0]] = bound
+				delete(schema, keywords[1])
+			}
+		}
+	}
+
+	if example, ok := schema["example"]; ok {
+		if _, ok := schema["examples"]; !ok {
+			schema["examples"] = []interface{}{example}
+		}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(properties) {
+			if o.isMarked(properties[name], hidden) {
+				properties[name] = false
+				if required, ok := schema["required"].([]interface{}); ok {
+					schema["required"] = removeValue(required, name)
+				}
+			}
+		}
+	}
+
+	o.rewriteDiscriminator(schema)
+}
+
+// isMarked determines whether a schema, or the schema it refers to, has the
+// given keyword set to true.
+func (o *OpenAPI) isMarked(value interface{}, keyword string) bool {
+	schema, ok := value.(map[string]interface{})
+	for i := 0; ok && i < DefaultMaxStackDepth; i++ {
+		if marked, _ := schema[keyword].(bool); marked {
+			return true
+		}
+
+		ref, isRef := schema["$ref"].(string)
+		if !isRef || !strings.HasPrefix(ref, "#") {
+			return false
+		}
+
+		ptr, err := jsonpointer.New(ref[1:])
+		if err != nil {
+			return false
+		}
+
+		target, err := ptr.Eval(o.document)
+		if err != nil {
+			return false
+		}
+
+		schema, ok = (*target).(map[string]interface{})
+	}
+
+	return false
+}
+
+// rewriteDiscriminator adds the constraints of a "discriminator" beside
+// "oneOf" or "anyOf" to a schema, in "allOf".
+func (o *OpenAPI) rewriteDiscriminator(schema map[string]interface{}) {
+	discriminator, ok := schema["discriminator"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	property, ok := discriminator["propertyName"].(string)
+	if !ok {
+		return
+	}
+
+	alternatives, ok := schema["oneOf"].([]interface{})
+	if !ok {
+		if alternatives, ok = schema["anyOf"].([]interface{}); !ok {
+			return
+		}
+	}
+
+	mapping, _ := discriminator["mapping"].(map[string]interface{})
+	values := []interface{}{}
+	mapped := map[string]bool{}
+	for _, value := range sortedKeys(mapping) {
+		values = append(values, value)
+		if target, ok := mapping[value].(string); ok {
+			mapped[discriminatorTarget(target)] = true
+		}
+	}
+
+	for _, alternative := range alternatives {
+		object, _ := alternative.(map[string]interface{})
+		ref, ok := object["$ref"].(string)
+		if !ok || mapped[ref] {
+			continue
+		}
+
+		// alternatives not in the mapping are identified by their name
+		if name := ref[strings.LastIndex(ref, "/")+1:]; name != "" {
+			values = append(values, name)
+		}
+	}
+
+	allOf, _ := schema["allOf"].([]interface{})
+	schema["allOf"] = append(allOf, map[string]interface{}{
+		"required": []interface{}{property},
+		"properties": map[string]interface{}{
+			property: map[string]interface{}{"enum": values},
+		},
+	})
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeValue(values []interface{}, value interface{}) []interface{} {
+	out := []interface{}{}
+	for _, v := range values {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// ValidateRequest validates a request against the operation with the given
+// operationId. Its path, query, header, and cookie parameters, and its JSON
+// body, are validated against their schemas. The body is read in full, and
+// restored so that it can be read again.
+//
+// Errors have an InstancePath within a notional object holding the parts of
+// the request, such as "/query/limit" or "/body/name". Parameters are
+// converted from strings to the type their schema expects, if it is a number,
+// an integer, a boolean, or an array of these.
+//
+// If no operation has the given ID, ErrNoSuchOperation is returned. If the body
+// is not valid JSON, an instance of ErrSyntax is returned.
+func (o *OpenAPI) ValidateRequest(operationID string, r *http.Request) (ValidationResult, error) {
+	op, ok := o.operations[operationID]
+	if !ok {
+		return ValidationResult{}, ErrNoSuchOperation
+	}
+
+	result := ValidationResult{Errors: []ValidationError{}}
+	pathParams := matchPathTemplate(op.path, r.URL.EscapedPath())
+	query := r.URL.Query()
+
+	for _, p := range op.parameters {
+		var values []string
+		switch p.in {
+		case "path":
+			if value, ok := pathParams[p.name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			values = query[p.name]
+		case "header":
+			values = r.Header[http.CanonicalHeaderKey(p.name)]
+		case "cookie":
+			if cookie, err := r.Cookie(p.name); err == nil {
+				values = []string{cookie.Value}
+			}
+		}
+
+		if err := o.validateParameter(&o.request, p, values, &result); err != nil {
+			return ValidationResult{}, err
+		}
+	}
+
+	if op.body != nil {
+		var data []byte
+		if r.Body != nil {
+			var err error
+			if data, err = ioutil.ReadAll(r.Body); err != nil {
+				return ValidationResult{}, err
+			}
+
+			r.Body.Close()
+			r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		}
+
+		err := o.validateBody(&o.request, op.body.tokens, op.body.required, op.body.content, r.Header.Get("Content-Type"), "request body", data, &result)
+		if err != nil {
+			return ValidationResult{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// ValidateResponse validates a response to the operation with the given
+// operationId. The response is described by the entry of "responses" for its
+// status code, its range of status codes such as "2XX", or "default". Its
+// headers and JSON body are validated against their schemas.
+//
+// Errors have an InstancePath within a notional object holding the parts of
+// the response, such as "/header/X-Rate-Limit" or "/body/id". If the document
+// does not describe the status code, the error has an InstancePath of
+// "/status".
+//
+// If no operation has the given ID, ErrNoSuchOperation is returned. If the body
+// is not valid JSON, an instance of ErrSyntax is returned.
+func (o *OpenAPI) ValidateResponse(operationID string, status int, header http.Header, body []byte) (ValidationResult, error) {
+	op, ok := o.operations[operationID]
+	if !ok {
+		return ValidationResult{}, ErrNoSuchOperation
+	}
+
+	result := ValidationResult{Errors: []ValidationError{}}
+
+	response, ok := op.responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = op.responses[strconv.Itoa(status/100)+"XX"]
+	}
+
+	if !ok {
+		response, ok = op.responses["default"]
+	}
+
+	if !ok {
+		result.Errors = append(result.Errors, o.newError([]string{"status"}, append(op.tokens[:len(op.tokens):len(op.tokens)], "responses")))
+		return result, nil
+	}
+
+	for _, h := range response.headers {
+		// the content type is described by the keys of "content" instead
+		if strings.EqualFold(h.name, "Content-Type") {
+			continue
+		}
+
+		if err := o.validateParameter(&o.response, h, header[http.CanonicalHeaderKey(h.name)], &result); err != nil {
+			return ValidationResult{}, err
+		}
+	}
+
+	err := o.validateBody(&o.response, response.tokens, false, response.content, header.Get("Content-Type"), "response body", body, &result)
+	if err != nil {
+		return ValidationResult{}, err
+	}
+
+	return result, nil
+}
+
+func (o *OpenAPI) validateParameter(v *Validator, p openAPIParameter, values []string, result *ValidationResult) error {
+	instancePath := []string{p.in, p.name}
+	if len(values) == 0 {
+		if p.required {
+			result.Errors = append(result.Errors, o.newError(instancePath, append(p.tokens[:len(p.tokens):len(p.tokens)], "required")))
+		}
+
+		return nil
+	}
+
+	if !p.hasSchema {
+		return nil
+	}
+
+	schemaTokens := append(p.tokens[:len(p.tokens):len(p.tokens)], "schema")
+	instance := o.parameterValue(schemaTokens, p.in, values)
+
+	return o.validate(v, schemaTokens, instancePath, instance, result)
+}
+
+func (o *OpenAPI) validateBody(v *Validator, tokens []string, required bool, content map[string]interface{}, contentType, filename string, data []byte, result *ValidationResult) error {
+	if len(data) == 0 {
+		if required {
+			result.Errors = append(result.Errors, o.newError([]string{"body"}, append(tokens[:len(tokens):len(tokens)], "required")))
+		}
+
+		return nil
+	}
+
+	if content == nil {
+		return nil
+	}
+
+	mediaType, ok := matchMediaType(content, contentType)
+	if !ok {
+		result.Errors = append(result.Errors, o.newError([]string{"body"}, append(tokens[:len(tokens):len(tokens)], "content")))
+		return nil
+	}
+
+	media, _ := content[mediaType].(map[string]interface{})
+	if _, ok := media["schema"]; !ok || !isJSONMediaType(contentType) {
+		// only JSON bodies can be validated
+		return nil
+	}
+
+	instance, _, err := DecodeJSON(filename, data)
+	if err != nil {
+		return err
+	}
+
+	schemaTokens := append(tokens[:len(tokens):len(tokens)], "content", mediaType, "schema")
+	return o.validate(v, schemaTokens, []string{"body"}, instance, result)
+}
+
+// validate evaluates an instance against the schema at tokens, and adds the
+// result to result, with instancePath prefixed to the path of each error.
+func (o *OpenAPI) validate(v *Validator, tokens, instancePath []string, instance interface{}, result *ValidationResult) error {
+	r, err := v.ValidateURI(o.uriOf(tokens), instance)
+	if err != nil {
+		return err
+	}
+
+	result.Errors = append(result.Errors, prefixInstancePaths(r.Errors, instancePath)...)
+	result.Overflowed = result.Overflowed || r.Overflowed
+	result.Suppressed += r.Suppressed
+	return nil
+}
+
+func (o *OpenAPI) newError(instancePath, schemaPath []string) ValidationError {
+	return ValidationError{
+		InstancePath: jsonpointer.Ptr{Tokens: instancePath},
+		SchemaPath:   jsonpointer.Ptr{Tokens: schemaPath},
+		URI:          o.uri,
+	}
+}
+
+func prefixInstancePaths(errors []ValidationError, prefix []string) []ValidationError {
+	if len(errors) == 0 {
+		return errors
+	}
+
+	out := make([]ValidationError, len(errors))
+	for i, err := range errors {
+		tokens := make([]string, 0, len(prefix)+len(err.InstancePath.Tokens))
+		tokens = append(tokens, prefix...)
+		tokens = append(tokens, err.InstancePath.Tokens...)
+
+		err.InstancePath = jsonpointer.Ptr{Tokens: tokens}
+		err.Causes = prefixInstancePaths(err.Causes, prefix)
+		out[i] = err
+	}
+
+	return out
+}
+
+// parameterValue converts the values of a parameter into the type expected by
+// its schema. Arrays are given as repeated query parameters, or as
+// comma-separated values elsewhere.
+func (o *OpenAPI) parameterValue(schemaTokens []string, in string, values []string) interface{} {
+	typ, itemType := o.schemaType(schemaTokens)
+	if typ != "array" {
+		return parseParameter(values[0], typ)
+	}
+
+	if in != "query" {
+		values = strings.Split(values[0], ",")
+	}
+
+	array := make([]interface{}, len(values))
+	for i, value := range values {
+		array[i] = parseParameter(value, itemType)
+	}
+
+	return array
+}
+
+// schemaType returns the type of the schema at tokens, and of its items if it
+// is an array.
+func (o *OpenAPI) schemaType(tokens []string) (string, string) {
+	_, schema, err := o.resolve(tokens)
+	if err != nil {
+		return "", ""
+	}
+
+	typ := primaryType(schema["type"])
+	if typ != "array" {
+		return typ, ""
+	}
+
+	if _, ok := schema["items"].(map[string]interface{}); !ok {
+		return typ, ""
+	}
+
+	_, items, err := o.resolve(append(tokens[:len(tokens):len(tokens)], "items"))
+	if err != nil {
+		return typ, ""
+	}
+
+	return typ, primaryType(items["type"])
+}
+
+// primaryType returns the first type other than "null" of a "type" keyword.
+func primaryType(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case []interface{}:
+		for _, typ := range value {
+			if typ, ok := typ.(string); ok && typ != "null" {
+				return typ
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseParameter converts a parameter into the given type. Values which cannot
+// be converted are left as strings, for the schema to reject.
+func parseParameter(value, typ string) interface{} {
+	switch typ {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
+
+// matchPathTemplate extracts the path parameters of a path matching a
+// template such as "/pets/{id}". The path may have a prefix, such as the base
+// path of a server. If the path does not match, nil is returned.
+func matchPathTemplate(template, path string) map[string]string {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(pathSegments) < len(templateSegments) {
+		return nil
+	}
+
+	pathSegments = pathSegments[len(pathSegments)-len(templateSegments):]
+
+	params := map[string]string{}
+	for i, segment := range templateSegments {
+		start, end := strings.Index(segment, "{"), strings.Index(segment, "}")
+		if start == -1 || end < start {
+			if segment != pathSegments[i] {
+				return nil
+			}
+
+			continue
+		}
+
+		prefix, suffix := segment[:start], segment[end+1:]
+		value := pathSegments[i]
+		if !strings.HasPrefix(value, prefix) || !strings.HasSuffix(value, suffix) || len(value) < len(prefix)+len(suffix) {
+			return nil
+		}
+
+		value, err := url.PathUnescape(value[len(prefix) : len(value)-len(suffix)])
+		if err != nil {
+			return nil
+		}
+
+		params[segment[start+1:end]] = value
+	}
+
+	return params
+}
+
+// matchMediaType finds the key of content which best matches a Content-Type:
+// the same media type, then a range such as "application/*", then "*/*".
+func matchMediaType(content map[string]interface{}, contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	ranges := []string{mediaType, mediaType[:strings.Index(mediaType, "/")+1] + "*", "*/*"}
+	for _, want := range ranges {
+		for _, key := range sortedKeys(content) {
+			if keyType, _, err := mime.ParseMediaType(key); err == nil && keyType == want {
+				return key, true
+			}
+		}
+	}
+
+	return "", false
+}
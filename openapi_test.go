@@ -0,0 +1,264 @@
+package jsonschema
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const openAPIPetstore = `
+openapi: 3.0.3
+info: {title: Petstore, version: "1"}
+paths:
+  /pets/{petId}:
+    parameters:
+      - {name: petId, in: path, required: true, schema: {type: integer}}
+    get:
+      operationId: getPet
+      parameters:
+        - {name: verbose, in: query, schema: {type: boolean}}
+        - $ref: '#/components/parameters/Fields'
+      responses:
+        "200":
+          headers:
+            X-Rate-Limit: {required: true, schema: {type: integer}}
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        4XX:
+          content:
+            application/problem+json:
+              schema: {type: object, required: [title]}
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201": {description: created}
+  /animals:
+    post:
+      operationId: createAnimal
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Animal'}
+      responses:
+        default: {description: anything}
+components:
+  parameters:
+    Fields:
+      name: fields
+      in: query
+      schema: {type: array, items: {type: string, enum: [id, name]}}
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string, nullable: true}
+        password: {type: string, writeOnly: true}
+        weight: {type: number, minimum: 0, exclusiveMinimum: true}
+        tag: {$ref: '#/components/schemas/Tag'}
+    Tag:
+      type: string
+      maxLength: 3
+      example: dog
+    Animal:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: kind
+        mapping:
+          hound: '#/components/schemas/Dog'
+    Cat:
+      type: object
+      required: [meows]
+    Dog:
+      type: object
+      required: [barks]
+`
+
+func loadPetstore(t *testing.T) *OpenAPI {
+	openAPI, err := LoadOpenAPI(Source{Name: "petstore.yaml", Data: []byte(openAPIPetstore)}, OpenAPIConfig{})
+	assert.NoError(t, err)
+	return openAPI
+}
+
+// openAPIErrors formats errors as "instancePath uri#schemaPath", for brevity.
+func openAPIErrors(result ValidationResult) []string {
+	errors := []string{}
+	for _, err := range result.Errors {
+		errors = append(errors, err.InstancePath.String()+" "+err.URI.String()+"#"+err.SchemaPath.String())
+	}
+
+	return errors
+}
+
+func TestOpenAPIValidateRequest(t *testing.T) {
+	openAPI := loadPetstore(t)
+
+	testCases := []struct {
+		operationID string
+		method      string
+		target      string
+		contentType string
+		body        string
+		errors      []string
+	}{
+		{"getPet", "GET", "/v1/pets/12?verbose=true&fields=id&fields=name", "", "", []string{}},
+		{"getPet", "GET", "/pets/x?verbose=maybe&fields=age", "", "", []string{
+			"/path/petId #/paths/~1pets~1{petId}/parameters/0/schema/type",
+			"/query/verbose #/paths/~1pets~1{petId}/get/parameters/0/schema/type",
+			"/query/fields/0 #/components/parameters/Fields/schema/items/enum",
+		}},
+		{"getPet", "GET", "/other", "", "", []string{
+			"/path/petId #/paths/~1pets~1{petId}/parameters/0/required",
+		}},
+		{"createPet", "POST", "/pets", "application/json", `{"name": null, "weight": 1, "tag": "cat"}`, []string{}},
+		{"createPet", "POST", "/pets", "application/json", `{"id": 1, "name": "x", "weight": 0, "tag": "lion"}`, []string{
+			"/body/id #/components/schemas/Pet/properties/id",
+			"/body/tag #/components/schemas/Tag/maxLength",
+			"/body/weight #/components/schemas/Pet/properties/weight/exclusiveMinimum",
+		}},
+		{"createPet", "POST", "/pets", "application/json", ``, []string{
+			"/body #/paths/~1pets/post/requestBody/required",
+		}},
+		{"createPet", "POST", "/pets", "text/plain", `name`, []string{
+			"/body #/paths/~1pets/post/requestBody/content",
+		}},
+		{"createAnimal", "POST", "/animals", "application/json", `{"kind": "hound", "barks": true}`, []string{}},
+		{"createAnimal", "POST", "/animals", "application/json", `{"kind": "Cat", "meows": true}`, []string{}},
+		{"createAnimal", "POST", "/animals", "application/json", `{"kind": "hound"}`, []string{
+			"/body #/components/schemas/Dog/required/0",
+		}},
+		{"createAnimal", "POST", "/animals", "application/json", `{"kind": "Dog", "barks": true}`, []string{
+			"/body/kind #/components/schemas/Animal/allOf/0/properties/kind/enum",
+		}},
+		{"createAnimal", "POST", "/animals", "application/json", `{}`, []string{
+			"/body #/components/schemas/Animal/allOf/0/required/0",
+			"/body #/components/schemas/Animal/oneOf",
+		}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.target+" "+tt.body, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			result, err := openAPI.ValidateRequest(tt.operationID, r)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.errors, openAPIErrors(result))
+
+			// the body can be read again
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
+
+func TestOpenAPIValidateResponse(t *testing.T) {
+	openAPI := loadPetstore(t)
+
+	header := func(pairs ...string) http.Header {
+		h := http.Header{}
+		for i := 0; i < len(pairs); i += 2 {
+			h.Set(pairs[i], pairs[i+1])
+		}
+
+		return h
+	}
+
+	testCases := []struct {
+		operationID string
+		status      int
+		header      http.Header
+		body        string
+		errors      []string
+	}{
+		{"getPet", 200, header("Content-Type", "application/json", "X-Rate-Limit", "10"), `{"id": 1, "name": "x"}`, []string{}},
+		{"getPet", 200, header("Content-Type", "application/json", "X-Rate-Limit", "ten"), `{"name": "x", "password": "p"}`, []string{
+			"/header/X-Rate-Limit #/paths/~1pets~1{petId}/get/responses/200/headers/X-Rate-Limit/schema/type",
+			"/body #/components/schemas/Pet/required/0",
+			"/body/password #/components/schemas/Pet/properties/password",
+		}},
+		{"getPet", 200, header("Content-Type", "application/json"), `{"id": 1, "name": "x"}`, []string{
+			"/header/X-Rate-Limit #/paths/~1pets~1{petId}/get/responses/200/headers/X-Rate-Limit/required",
+		}},
+		{"getPet", 404, header("Content-Type", "application/problem+json"), `{}`, []string{
+			"/body #/paths/~1pets~1{petId}/get/responses/4XX/content/application~1problem+json/schema/required/0",
+		}},
+		{"getPet", 500, header(), ``, []string{
+			"/status #/paths/~1pets~1{petId}/get/responses",
+		}},
+		{"createPet", 201, header(), ``, []string{}},
+		{"createAnimal", 503, header("Content-Type", "text/plain"), `unavailable`, []string{}},
+	}
+
+	for _, tt := range testCases {
+		result, err := openAPI.ValidateResponse(tt.operationID, tt.status, tt.header, []byte(tt.body))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.errors, openAPIErrors(result), "%s %d", tt.operationID, tt.status)
+	}
+}
+
+func TestOpenAPIValidators(t *testing.T) {
+	openAPI := loadPetstore(t)
+
+	// readOnly properties are required in responses, and forbidden in requests
+	pet := mustDecode(t, `{"id": 1, "name": "x"}`)
+
+	result, err := openAPI.ResponseValidator().ValidateURI(openAPI.SchemaURI("Pet"), pet)
+	assert.NoError(t, err)
+	assert.True(t, result.IsValid())
+
+	result, err = openAPI.RequestValidator().ValidateURI(openAPI.SchemaURI("Pet"), pet)
+	assert.NoError(t, err)
+	assert.False(t, result.IsValid())
+
+	// errors point into the document
+	pos, ok := openAPI.RequestValidator().SchemaPosition(result.Errors[0].URI, result.Errors[0].SchemaPath)
+	assert.True(t, ok)
+	assert.Equal(t, "petstore.yaml:54:13", pos.String())
+}
+
+func TestOpenAPIErrors(t *testing.T) {
+	openAPI := loadPetstore(t)
+
+	_, err := openAPI.ValidateRequest("deletePet", httptest.NewRequest("DELETE", "/pets/1", nil))
+	assert.Equal(t, ErrNoSuchOperation, err)
+
+	_, err = openAPI.ValidateResponse("deletePet", 200, http.Header{}, nil)
+	assert.Equal(t, ErrNoSuchOperation, err)
+
+	r := httptest.NewRequest("POST", "/pets", strings.NewReader(`{"name":`))
+	r.Header.Set("Content-Type", "application/json")
+	_, err = openAPI.ValidateRequest("createPet", r)
+	assert.IsType(t, ErrSyntax{}, err)
+
+	testCases := []struct {
+		document string
+		err      string
+	}{
+		{`{"swagger": "2.0"}`, `jsonschema: unsupported OpenAPI version ""`},
+		{`{"openapi": "3.0.0", "paths": {"/a": {"get": {"operationId": "a"}, "put": {"operationId": "a"}}}}`, `jsonschema: duplicate operationId "a" in OpenAPI document`},
+		{`{"openapi": "3.1.0", "paths": {"/a": {"get": {"operationId": "a", "requestBody": {"$ref": "other.json#/body"}}}}}`, `jsonschema: cannot resolve "other.json#/body" in OpenAPI document: only references within the document are supported`},
+	}
+
+	for _, tt := range testCases {
+		_, err := NewOpenAPI(mustDecode(t, tt.document), OpenAPIConfig{})
+		assert.EqualError(t, err, tt.err)
+	}
+}
@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"net/url"
 	"regexp"
@@ -12,19 +13,28 @@ import (
 
 type parser struct {
 	registry *registry
+	keywords map[string]KeywordCompiler
+	source   *SourceMap
 	baseURI  url.URL
 	tokens   []string
+
+	// discriminators indicates whether the "discriminator" keyword of OpenAPI
+	// is parsed. It is not part of JSON Schema, and is otherwise ignored.
+	discriminators bool
 }
 
-func parseRootSchema(registry *registry, input interface{}) (schema, error) {
-	return parseSubSchema(registry, url.URL{}, []string{}, input)
+func parseRootSchema(registry *registry, keywords map[string]KeywordCompiler, discriminators bool, source *SourceMap, input interface{}) (schema, error) {
+	return parseSubSchema(registry, keywords, discriminators, source, url.URL{}, []string{}, input)
 }
 
-func parseSubSchema(registry *registry, baseURI url.URL, tokens []string, input interface{}) (schema, error) {
+func parseSubSchema(registry *registry, keywords map[string]KeywordCompiler, discriminators bool, source *SourceMap, baseURI url.URL, tokens []string, input interface{}) (schema, error) {
 	p := parser{
-		registry: registry,
-		tokens:   tokens,
-		baseURI:  baseURI,
+		registry:       registry,
+		keywords:       keywords,
+		source:         source,
+		tokens:         tokens,
+		baseURI:        baseURI,
+		discriminators: discriminators,
 	}
 
 	index, err := p.Parse(input)
@@ -32,7 +42,7 @@ func parseSubSchema(registry *registry, baseURI url.URL, tokens []string, input
 		return schema{}, err
 	}
 
-	return registry.GetIndex(index), nil
+	return *registry.GetIndex(index), nil
 }
 
 func (p *parser) Push(token string) {
@@ -51,6 +61,25 @@ func (p *parser) URI() url.URL {
 	return url
 }
 
+func (p *parser) location() KeywordLocation {
+	tokens := make([]string, len(p.tokens))
+	copy(tokens, p.tokens)
+
+	uri := p.baseURI
+	uri.Fragment = ""
+	return KeywordLocation{URI: uri, Path: jsonpointer.Ptr{Tokens: tokens}}
+}
+
+// keys returns the keywords of a schema in the order in which they appear in
+// its source, or in sorted order if the source is not known.
+func (p *parser) keys(input map[string]interface{}) []string {
+	if keys, ok := p.source.Keys(jsonpointer.Ptr{Tokens: p.tokens}); ok {
+		return keys
+	}
+
+	return sortedKeys(input)
+}
+
 func (p *parser) Parse(input interface{}) (int, error) {
 	s := schema{}
 
@@ -252,6 +281,7 @@ func (p *parser) Parse(input interface{}) (int, error) {
 
 			s.Enum.IsSet = true
 			s.Enum.Values = enumArray
+			s.Enum.Set = newJSONSet(enumArray)
 		}
 
 		multipleOfValue, ok := input["multipleOf"]
@@ -725,6 +755,54 @@ func (p *parser) Parse(input interface{}) (int, error) {
 
 			p.Pop()
 		}
+
+		// "discriminator" is ignored if malformed, as OpenAPI does not require it
+		// to be validated
+		if discriminator, ok := input["discriminator"].(map[string]interface{}); ok && p.discriminators {
+			if property, ok := discriminator["propertyName"].(string); ok {
+				s.Discriminator.IsSet = true
+				s.Discriminator.Property = property
+				s.Discriminator.Mapping = map[string]url.URL{}
+
+				mapping, _ := discriminator["mapping"].(map[string]interface{})
+				for value, target := range mapping {
+					targetStr, ok := target.(string)
+					if !ok {
+						continue
+					}
+
+					if uri, err := p.baseURI.Parse(discriminatorTarget(targetStr)); err == nil {
+						s.Discriminator.Mapping[value] = *uri
+					}
+				}
+			}
+		}
+
+		for _, name := range p.keys(input) {
+			compiler, ok := p.keywords[name]
+			if !ok {
+				continue
+			}
+
+			p.Push(name)
+
+			location := p.location()
+			evaluator, err := compiler(input[name], location)
+			if err != nil {
+				return -1, SchemaError{
+					URI:     location.URI,
+					Path:    location.Path,
+					Message: fmt.Sprintf("invalid value for keyword %q: %v", name, err),
+				}
+			}
+
+			s.Keywords = append(s.Keywords, schemaKeyword{
+				Name:      name,
+				Evaluator: evaluator,
+			})
+
+			p.Pop()
+		}
 	default:
 		return -1, ErrInvalidSchema
 	}
@@ -0,0 +1,450 @@
+package jsonschema
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// opcode identifies the check an instruction performs. Each opcode corresponds
+// to a keyword, except for opFalse, which corresponds to the "false" schema.
+type opcode int
+
+const (
+	opFalse opcode = iota
+	opRef
+	opNot
+	opIf
+	opConst
+	opEnum
+	opAllOf
+	opAnyOf
+	opOneOf
+	opType
+	opMultipleOf
+	opMaximum
+	opMinimum
+	opExclusiveMaximum
+	opExclusiveMinimum
+	opMaxLength
+	opMinLength
+	opPattern
+	opMaxItems
+	opMinItems
+	opUniqueItems
+	opContains
+	opItems
+	opMaxProperties
+	opMinProperties
+	opRequired
+	opProperties
+	opDependencies
+	opPropertyNames
+	opKeyword
+)
+
+// opcodeCosts is a rough ranking of how expensive each opcode is to evaluate.
+// Checks which only look at the instance itself come first; checks which
+// evaluate subschemas come last.
+var opcodeCosts = [...]int{
+	opFalse:            0,
+	opRef:              4,
+	opNot:              5,
+	opIf:               5,
+	opConst:            1,
+	opEnum:             2,
+	opAllOf:            5,
+	opAnyOf:            5,
+	opOneOf:            6,
+	opType:             0,
+	opMultipleOf:       1,
+	opMaximum:          0,
+	opMinimum:          0,
+	opExclusiveMaximum: 0,
+	opExclusiveMinimum: 0,
+	opMaxLength:        1,
+	opMinLength:        1,
+	opPattern:          3,
+	opMaxItems:         0,
+	opMinItems:         0,
+	opUniqueItems:      3,
+	opContains:         5,
+	opItems:            5,
+	opMaxProperties:    0,
+	opMinProperties:    0,
+	opRequired:         1,
+	opProperties:       5,
+	opDependencies:     4,
+	opPropertyNames:    5,
+	opKeyword:          6,
+}
+
+// instruction is a single step in the program of a schema.
+type instruction struct {
+	op opcode
+
+	// keyword is, for opKeyword, the index of the custom keyword in the
+	// Keywords of the schema
+	keyword int
+}
+
+// instanceKind is the kind of JSON value an instance is. Programs have a list
+// of instructions for each kind, so that keywords which do not apply to an
+// instance are never visited.
+type instanceKind int
+
+const (
+	kindNull instanceKind = iota
+	kindBoolean
+	kindNumber
+	kindString
+	kindArray
+	kindObject
+	kindCount
+)
+
+func kindOf(instance interface{}) instanceKind {
+	switch instance.(type) {
+	case nil:
+		return kindNull
+	case bool:
+		return kindBoolean
+	case float64:
+		return kindNumber
+	case string:
+		return kindString
+	case []interface{}:
+		return kindArray
+	case map[string]interface{}:
+		return kindObject
+	default:
+		// TODO a better error here
+		panic("unexpected non-json input")
+	}
+}
+
+// schemaProgram is a schema compiled into a flat list of instructions, holding
+// only the keywords present in the schema.
+type schemaProgram struct {
+	// code holds, for each kind of instance, the instructions to run in the
+	// order in which keywords report errors.
+	code [kindCount][]instruction
+
+	// fast holds the same instructions as code, ordered cheapest-first. It is
+	// used when only the validity of an instance matters, and not which errors
+	// it produces.
+	fast [kindCount][]instruction
+
+	// patternProperties holds the "patternProperties" of the schema, sorted by
+	// pattern, so that they are evaluated in a stable order.
+	patternProperties []patternProperty
+
+	// dependencies holds the property names of "dependencies", sorted.
+	dependencies []string
+
+	// oneOf, if non-nil, picks the alternative of "oneOf" an object instance
+	// must match from the value of one of its properties.
+	oneOf *oneOfDispatch
+}
+
+// oneOfDispatch picks the alternative of "oneOf" to evaluate an object against
+// from the value of a property, so that the other alternatives need not be
+// evaluated.
+type oneOfDispatch struct {
+	property string
+
+	// alternatives holds the index of the alternative for each value of the
+	// property
+	alternatives map[string]int
+}
+
+// alternative returns the index of the alternative for an instance. If the
+// instance is not an object, or it has no known value for the property, ok is
+// false, and every alternative must be evaluated.
+func (d *oneOfDispatch) alternative(instance interface{}) (index int, ok bool) {
+	object, ok := instance.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+
+	value, ok := object[d.property].(string)
+	if !ok {
+		return 0, false
+	}
+
+	index, ok = d.alternatives[value]
+	return index, ok
+}
+
+type patternProperty struct {
+	pattern *regexp.Regexp
+	schema  int
+}
+
+// compileProgram compiles a schema into a schemaProgram.
+func compileProgram(s *schema) schemaProgram {
+	var p schemaProgram
+
+	for pattern, index := range s.PatternProperties.Schemas {
+		p.patternProperties = append(p.patternProperties, patternProperty{pattern: pattern, schema: index})
+	}
+
+	sort.Slice(p.patternProperties, func(i, j int) bool {
+		return p.patternProperties[i].pattern.String() < p.patternProperties[j].pattern.String()
+	})
+
+	for key := range s.Dependencies.Deps {
+		p.dependencies = append(p.dependencies, key)
+	}
+
+	sort.Strings(p.dependencies)
+
+	if s.Bool.IsSet {
+		if !s.Bool.Value {
+			for kind := instanceKind(0); kind < kindCount; kind++ {
+				p.code[kind] = []instruction{{op: opFalse}}
+			}
+		}
+
+		p.fast = p.code
+		return p
+	}
+
+	// instructions which apply regardless of the kind of instance
+	common := []instruction{}
+	if s.Ref.IsSet {
+		common = append(common, instruction{op: opRef})
+	}
+
+	if s.Not.IsSet {
+		common = append(common, instruction{op: opNot})
+	}
+
+	if s.If.IsSet {
+		common = append(common, instruction{op: opIf})
+	}
+
+	if s.Const.IsSet {
+		common = append(common, instruction{op: opConst})
+	}
+
+	if s.Enum.IsSet {
+		common = append(common, instruction{op: opEnum})
+	}
+
+	if s.AllOf.IsSet {
+		common = append(common, instruction{op: opAllOf})
+	}
+
+	if s.AnyOf.IsSet {
+		common = append(common, instruction{op: opAnyOf})
+	}
+
+	if s.OneOf.IsSet {
+		common = append(common, instruction{op: opOneOf})
+	}
+
+	if s.Type.IsSet {
+		common = append(common, instruction{op: opType})
+	}
+
+	var specific [kindCount][]instruction
+	add := func(kind instanceKind, isSet bool, op opcode) {
+		if isSet {
+			specific[kind] = append(specific[kind], instruction{op: op})
+		}
+	}
+
+	add(kindNumber, s.MultipleOf.IsSet, opMultipleOf)
+	add(kindNumber, s.Maximum.IsSet, opMaximum)
+	add(kindNumber, s.Minimum.IsSet, opMinimum)
+	add(kindNumber, s.ExclusiveMaximum.IsSet, opExclusiveMaximum)
+	add(kindNumber, s.ExclusiveMinimum.IsSet, opExclusiveMinimum)
+
+	add(kindString, s.MaxLength.IsSet, opMaxLength)
+	add(kindString, s.MinLength.IsSet, opMinLength)
+	add(kindString, s.Pattern.IsSet, opPattern)
+
+	add(kindArray, s.MaxItems.IsSet, opMaxItems)
+	add(kindArray, s.MinItems.IsSet, opMinItems)
+	add(kindArray, s.UniqueItems.IsSet && s.UniqueItems.Value, opUniqueItems)
+	add(kindArray, s.Contains.IsSet, opContains)
+	add(kindArray, s.Items.IsSet, opItems)
+
+	add(kindObject, s.MaxProperties.IsSet, opMaxProperties)
+	add(kindObject, s.MinProperties.IsSet, opMinProperties)
+	add(kindObject, s.Required.IsSet, opRequired)
+	add(kindObject, s.Properties.IsSet || s.PatternProperties.IsSet || s.AdditionalProperties.IsSet, opProperties)
+	add(kindObject, s.Dependencies.IsSet, opDependencies)
+	add(kindObject, s.PropertyNames.IsSet, opPropertyNames)
+
+	for kind := instanceKind(0); kind < kindCount; kind++ {
+		code := make([]instruction, 0, len(common)+len(specific[kind])+len(s.Keywords))
+		code = append(code, common...)
+		code = append(code, specific[kind]...)
+
+		for i := range s.Keywords {
+			code = append(code, instruction{op: opKeyword, keyword: i})
+		}
+
+		fast := make([]instruction, len(code))
+		copy(fast, code)
+		sort.SliceStable(fast, func(i, j int) bool {
+			return opcodeCosts[fast[i].op] < opcodeCosts[fast[j].op]
+		})
+
+		p.code[kind] = code
+		p.fast[kind] = fast
+	}
+
+	return p
+}
+
+// compileOneOfDispatch finds how to pick the alternative of "oneOf" for an
+// instance, if it can be done. It must be called once references have been
+// resolved.
+//
+// With a "discriminator", which is only parsed in OpenAPI documents, the
+// alternative is picked by its mapping, or by the name of the schema an
+// alternative refers to, as OpenAPI specifies. The other alternatives are not
+// evaluated, so an instance which matches them too is nonetheless accepted.
+//
+// Otherwise, if every alternative requires a different string value of the
+// same property with "const" or a one-valued "enum", the alternative is picked
+// by that value. Every other alternative would reject the instance, so this
+// does not change the outcome of "oneOf".
+func compileOneOfDispatch(r *registry, s *schema) *oneOfDispatch {
+	if !s.OneOf.IsSet || len(s.OneOf.Schemas) < 2 {
+		return nil
+	}
+
+	if s.Discriminator.IsSet {
+		return compileDiscriminator(r, s)
+	}
+
+	// the string values each alternative requires of each property
+	required := make([]map[string]string, len(s.OneOf.Schemas))
+	for i, index := range s.OneOf.Schemas {
+		required[i] = map[string]string{}
+		collectTagValues(r, r.GetIndex(index), required[i], 0)
+	}
+
+	for _, property := range sortedStringKeys(required[0]) {
+		d := oneOfDispatch{property: property, alternatives: map[string]int{}}
+		for i, values := range required {
+			value, ok := values[property]
+			if !ok {
+				break
+			}
+
+			if _, ok := d.alternatives[value]; ok {
+				break
+			}
+
+			d.alternatives[value] = i
+		}
+
+		if len(d.alternatives) == len(required) {
+			return &d
+		}
+	}
+
+	return nil
+}
+
+func compileDiscriminator(r *registry, s *schema) *oneOfDispatch {
+	d := oneOfDispatch{property: s.Discriminator.Property, alternatives: map[string]int{}}
+
+	mapped := map[url.URL]bool{}
+	for _, uri := range s.Discriminator.Mapping {
+		mapped[uri] = true
+	}
+
+	for i, index := range s.OneOf.Schemas {
+		alternative := r.GetIndex(index)
+		if !alternative.Ref.IsSet {
+			continue
+		}
+
+		for value, uri := range s.Discriminator.Mapping {
+			if uri == alternative.Ref.URI {
+				d.alternatives[value] = i
+			}
+		}
+
+		// alternatives not in the mapping are picked by the name of the schema
+		// they refer to
+		tokens := alternative.Ref.Ptr.Tokens
+		if !mapped[alternative.Ref.URI] && len(tokens) > 0 {
+			if _, ok := d.alternatives[tokens[len(tokens)-1]]; !ok {
+				d.alternatives[tokens[len(tokens)-1]] = i
+			}
+		}
+	}
+
+	if len(d.alternatives) == 0 {
+		return nil
+	}
+
+	return &d
+}
+
+// collectTagValues finds the properties for which a schema requires a single
+// string value, through "properties", "$ref", and "allOf".
+func collectTagValues(r *registry, s *schema, values map[string]string, depth int) {
+	if depth > DefaultMaxStackDepth {
+		return
+	}
+
+	for property, index := range s.Properties.Schemas {
+		if value, ok := singleStringValue(r.GetIndex(index)); ok {
+			values[property] = value
+		}
+	}
+
+	if s.Ref.IsSet {
+		collectTagValues(r, r.GetIndex(s.Ref.Schema), values, depth+1)
+	}
+
+	for _, index := range s.AllOf.Schemas {
+		collectTagValues(r, r.GetIndex(index), values, depth+1)
+	}
+}
+
+// singleStringValue returns the string a schema requires with "const", or with
+// an "enum" of one value.
+func singleStringValue(s *schema) (string, bool) {
+	if s.Const.IsSet {
+		value, ok := s.Const.Value.(string)
+		return value, ok
+	}
+
+	if s.Enum.IsSet && len(s.Enum.Values) == 1 {
+		value, ok := s.Enum.Values[0].(string)
+		return value, ok
+	}
+
+	return "", false
+}
+
+// discriminatorTarget converts a value of a discriminator mapping, which is
+// either a reference or the name of a schema in "components/schemas", into a
+// reference.
+func discriminatorTarget(target string) string {
+	if strings.ContainsAny(target, "#/") {
+		return target
+	}
+
+	return "#/components/schemas/" + target
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
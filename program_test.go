@@ -0,0 +1,311 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucarion/json-pointer"
+)
+
+func TestCompileProgram(t *testing.T) {
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{
+			"anyOf":     []interface{}{true, false},
+			"pattern":   "^a",
+			"maxLength": 3.0,
+			"type":      "string",
+			"minimum":   1.0,
+		},
+	})
+	assert.NoError(t, err)
+
+	schema, ok := validator.registry.Get(validator.registry.arena.schemas[0].ID)
+	assert.True(t, ok)
+
+	ops := func(code []instruction) []opcode {
+		out := make([]opcode, len(code))
+		for i, inst := range code {
+			out[i] = inst.op
+		}
+
+		return out
+	}
+
+	assert.Equal(t, []opcode{opAnyOf, opType, opMaxLength, opPattern}, ops(schema.Program.code[kindString]))
+	assert.Equal(t, []opcode{opType, opMaxLength, opPattern, opAnyOf}, ops(schema.Program.fast[kindString]))
+	assert.Equal(t, []opcode{opAnyOf, opType, opMinimum}, ops(schema.Program.code[kindNumber]))
+	assert.Equal(t, []opcode{opAnyOf, opType}, ops(schema.Program.code[kindObject]))
+
+	falseSchema := validator.registry.GetIndex(schema.AnyOf.Schemas[1])
+	for kind := instanceKind(0); kind < kindCount; kind++ {
+		assert.Equal(t, []opcode{opFalse}, ops(falseSchema.Program.code[kind]))
+	}
+}
+
+func TestValidatorFirstError(t *testing.T) {
+	schema := mustDecode(t, `{"anyOf": [{"minLength": 5}, {"pattern": "^x"}], "maxLength": 1}`)
+
+	validator, err := NewValidator([]interface{}{schema})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate("abc")
+	assert.NoError(t, err)
+	assert.Len(t, result.Errors, 2)
+	assert.Equal(t, jsonpointer.Ptr{Tokens: []string{"anyOf"}}, result.Errors[0].SchemaPath)
+
+	// when only one error is wanted, cheap keywords are evaluated first
+	validator, err = NewValidatorWithConfig([]interface{}{schema}, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		MaxErrors:     1,
+	})
+	assert.NoError(t, err)
+
+	result, err = validator.Validate("abc")
+	assert.NoError(t, err)
+	assert.True(t, result.Overflowed)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"maxLength"}},
+		},
+	}, result.Errors)
+
+	// the causes of errors are still reported in full
+	result, err = validator.Validate("a")
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf"}},
+			Causes: []ValidationError{
+				{
+					InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+					SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf", "0", "minLength"}},
+				},
+				{
+					InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+					SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf", "1", "pattern"}},
+				},
+			},
+		},
+	}, result.Errors)
+
+	// "not" comes before "type" in error order, but costs more
+	schema = mustDecode(t, `{"not": {}, "type": "string"}`)
+	for maxErrors, expected := range map[int][]string{0: {"/not", "/type"}, 1: {"/type"}, 2: {"/not", "/type"}} {
+		validator, err = NewValidatorWithConfig([]interface{}{schema}, ValidatorConfig{
+			MaxStackDepth: DefaultMaxStackDepth,
+			MaxErrors:     maxErrors,
+		})
+		assert.NoError(t, err)
+
+		result, err = validator.Validate(1.0)
+		assert.NoError(t, err)
+
+		paths := []string{}
+		for _, validationError := range result.Errors {
+			paths = append(paths, validationError.SchemaPath.String())
+		}
+
+		assert.Equal(t, expected, paths, "MaxErrors %d", maxErrors)
+		assert.Equal(t, maxErrors == 1, result.Overflowed, "MaxErrors %d", maxErrors)
+	}
+}
+
+func TestValidatorQuickMode(t *testing.T) {
+	// the subschemas of "not" and "if" quit at their first error, which must
+	// leave the vm as it was before evaluating them
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{
+			"definitions": map[string]interface{}{
+				"positive": map[string]interface{}{
+					"items": map[string]interface{}{"minimum": 1.0},
+				},
+			},
+			"not": map[string]interface{}{
+				"$ref":     "#/definitions/positive",
+				"maxItems": 1.0,
+			},
+			"if": map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{"$ref": "#/definitions/positive"},
+					map[string]interface{}{"contains": map[string]interface{}{"const": 0.0}},
+				},
+			},
+			"then": map[string]interface{}{"maxItems": 2.0},
+			"else": map[string]interface{}{"items": map[string]interface{}{"type": "integer"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate([]interface{}{-1.0, 2.5, 3.0})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{"1"}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"else", "items", "type"}},
+		},
+	}, result.Errors)
+
+	result, err = validator.Validate([]interface{}{1.0, 2.0, 3.0})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"then", "maxItems"}},
+		},
+	}, result.Errors)
+
+	result, err = validator.Validate([]interface{}{1.0})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"not"}},
+		},
+	}, result.Errors)
+}
+
+func TestCompileOneOfDispatch(t *testing.T) {
+	testCases := []struct {
+		schema   string
+		property string
+		values   map[string]int
+	}{
+		{`{"oneOf": [{"properties": {"kind": {"const": "a"}}}, {"properties": {"kind": {"enum": ["b"]}}}]}`, "kind", map[string]int{"a": 0, "b": 1}},
+		{`{
+			"definitions": {"b": {"allOf": [{"properties": {"kind": {"const": "b"}}}]}},
+			"oneOf": [{"properties": {"kind": {"const": "a"}}}, {"$ref": "#/definitions/b"}]
+		}`, "kind", map[string]int{"a": 0, "b": 1}},
+		{`{"oneOf": [
+			{"properties": {"kind": {"const": "a"}, "type": {"const": "x"}}},
+			{"properties": {"kind": {"const": "b"}, "type": {"const": "x"}}}
+		]}`, "kind", map[string]int{"a": 0, "b": 1}},
+		{`{"oneOf": [{"properties": {"kind": {"const": "a"}}}, {"properties": {"kind": {"const": "a"}}}]}`, "", nil},
+		{`{"oneOf": [{"properties": {"kind": {"const": "a"}}}, {"properties": {"kind": {"enum": ["b", "c"]}}}]}`, "", nil},
+		{`{"oneOf": [{"properties": {"kind": {"const": 1}}}, {"properties": {"kind": {"const": 2}}}]}`, "", nil},
+		{`{"oneOf": [{"properties": {"kind": {"const": "a"}}}]}`, "", nil},
+	}
+
+	for _, tt := range testCases {
+		validator, err := NewValidator([]interface{}{mustDecode(t, tt.schema)})
+		assert.NoError(t, err)
+
+		schema, ok := validator.registry.Get(validator.registry.arena.schemas[0].ID)
+		assert.True(t, ok)
+
+		if tt.values == nil {
+			assert.Nil(t, schema.Program.oneOf, tt.schema)
+		} else if assert.NotNil(t, schema.Program.oneOf, tt.schema) {
+			assert.Equal(t, tt.property, schema.Program.oneOf.property)
+			assert.Equal(t, tt.values, schema.Program.oneOf.alternatives)
+		}
+	}
+}
+
+func TestCompileDiscriminator(t *testing.T) {
+	schema := mustDecode(t, `{
+		"definitions": {"Cat": {}, "Dog": {}},
+		"oneOf": [{"$ref": "#/definitions/Cat"}, {"$ref": "#/definitions/Dog"}],
+		"discriminator": {"propertyName": "pet", "mapping": {"hound": "#/definitions/Dog"}}
+	}`)
+
+	// "discriminator" is not part of JSON Schema, so both alternatives match
+	validator, err := NewValidator([]interface{}{schema})
+	assert.NoError(t, err)
+
+	root, ok := validator.registry.Get(validator.registry.arena.schemas[0].ID)
+	assert.True(t, ok)
+	assert.Nil(t, root.Program.oneOf)
+
+	result, err := validator.Validate(mustDecode(t, `{"pet": "Cat"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf"}},
+			Matches:      []int{0, 1},
+		},
+	}, result.Errors)
+
+	ok, err = validator.IsValid(mustDecode(t, `{"pet": "Cat"}`))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// in OpenAPI documents, it picks the alternative
+	validator = newValidator(ValidatorConfig{MaxStackDepth: DefaultMaxStackDepth})
+	validator.discriminators = true
+	assert.NoError(t, validator.seal([]interface{}{schema}, []*SourceMap{nil}))
+
+	root, ok = validator.registry.Get(validator.registry.arena.schemas[0].ID)
+	assert.True(t, ok)
+	if assert.NotNil(t, root.Program.oneOf) {
+		assert.Equal(t, "pet", root.Program.oneOf.property)
+		assert.Equal(t, map[string]int{"Cat": 0, "hound": 1}, root.Program.oneOf.alternatives)
+	}
+
+	ok, err = validator.IsValid(mustDecode(t, `{"pet": "Cat"}`))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestValidatorOneOfDispatch(t *testing.T) {
+	validator, err := NewValidator([]interface{}{mustDecode(t, `{
+		"oneOf": [
+			{"properties": {"kind": {"const": "circle"}, "radius": {"type": "number"}}, "required": ["radius"]},
+			{"properties": {"kind": {"const": "square"}, "side": {"type": "number"}}, "required": ["side"]}
+		]
+	}`)})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		instance string
+		errors   []ValidationError
+	}{
+		{`{"kind": "square", "side": 1}`, []ValidationError{}},
+		// the errors of the alternative picked by "kind" are reported as they are
+		{`{"kind": "square", "side": "x"}`, []ValidationError{
+			{
+				InstancePath: jsonpointer.Ptr{Tokens: []string{"side"}},
+				SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf", "1", "properties", "side", "type"}},
+			},
+		}},
+		{`{"kind": "circle"}`, []ValidationError{
+			{
+				InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+				SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf", "0", "required", "0"}},
+			},
+		}},
+		// without a known value of "kind", every alternative is evaluated
+		{`{"kind": "triangle"}`, []ValidationError{
+			{
+				InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+				SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf"}},
+				Causes: []ValidationError{
+					{
+						InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+						SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf", "0", "required", "0"}},
+					},
+					{
+						InstancePath: jsonpointer.Ptr{Tokens: []string{"kind"}},
+						SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf", "0", "properties", "kind", "const"}},
+					},
+					{
+						InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+						SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf", "1", "required", "0"}},
+					},
+					{
+						InstancePath: jsonpointer.Ptr{Tokens: []string{"kind"}},
+						SchemaPath:   jsonpointer.Ptr{Tokens: []string{"oneOf", "1", "properties", "kind", "const"}},
+					},
+				},
+			},
+		}},
+	}
+
+	for _, tt := range testCases {
+		result, err := validator.Validate(mustDecode(t, tt.instance))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.errors, result.Errors, tt.instance)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package jsonschema
+
+// raceEnabled indicates whether the race detector is enabled. The race detector
+// makes sync.Pool drop items at random, so allocations cannot be measured.
+const raceEnabled = true
@@ -0,0 +1,473 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reflect produces a draft-07 schema describing the JSON encoding of values of
+// type t by encoding/json. The result can be given to NewValidator, or encoded
+// as JSON.
+//
+// Struct fields are named and skipped according to their "json" tags, and the
+// fields of embedded structs are promoted as encoding/json does. Fields are
+// required unless tagged "omitempty", or promoted from an embedded pointer,
+// which encoding/json skips when nil. Values of pointer types are described by
+// the schema of the type they point to. As encoding/json encodes nil pointers,
+// slices, and maps as null, their schemas also accept null, except for fields
+// tagged "omitempty", which are left out instead. time.Time becomes a string
+// with format "date-time", byte slices become strings, and types implementing
+// json.Marshaler or encoding.TextMarshaler are described by the empty schema or
+// a string, as their encoding is unknown.
+//
+// A "jsonschema" tag adds constraints to a field, as a comma-separated list of
+// keywords and values:
+//
+//	Name string   `json:"name" jsonschema:"minLength=1,maxLength=64,pattern=^[a-z]+$"`
+//	Tags []string `json:"tags,omitempty" jsonschema:"uniqueItems,maxItems=8"`
+//	Kind string   `json:"kind" jsonschema:"enum=a|b|c"`
+//
+// The keywords supported are title, description, format, pattern, enum, the
+// numeric bounds, and the array and object size bounds. Values cannot contain
+// commas. The tag may also contain "required" or "optional" to override
+// omitempty.
+//
+// Named struct types are placed in "definitions", keyed by their name, and
+// referred to with "$ref", which allows recursive types. If t is a struct, it
+// is described by the root of the schema, and its own references are "#".
+func Reflect(t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	r := reflector{
+		root:        t,
+		names:       map[reflect.Type]string{},
+		used:        map[string]bool{},
+		definitions: map[string]interface{}{},
+	}
+
+	schema, err := r.reflectType(t, true)
+	if err != nil {
+		return nil, err
+	}
+
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	if len(r.definitions) > 0 {
+		schema["definitions"] = r.definitions
+	}
+
+	return schema, nil
+}
+
+// reflector holds the state of Reflect.
+type reflector struct {
+	root reflect.Type
+
+	// names holds the name of the definition of each named struct type
+	names map[reflect.Type]string
+
+	// used holds every name taken by a definition
+	used map[string]bool
+
+	definitions map[string]interface{}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	numberType        = reflect.TypeOf(json.Number(""))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// reflectType returns the schema of t. If root is true, struct types are
+// described in place rather than referred to.
+func (r *reflector) reflectType(t reflect.Type, root bool) (map[string]interface{}, error) {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == rawMessageType:
+		return map[string]interface{}{}, nil
+	case t == numberType:
+		return map[string]interface{}{"type": "number"}, nil
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return map[string]interface{}{}, nil
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0.0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Ptr:
+		return r.reflectType(t.Elem(), false)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+
+		items, err := r.reflectElem(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		schema := map[string]interface{}{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			schema["minItems"] = float64(t.Len())
+			schema["maxItems"] = float64(t.Len())
+		}
+
+		return schema, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !reflect.PtrTo(t.Key()).Implements(textMarshalerType) {
+				return nil, fmt.Errorf("jsonschema: cannot reflect map key type %s", t.Key())
+			}
+		}
+
+		values, err := r.reflectElem(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.reflectStruct(t)
+		}
+
+		if root {
+			return r.reflectStruct(t)
+		}
+
+		if t == r.root {
+			return map[string]interface{}{"$ref": "#"}, nil
+		}
+
+		if name, ok := r.names[t]; ok {
+			return map[string]interface{}{"$ref": "#/definitions/" + name}, nil
+		}
+
+		name := t.Name()
+		for i := 2; r.used[name]; i++ {
+			name = t.Name() + "-" + strconv.Itoa(i)
+		}
+
+		// the name is taken before the definition is reflected, so that it may
+		// refer to itself
+		r.names[t] = name
+		r.used[name] = true
+
+		schema, err := r.reflectStruct(t)
+		if err != nil {
+			return nil, err
+		}
+
+		r.definitions[name] = schema
+		return map[string]interface{}{"$ref": "#/definitions/" + name}, nil
+	}
+
+	return nil, fmt.Errorf("jsonschema: cannot reflect type %s", t)
+}
+
+// reflectElem returns the schema of the elements of an array, slice, or map.
+func (r *reflector) reflectElem(t reflect.Type) (map[string]interface{}, error) {
+	schema, err := r.reflectType(t, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNilable(t) {
+		schema = nullable(schema)
+	}
+
+	return schema, nil
+}
+
+// isNilable determines whether values of type t may be encoded as null by
+// encoding/json.
+func isNilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+// nullable returns a schema which accepts null as well as what schema accepts.
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if len(schema) == 0 {
+		return schema
+	}
+
+	if t, ok := schema["type"].(string); ok && schema["enum"] == nil {
+		schema["type"] = []interface{}{t, "null"}
+		return schema
+	}
+
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+// reflectField is a field of a struct, as encoding/json sees it.
+type reflectField struct {
+	name     string
+	tagged   bool
+	depth    int
+	field    reflect.StructField
+	optional bool
+	quoted   bool
+
+	// omitEmpty indicates whether the field is tagged "omitempty"
+	omitEmpty bool
+}
+
+// structFields returns the fields encoding/json encodes for a struct type,
+// including those promoted from embedded structs, in order.
+func structFields(t reflect.Type, depth int, visited map[reflect.Type]bool) []reflectField {
+	if visited[t] {
+		return nil
+	}
+
+	visited[t] = true
+	defer delete(visited, t)
+
+	fields := []reflectField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			promoted := structFields(fieldType, depth+1, visited)
+
+			// the fields of a nil embedded pointer are left out
+			if field.Type.Kind() == reflect.Ptr {
+				for i := range promoted {
+					promoted[i].optional = true
+				}
+			}
+
+			fields = append(fields, promoted...)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		f := reflectField{name: name, tagged: name != "", depth: depth, field: field}
+		if name == "" {
+			f.name = field.Name
+		}
+
+		for _, option := range parts[1:] {
+			switch option {
+			case "omitempty":
+				f.optional = true
+				f.omitEmpty = true
+			case "string":
+				f.quoted = true
+			}
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// dominantFields resolves fields with the same name as encoding/json does: the
+// shallowest field wins, then the only tagged one; otherwise all are dropped.
+func dominantFields(fields []reflectField) []reflectField {
+	byName := map[string][]reflectField{}
+	for _, f := range fields {
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	result := []reflectField{}
+	for _, f := range fields {
+		candidates := byName[f.name]
+		if candidates == nil {
+			// already resolved
+			continue
+		}
+
+		delete(byName, f.name)
+
+		minDepth := candidates[0].depth
+		for _, c := range candidates {
+			if c.depth < minDepth {
+				minDepth = c.depth
+			}
+		}
+
+		shallowest := []reflectField{}
+		tagged := []reflectField{}
+		for _, c := range candidates {
+			if c.depth == minDepth {
+				shallowest = append(shallowest, c)
+				if c.tagged {
+					tagged = append(tagged, c)
+				}
+			}
+		}
+
+		switch {
+		case len(shallowest) == 1:
+			result = append(result, shallowest[0])
+		case len(tagged) == 1:
+			result = append(result, tagged[0])
+		}
+	}
+
+	return result
+}
+
+func (r *reflector) reflectStruct(t reflect.Type) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+
+	for _, f := range dominantFields(structFields(t, 0, map[reflect.Type]bool{})) {
+		schema, err := r.reflectType(f.field.Type, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.quoted {
+			switch schema["type"] {
+			case "boolean", "integer", "number", "string":
+				schema = map[string]interface{}{"type": "string"}
+			}
+		}
+
+		optional := f.optional
+		if tag, ok := f.field.Tag.Lookup("jsonschema"); ok {
+			if schema, optional, err = applyReflectTag(schema, optional, tag); err != nil {
+				return nil, fmt.Errorf("jsonschema: field %s of %s: %v", f.field.Name, t, err)
+			}
+		}
+
+		if !f.omitEmpty && isNilable(f.field.Type) {
+			schema = nullable(schema)
+		}
+
+		properties[f.name] = schema
+		if !optional {
+			required = append(required, f.name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+// applyReflectTag adds the constraints of a "jsonschema" tag to a schema.
+func applyReflectTag(schema map[string]interface{}, optional bool, tag string) (map[string]interface{}, bool, error) {
+	if ref, ok := schema["$ref"]; ok && tag != "" {
+		// "$ref" overrides its siblings in some implementations, so
+		// constraints are placed beside it in "allOf" instead
+		schema = map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": ref}}}
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		if option == "" {
+			continue
+		}
+
+		parts := strings.SplitN(option, "=", 2)
+		keyword := parts[0]
+		value := ""
+		if len(parts) == 2 {
+			value = parts[1]
+		}
+
+		switch keyword {
+		case "required":
+			optional = false
+		case "optional":
+			optional = true
+		case "title", "description", "format", "pattern":
+			schema[keyword] = value
+		case "uniqueItems":
+			schema[keyword] = true
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf":
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s is not a number", keyword)
+			}
+
+			schema[keyword] = number
+		case "minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties":
+			number, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s is not a non-negative integer", keyword)
+			}
+
+			schema[keyword] = float64(number)
+		case "enum":
+			values := []interface{}{}
+			for _, str := range strings.Split(value, "|") {
+				switch schema["type"] {
+				case "integer", "number":
+					number, err := strconv.ParseFloat(str, 64)
+					if err != nil {
+						return nil, false, fmt.Errorf("enum value %q is not a number", str)
+					}
+
+					values = append(values, number)
+				case "boolean":
+					b, err := strconv.ParseBool(str)
+					if err != nil {
+						return nil, false, fmt.Errorf("enum value %q is not a boolean", str)
+					}
+
+					values = append(values, b)
+				default:
+					values = append(values, str)
+				}
+			}
+
+			schema[keyword] = values
+		default:
+			return nil, false, fmt.Errorf("unknown keyword %q", keyword)
+		}
+	}
+
+	return schema, optional, nil
+}
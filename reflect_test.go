@@ -0,0 +1,223 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reflectBase struct {
+	ID      int64     `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type reflectNode struct {
+	reflectBase
+	Name     string            `json:"name" jsonschema:"minLength=3,pattern=^[a-z]+$"`
+	Kind     string            `json:"kind,omitempty" jsonschema:"enum=leaf|branch"`
+	Weight   float64           `json:"weight,string"`
+	Tags     []string          `json:"tags,omitempty" jsonschema:"uniqueItems,maxItems=2"`
+	Children []*reflectNode    `json:"children,omitempty"`
+	Labels   map[string]uint8  `json:"labels,omitempty"`
+	Parent   *reflectNode      `json:"parent,omitempty" jsonschema:"description=the parent node"`
+	Data     []byte            `json:"data,omitempty"`
+	Extra    interface{}       `json:"extra" jsonschema:"optional"`
+	Skipped  string            `json:"-"`
+	Point    reflectPoint      `json:"point"`
+	Pair     [2]reflectPoint   `json:"pair,omitempty"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	private  string            // unexported
+	Default  string            // untagged
+	Meta     struct{ A bool }  `json:"meta,omitempty"`
+	Nested   map[string][]bool `json:"nested,omitempty"`
+}
+
+type reflectPoint struct {
+	X, Y int
+}
+
+func TestReflect(t *testing.T) {
+	schema, err := Reflect(reflect.TypeOf(&reflectNode{}))
+	assert.NoError(t, err)
+
+	assert.Equal(t, mustDecode(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"created": {"type": "string", "format": "date-time"},
+			"name": {"type": "string", "minLength": 3, "pattern": "^[a-z]+$"},
+			"kind": {"type": "string", "enum": ["leaf", "branch"]},
+			"weight": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 2},
+			"children": {"type": "array", "items": {"anyOf": [{"$ref": "#"}, {"type": "null"}]}},
+			"labels": {"type": "object", "additionalProperties": {"type": "integer", "minimum": 0}},
+			"parent": {"allOf": [{"$ref": "#"}], "description": "the parent node"},
+			"data": {"type": "string", "contentEncoding": "base64"},
+			"extra": {},
+			"point": {"$ref": "#/definitions/reflectPoint"},
+			"pair": {"type": "array", "items": {"$ref": "#/definitions/reflectPoint"}, "minItems": 2, "maxItems": 2},
+			"raw": {},
+			"Default": {"type": "string"},
+			"meta": {"type": "object", "properties": {"A": {"type": "boolean"}}, "required": ["A"]},
+			"nested": {"type": "object", "additionalProperties": {"type": ["array", "null"], "items": {"type": "boolean"}}}
+		},
+		"required": ["id", "created", "name", "weight", "point", "Default"],
+		"definitions": {
+			"reflectPoint": {
+				"type": "object",
+				"properties": {"X": {"type": "integer"}, "Y": {"type": "integer"}},
+				"required": ["X", "Y"]
+			}
+		}
+	}`), roundTripJSON(t, schema))
+
+	// the schema accepts what encoding/json produces
+	validator, err := NewValidator([]interface{}{roundTripJSON(t, schema)})
+	assert.NoError(t, err)
+
+	node := reflectNode{
+		Name:     "root",
+		Kind:     "branch",
+		Children: []*reflectNode{{Name: "leaf", Kind: "leaf"}},
+		Labels:   map[string]uint8{"a": 1},
+		Data:     []byte("data"),
+	}
+	node.Children[0].Parent = &reflectNode{Name: "abc"}
+
+	result, err := validator.Validate(roundTripJSON(t, node))
+	assert.NoError(t, err)
+	assert.True(t, result.IsValid(), "%v", result.Errors)
+
+	node.Name = "x"
+	result, err = validator.Validate(roundTripJSON(t, node))
+	assert.NoError(t, err)
+	assert.False(t, result.IsValid())
+}
+
+func TestReflectZeroValues(t *testing.T) {
+	type zero struct {
+		*reflectBase
+		Map      map[string]int  `json:"map"`
+		Slice    []string        `json:"slice" jsonschema:"maxItems=1"`
+		Pointer  *reflectPoint   `json:"pointer"`
+		Enum     *string         `json:"enum" jsonschema:"enum=a|b"`
+		Elems    []*reflectPoint `json:"elems"`
+		Omitted  []string        `json:"omitted,omitempty"`
+		Internal *int            `json:"internal"`
+	}
+
+	schema, err := Reflect(reflect.TypeOf(zero{}))
+	assert.NoError(t, err)
+	assert.Equal(t, mustDecode(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"created": {"type": "string", "format": "date-time"},
+			"map": {"type": ["object", "null"], "additionalProperties": {"type": "integer"}},
+			"slice": {"type": ["array", "null"], "items": {"type": "string"}, "maxItems": 1},
+			"pointer": {"anyOf": [{"$ref": "#/definitions/reflectPoint"}, {"type": "null"}]},
+			"enum": {"anyOf": [{"type": "string", "enum": ["a", "b"]}, {"type": "null"}]},
+			"elems": {"type": ["array", "null"], "items": {"anyOf": [{"$ref": "#/definitions/reflectPoint"}, {"type": "null"}]}},
+			"omitted": {"type": "array", "items": {"type": "string"}},
+			"internal": {"type": ["integer", "null"]}
+		},
+		"required": ["map", "slice", "pointer", "enum", "elems", "internal"],
+		"definitions": {
+			"reflectPoint": {
+				"type": "object",
+				"properties": {"X": {"type": "integer"}, "Y": {"type": "integer"}},
+				"required": ["X", "Y"]
+			}
+		}
+	}`), roundTripJSON(t, schema))
+
+	// the schema accepts what encoding/json produces for zero values
+	validator, err := NewValidator([]interface{}{roundTripJSON(t, schema)})
+	assert.NoError(t, err)
+
+	a, one := "a", 1
+	for _, value := range []zero{
+		{},
+		{Elems: []*reflectPoint{nil, {X: 1}}},
+		{
+			reflectBase: &reflectBase{ID: 1},
+			Map:         map[string]int{},
+			Slice:       []string{},
+			Pointer:     &reflectPoint{},
+			Enum:        &a,
+			Elems:       []*reflectPoint{},
+			Internal:    &one,
+		},
+	} {
+		result, err := validator.Validate(roundTripJSON(t, value))
+		assert.NoError(t, err)
+		assert.True(t, result.IsValid(), "%v", result.Errors)
+	}
+}
+
+func TestReflectDominantFields(t *testing.T) {
+	type inner struct {
+		A string `json:"a"`
+		B string
+	}
+
+	type other struct {
+		B string
+	}
+
+	type outer struct {
+		inner
+		*other
+		A int `json:"a"`
+	}
+
+	schema, err := Reflect(reflect.TypeOf(outer{}))
+	assert.NoError(t, err)
+	assert.Equal(t, mustDecode(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {"a": {"type": "integer"}},
+		"required": ["a"]
+	}`), roundTripJSON(t, schema))
+}
+
+func TestReflectErrors(t *testing.T) {
+	type badTag struct {
+		A string `jsonschema:"minLength=x"`
+	}
+
+	type unknownTag struct {
+		A string `jsonschema:"color=red"`
+	}
+
+	testCases := []struct {
+		value interface{}
+		err   string
+	}{
+		{make(chan int), "jsonschema: cannot reflect type chan int"},
+		{map[[2]int]bool{}, "jsonschema: cannot reflect map key type [2]int"},
+		{badTag{}, "jsonschema: field A of jsonschema.badTag: minLength is not a non-negative integer"},
+		{unknownTag{}, `jsonschema: field A of jsonschema.unknownTag: unknown keyword "color"`},
+	}
+
+	for _, tt := range testCases {
+		_, err := Reflect(reflect.TypeOf(tt.value))
+		assert.EqualError(t, err, tt.err)
+	}
+}
+
+// roundTripJSON encodes and decodes a value, so that it can be compared with
+// or validated against decoded JSON.
+func roundTripJSON(t *testing.T, value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	assert.NoError(t, err)
+
+	var decoded interface{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
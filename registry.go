@@ -13,12 +13,16 @@ func newRegistry(cap int) registry {
 	return registry{schemas: map[url.URL]int{}, arena: newArena(cap)}
 }
 
-func (r *registry) Get(uri url.URL) (schema, bool) {
+func (r *registry) Get(uri url.URL) (*schema, bool) {
 	index, ok := r.schemas[uri]
-	return r.arena.Get(index), ok
+	if !ok {
+		return nil, false
+	}
+
+	return r.arena.Get(index), true
 }
 
-func (r *registry) GetIndex(index int) schema {
+func (r *registry) GetIndex(index int) *schema {
 	return r.arena.Get(index)
 }
 
@@ -32,6 +36,16 @@ func (r *registry) Insert(uri url.URL, s schema) int {
 	return index
 }
 
+// Compile compiles every schema in the registry into a program. It must be
+// called once all schemas have been inserted.
+func (r *registry) Compile() {
+	for index := range r.arena.schemas {
+		s := &r.arena.schemas[index]
+		s.Program = compileProgram(s)
+		s.Program.oneOf = compileOneOfDispatch(r, s)
+	}
+}
+
 func (r *registry) PopulateRefs() []url.URL {
 	missing := []url.URL{}
 
@@ -0,0 +1,1201 @@
+package jsonschema
+
+import (
+	"math"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// sampleAttempts is the number of instances a Sampler generates in search of a
+// valid one before giving up.
+const sampleAttempts = 100
+
+// sampleMaxDepth is the depth of nesting beyond which a Sampler generates only
+// the properties and items a schema requires.
+const sampleMaxDepth = 4
+
+// Sampler generates instances of the schemas of a Validator, for use as
+// example data in tests.
+//
+// Generation is random, but reproducible: two Samplers created with the same
+// Validator and seed generate the same instances, if called in the same
+// order. A Sampler is not safe for concurrent use.
+type Sampler struct {
+	v    *Validator
+	rand *rand.Rand
+	uris map[int]url.URL
+
+	// complete indicates whether to include every optional property of
+	// objects, so that Invalid can reach their keywords
+	complete bool
+}
+
+// InvalidSample is an instance which violates exactly one keyword of a schema.
+type InvalidSample struct {
+	// Instance is the invalid instance.
+	Instance interface{}
+
+	// Keyword is the keyword the instance violates.
+	Keyword KeywordLocation
+}
+
+// NewSampler constructs a Sampler for the schemas of v, seeded with seed.
+func NewSampler(v *Validator, seed int64) *Sampler {
+	return &Sampler{
+		v:    v,
+		rand: rand.New(rand.NewSource(seed)),
+		uris: schemaURIs(v.registry),
+	}
+}
+
+// Valid generates an instance which the schema identified by uri accepts. The
+// empty URI identifies the default schema.
+//
+// Instances are generated to satisfy the types, bounds, patterns, formats,
+// required properties and enums of the schema, following "$ref" and "allOf",
+// and choosing among the alternatives of "anyOf", "oneOf" and "if". Each is
+// checked with ValidateURI, and generation is retried until one is valid. If
+// none is found, ErrNoSample is returned; this happens for schemas which
+// accept nothing, and for schemas whose keywords interact in ways the Sampler
+// does not understand, such as through "not".
+func (s *Sampler) Valid(uri url.URL) (interface{}, error) {
+	index, ok := s.v.registry.schemas[uri]
+	if !ok {
+		return nil, ErrNoSuchSchema
+	}
+
+	for i := 0; i < sampleAttempts; i++ {
+		instance, ok := s.generate([]int{index}, 0)
+		if !ok {
+			continue
+		}
+
+		valid, err := s.v.IsValidURI(uri, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		if valid {
+			return instance, nil
+		}
+	}
+
+	return nil, ErrNoSample
+}
+
+// Invalid generates instances which the schema identified by uri rejects, each
+// violating exactly one of its keywords.
+//
+// Each instance is a valid instance, as generated by Valid, with one part
+// changed to violate a keyword of the schema which applies to that part, such
+// as by removing a required property, or by exceeding a maximum. The changed
+// instance is checked against the schema, and kept only if the keyword is the
+// sole source of errors. The check reports every error, whatever the
+// error-limiting options of the Validator's ValidatorConfig. At most one instance is returned per keyword, in a
+// deterministic order.
+func (s *Sampler) Invalid(uri url.URL) ([]InvalidSample, error) {
+	index, ok := s.v.registry.schemas[uri]
+	if !ok {
+		return nil, ErrNoSuchSchema
+	}
+
+	s.complete = true
+	valid, err := s.Valid(uri)
+	s.complete = false
+	if err != nil {
+		return nil, err
+	}
+
+	inv := invalidator{
+		s:       s,
+		uri:     uri,
+		root:    valid,
+		vm:      newVM(s.v.registry, s.v.maxStackDepth, 0, 0, 0),
+		samples: []InvalidSample{},
+		seen:    map[string]bool{},
+	}
+
+	if err := inv.walk(s.expandStatic(index, nil), nil); err != nil {
+		return nil, err
+	}
+
+	return inv.samples, nil
+}
+
+// expand returns the schemas an instance must satisfy to be accepted by the
+// schema at index, following references and "allOf", and choosing an
+// alternative of "anyOf", "oneOf" and "if". It returns false if the schemas
+// reject every instance.
+func (s *Sampler) expand(index int, out []int, depth int) ([]int, bool) {
+	if depth > s.v.maxStackDepth {
+		return out, false
+	}
+
+	out = append(out, index)
+	sch := s.v.registry.GetIndex(index)
+
+	if sch.Bool.IsSet && !sch.Bool.Value {
+		return out, false
+	}
+
+	ok := true
+	if sch.Ref.IsSet {
+		out, ok = s.expand(sch.Ref.Schema, out, depth+1)
+	}
+
+	for _, member := range sch.AllOf.Schemas {
+		if ok {
+			out, ok = s.expand(member, out, depth+1)
+		}
+	}
+
+	for _, alternatives := range [][]int{sch.AnyOf.Schemas, sch.OneOf.Schemas} {
+		if ok && len(alternatives) > 0 {
+			out, ok = s.expand(alternatives[s.rand.Intn(len(alternatives))], out, depth+1)
+		}
+	}
+
+	if ok && sch.If.IsSet {
+		if s.rand.Intn(2) == 0 {
+			out, ok = s.expand(sch.If.Schema, out, depth+1)
+			if ok && sch.Then.IsSet {
+				out, ok = s.expand(sch.Then.Schema, out, depth+1)
+			}
+		} else if sch.Else.IsSet {
+			out, ok = s.expand(sch.Else.Schema, out, depth+1)
+		}
+	}
+
+	return out, ok
+}
+
+// expandStatic returns the schemas which always apply along with the schema
+// at index: those it refers to, and the members of its "allOf".
+func (s *Sampler) expandStatic(index int, out []int) []int {
+	for _, i := range out {
+		if i == index {
+			return out
+		}
+	}
+
+	out = append(out, index)
+	sch := s.v.registry.GetIndex(index)
+	if sch.Ref.IsSet {
+		out = s.expandStatic(sch.Ref.Schema, out)
+	}
+
+	for _, member := range sch.AllOf.Schemas {
+		out = s.expandStatic(member, out)
+	}
+
+	return out
+}
+
+// generate returns an instance which may satisfy all of the given schemas, or
+// false if one clearly cannot be produced.
+func (s *Sampler) generate(indexes []int, depth int) (interface{}, bool) {
+	var schemas []*schema
+	var expanded []int
+	for _, index := range indexes {
+		var ok bool
+		if expanded, ok = s.expand(index, expanded, 0); !ok {
+			return nil, false
+		}
+	}
+
+	for _, index := range expanded {
+		schemas = append(schemas, s.v.registry.GetIndex(index))
+	}
+
+	for _, sch := range schemas {
+		if sch.Const.IsSet {
+			return copyRawValue(sch.Const.Value), true
+		}
+	}
+
+	for _, sch := range schemas {
+		if sch.Enum.IsSet {
+			candidates := []interface{}{}
+			for _, value := range sch.Enum.Values {
+				if s.acceptsAll(expanded, value) {
+					candidates = append(candidates, value)
+				}
+			}
+
+			if len(candidates) == 0 {
+				return nil, false
+			}
+
+			return copyRawValue(candidates[s.rand.Intn(len(candidates))]), true
+		}
+	}
+
+	types := sampleTypes(schemas)
+	if len(types) == 0 {
+		return nil, false
+	}
+
+	switch types[s.rand.Intn(len(types))] {
+	case jsonTypeNull:
+		return nil, true
+	case jsonTypeBoolean:
+		return s.rand.Intn(2) == 0, true
+	case jsonTypeInteger:
+		return s.generateNumber(schemas, true)
+	case jsonTypeNumber:
+		return s.generateNumber(schemas, false)
+	case jsonTypeString:
+		return s.generateString(expanded, schemas)
+	case jsonTypeArray:
+		return s.generateArray(schemas, depth)
+	}
+
+	return s.generateObject(schemas, depth)
+}
+
+// acceptsAll determines whether every schema given accepts an instance.
+func (s *Sampler) acceptsAll(indexes []int, instance interface{}) bool {
+	for _, index := range indexes {
+		if valid, err := s.v.IsValidURI(s.uris[index], instance); err != nil || !valid {
+			return false
+		}
+	}
+
+	return true
+}
+
+var allJSONTypes = []jsonType{
+	jsonTypeNull, jsonTypeBoolean, jsonTypeInteger, jsonTypeNumber,
+	jsonTypeString, jsonTypeArray, jsonTypeObject,
+}
+
+// sampleTypes returns the types an instance of every schema may take. Where no
+// schema restricts types, types are guessed from the keywords present.
+func sampleTypes(schemas []*schema) []jsonType {
+	types := []jsonType{}
+	restricted := false
+	for _, t := range allJSONTypes {
+		ok := true
+		for _, sch := range schemas {
+			if sch.Type.IsSet {
+				restricted = true
+				ok = ok && (sch.Type.contains(t) || (t == jsonTypeInteger && sch.Type.contains(jsonTypeNumber)))
+			}
+		}
+
+		if ok {
+			types = append(types, t)
+		}
+	}
+
+	// null is chosen only when it is the only choice
+	if len(types) > 1 && types[0] == jsonTypeNull {
+		types = types[1:]
+	}
+
+	if restricted {
+		return types
+	}
+
+	for _, sch := range schemas {
+		switch {
+		case sch.Properties.IsSet || sch.Required.IsSet || sch.AdditionalProperties.IsSet ||
+			sch.PatternProperties.IsSet || sch.MinProperties.IsSet || sch.Dependencies.IsSet:
+			return []jsonType{jsonTypeObject}
+		case sch.Items.IsSet || sch.MinItems.IsSet || sch.Contains.IsSet || sch.UniqueItems.IsSet:
+			return []jsonType{jsonTypeArray}
+		case sch.MinLength.IsSet || sch.MaxLength.IsSet || sch.Pattern.IsSet:
+			return []jsonType{jsonTypeString}
+		case sch.Minimum.IsSet || sch.Maximum.IsSet || sch.ExclusiveMinimum.IsSet ||
+			sch.ExclusiveMaximum.IsSet || sch.MultipleOf.IsSet:
+			return []jsonType{jsonTypeNumber}
+		}
+	}
+
+	return []jsonType{jsonTypeBoolean, jsonTypeInteger, jsonTypeString}
+}
+
+func (s *Sampler) generateNumber(schemas []*schema, integer bool) (interface{}, bool) {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	loExclusive, hiExclusive := false, false
+	multipleOf := 0.0
+
+	for _, sch := range schemas {
+		if sch.Minimum.IsSet && sch.Minimum.Value >= lo {
+			lo, loExclusive = sch.Minimum.Value, false
+		}
+
+		if sch.ExclusiveMinimum.IsSet && sch.ExclusiveMinimum.Value >= lo {
+			lo, loExclusive = sch.ExclusiveMinimum.Value, true
+		}
+
+		if sch.Maximum.IsSet && sch.Maximum.Value <= hi {
+			hi, hiExclusive = sch.Maximum.Value, false
+		}
+
+		if sch.ExclusiveMaximum.IsSet && sch.ExclusiveMaximum.Value <= hi {
+			hi, hiExclusive = sch.ExclusiveMaximum.Value, true
+		}
+
+		if sch.MultipleOf.IsSet && multipleOf == 0 {
+			multipleOf = sch.MultipleOf.Value
+		}
+	}
+
+	switch {
+	case math.IsInf(lo, -1) && math.IsInf(hi, 1):
+		lo, hi = 0, 100
+	case math.IsInf(lo, -1):
+		lo = hi - 100
+	case math.IsInf(hi, 1):
+		hi = lo + 100
+	}
+
+	step := multipleOf
+	if integer && step == 0 {
+		step = 1
+	}
+
+	if step != 0 {
+		first, last := math.Ceil(lo/step), math.Floor(hi/step)
+		if loExclusive && first*step == lo {
+			first++
+		}
+
+		if hiExclusive && last*step == hi {
+			last--
+		}
+
+		if first > last {
+			return nil, false
+		}
+
+		n := first + math.Floor(s.rand.Float64()*(last-first+1))
+		return n * step, true
+	}
+
+	if lo > hi || (lo == hi && (loExclusive || hiExclusive)) {
+		return nil, false
+	}
+
+	value := lo + s.rand.Float64()*(hi-lo)
+	if (loExclusive && value == lo) || (hiExclusive && value == hi) {
+		value = lo + (hi-lo)/2
+	}
+
+	return value, true
+}
+
+// sampleFormats holds an example of each format a Sampler knows.
+var sampleFormats = map[string]string{
+	"date-time":             "2006-01-02T15:04:05Z",
+	"date":                  "2006-01-02",
+	"time":                  "15:04:05Z",
+	"email":                 "user@example.com",
+	"idn-email":             "user@example.com",
+	"hostname":              "example.com",
+	"idn-hostname":          "example.com",
+	"ipv4":                  "192.0.2.1",
+	"ipv6":                  "2001:db8::1",
+	"uri":                   "http://example.com/",
+	"uri-reference":         "/example",
+	"iri":                   "http://example.com/",
+	"iri-reference":         "/example",
+	"uri-template":          "http://example.com/{id}",
+	"json-pointer":          "/example",
+	"relative-json-pointer": "0/example",
+	"regex":                 "^example$",
+	"uuid":                  "123e4567-e89b-12d3-a456-426614174000",
+}
+
+func (s *Sampler) generateString(indexes []int, schemas []*schema) (interface{}, bool) {
+	minLength, maxLength := 0, -1
+	var pattern *regexp.Regexp
+	for _, sch := range schemas {
+		if sch.MinLength.IsSet && sch.MinLength.Value > minLength {
+			minLength = sch.MinLength.Value
+		}
+
+		if sch.MaxLength.IsSet && (maxLength == -1 || sch.MaxLength.Value < maxLength) {
+			maxLength = sch.MaxLength.Value
+		}
+
+		if sch.Pattern.IsSet && pattern == nil {
+			pattern = sch.Pattern.Value
+		}
+	}
+
+	if maxLength != -1 && minLength > maxLength {
+		return nil, false
+	}
+
+	for _, index := range indexes {
+		if format, ok := s.v.rawSchema(s.uris[index])["format"].(string); ok {
+			if str, ok := sampleFormats[format]; ok {
+				return str, true
+			}
+		}
+	}
+
+	if pattern != nil {
+		re, err := syntax.Parse(pattern.String(), syntax.Perl)
+		if err != nil {
+			return nil, false
+		}
+
+		var b strings.Builder
+		s.generateRegexp(&b, re.Simplify())
+
+		// patterns are unanchored, so padding keeps a match
+		str := b.String()
+		if n := minLength - len([]rune(str)); n > 0 && !strings.HasSuffix(pattern.String(), "$") {
+			str += s.randomString(n)
+		}
+
+		return str, true
+	}
+
+	n := minLength
+	if maxLength == -1 || maxLength > minLength+8 {
+		n += s.rand.Intn(9)
+	} else {
+		n += s.rand.Intn(maxLength - minLength + 1)
+	}
+
+	return s.randomString(n), true
+}
+
+func (s *Sampler) randomString(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[s.rand.Intn(len(letters))]
+	}
+
+	return string(b)
+}
+
+// generateRegexp writes a random string matching a regular expression.
+func (s *Sampler) generateRegexp(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		// Rune holds pairs of inclusive bounds
+		if len(re.Rune) == 0 {
+			return
+		}
+
+		i := s.rand.Intn(len(re.Rune)/2) * 2
+		lo, hi := re.Rune[i], re.Rune[i+1]
+		if hi-lo > 25 && lo <= 'a' && 'z' <= hi {
+			// prefer readable characters from wide classes
+			lo, hi = 'a', 'z'
+		}
+
+		b.WriteRune(lo + rune(s.rand.Intn(int(hi-lo)+1)))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(byte('a' + s.rand.Intn(26)))
+	case syntax.OpCapture:
+		s.generateRegexp(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			s.generateRegexp(b, sub)
+		}
+	case syntax.OpAlternate:
+		s.generateRegexp(b, re.Sub[s.rand.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+
+		if max == -1 || max > min+3 {
+			max = min + 3
+		}
+
+		for n := min + s.rand.Intn(max-min+1); n > 0; n-- {
+			s.generateRegexp(b, re.Sub[0])
+		}
+	}
+}
+
+func (s *Sampler) generateArray(schemas []*schema, depth int) (interface{}, bool) {
+	minItems, maxItems := 0, -1
+	unique := false
+	for _, sch := range schemas {
+		if sch.MinItems.IsSet && sch.MinItems.Value > minItems {
+			minItems = sch.MinItems.Value
+		}
+
+		if sch.MaxItems.IsSet && (maxItems == -1 || sch.MaxItems.Value < maxItems) {
+			maxItems = sch.MaxItems.Value
+		}
+
+		if sch.UniqueItems.IsSet && sch.UniqueItems.Value {
+			unique = true
+		}
+
+		// additional items beyond a tuple may be forbidden
+		if sch.Items.IsSet && !sch.Items.IsSingle && sch.AdditionalItems.IsSet {
+			if additional := s.v.registry.GetIndex(sch.AdditionalItems.Schema); additional.Bool.IsSet && !additional.Bool.Value {
+				if maxItems == -1 || len(sch.Items.Schemas) < maxItems {
+					maxItems = len(sch.Items.Schemas)
+				}
+			}
+		}
+	}
+
+	if maxItems != -1 && minItems > maxItems {
+		return nil, false
+	}
+
+	n := minItems
+	if depth < sampleMaxDepth {
+		extra := 3
+		if maxItems != -1 && maxItems-minItems < extra {
+			extra = maxItems - minItems
+		}
+
+		n += s.rand.Intn(extra + 1)
+	}
+
+	containsAt := -1
+	for _, sch := range schemas {
+		if sch.Contains.IsSet {
+			if n == 0 {
+				if maxItems == 0 {
+					return nil, false
+				}
+
+				n = 1
+			}
+
+			containsAt = s.rand.Intn(n)
+		}
+	}
+
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		indexes := []int{}
+		for _, sch := range schemas {
+			switch {
+			case sch.Items.IsSet && sch.Items.IsSingle:
+				indexes = append(indexes, sch.Items.Schemas[0])
+			case sch.Items.IsSet && i < len(sch.Items.Schemas):
+				indexes = append(indexes, sch.Items.Schemas[i])
+			case sch.Items.IsSet && sch.AdditionalItems.IsSet:
+				indexes = append(indexes, sch.AdditionalItems.Schema)
+			}
+
+			if i == containsAt && sch.Contains.IsSet {
+				indexes = append(indexes, sch.Contains.Schema)
+			}
+		}
+
+		item, ok := s.generate(indexes, depth+1)
+		for attempt := 0; ok && unique && attempt < sampleAttempts && containsJSON(items, item); attempt++ {
+			item, ok = s.generate(indexes, depth+1)
+		}
+
+		if !ok {
+			return nil, false
+		}
+
+		items = append(items, item)
+	}
+
+	return items, true
+}
+
+func containsJSON(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if jsonEqual(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Sampler) generateObject(schemas []*schema, depth int) (interface{}, bool) {
+	required := map[string]bool{}
+	optional := map[string]bool{}
+	minProperties, maxProperties := 0, -1
+	closed := false
+
+	for _, sch := range schemas {
+		for _, key := range sch.Required.Properties {
+			required[key] = true
+		}
+
+		for key := range sch.Properties.Schemas {
+			optional[key] = true
+		}
+
+		if sch.MinProperties.IsSet && sch.MinProperties.Value > minProperties {
+			minProperties = sch.MinProperties.Value
+		}
+
+		if sch.MaxProperties.IsSet && (maxProperties == -1 || sch.MaxProperties.Value < maxProperties) {
+			maxProperties = sch.MaxProperties.Value
+		}
+
+		if sch.AdditionalProperties.IsSet {
+			additional := s.v.registry.GetIndex(sch.AdditionalProperties.Schema)
+			closed = closed || (additional.Bool.IsSet && !additional.Bool.Value)
+		}
+	}
+
+	// properties required by dependencies are added along with the properties
+	// they depend on
+	addDeps := func(keys map[string]bool) {
+		for changed := true; changed; {
+			changed = false
+			for _, sch := range schemas {
+				for _, key := range sortedDependencies(sch.Dependencies.Deps) {
+					dep := sch.Dependencies.Deps[key]
+					if !keys[key] || dep.IsSchema {
+						continue
+					}
+
+					for _, prop := range dep.Properties {
+						if !keys[prop] {
+							keys[prop] = true
+							changed = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	keys := map[string]bool{}
+	for key := range required {
+		keys[key] = true
+	}
+
+	addDeps(keys)
+
+	candidates := []string{}
+	for _, key := range sortedSet(optional) {
+		if !keys[key] {
+			candidates = append(candidates, key)
+		}
+	}
+
+	s.rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	for _, key := range candidates {
+		if maxProperties != -1 && len(keys) >= maxProperties {
+			break
+		}
+
+		if len(keys) < minProperties || (depth < sampleMaxDepth && (s.complete || s.rand.Intn(2) == 0)) {
+			keys[key] = true
+			addDeps(keys)
+		}
+	}
+
+	for i := 1; len(keys) < minProperties && i <= sampleAttempts; i++ {
+		if key, ok := s.generateKey(schemas, i, closed); ok {
+			keys[key] = true
+		}
+	}
+
+	if maxProperties != -1 && len(keys) > maxProperties {
+		return nil, false
+	}
+
+	object := map[string]interface{}{}
+	for _, key := range sortedSet(keys) {
+		indexes := []int{}
+		for _, sch := range schemas {
+			matched := false
+			if index, ok := sch.Properties.Schemas[key]; ok {
+				indexes = append(indexes, index)
+				matched = true
+			}
+
+			if patterns := matchingPatterns(sch, key); len(patterns) > 0 {
+				indexes = append(indexes, patterns...)
+				matched = true
+			}
+
+			if !matched && sch.AdditionalProperties.IsSet {
+				indexes = append(indexes, sch.AdditionalProperties.Schema)
+			}
+		}
+
+		value, ok := s.generate(indexes, depth+1)
+		if !ok {
+			return nil, false
+		}
+
+		object[key] = value
+	}
+
+	return object, true
+}
+
+// generateKey returns the name of a property to add to an object beyond those
+// the schemas declare, or false if none could be found. Names are taken from
+// "propertyNames" or "patternProperties" if present, or else are numbered.
+func (s *Sampler) generateKey(schemas []*schema, i int, closed bool) (string, bool) {
+	names := []int{}
+	patterns := []*regexp.Regexp{}
+	for _, sch := range schemas {
+		if sch.PropertyNames.IsSet {
+			names = append(names, sch.PropertyNames.Schema)
+		}
+
+		for re := range sch.PatternProperties.Schemas {
+			patterns = append(patterns, re)
+		}
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		return patterns[i].String() < patterns[j].String()
+	})
+
+	if len(names) > 0 {
+		key, ok := s.generate(names, sampleMaxDepth)
+		str, isString := key.(string)
+		return str, ok && isString
+	}
+
+	if len(patterns) > 0 {
+		re, err := syntax.Parse(patterns[s.rand.Intn(len(patterns))].String(), syntax.Perl)
+		if err != nil {
+			return "", false
+		}
+
+		var b strings.Builder
+		s.generateRegexp(&b, re.Simplify())
+		return b.String(), true
+	}
+
+	return "property" + strconv.Itoa(i), !closed
+}
+
+// matchingPatterns returns the schemas of the "patternProperties" of a schema
+// which apply to a property, in a deterministic order.
+func matchingPatterns(sch *schema, key string) []int {
+	indexes := []int{}
+	for re, index := range sch.PatternProperties.Schemas {
+		if re.MatchString(key) {
+			indexes = append(indexes, index)
+		}
+	}
+
+	sort.Ints(indexes)
+	return indexes
+}
+
+// invalidator holds the state of Sampler.Invalid.
+type invalidator struct {
+	s    *Sampler
+	uri  url.URL
+	root interface{}
+
+	// vm checks instances without any limit on errors, so that the errors of
+	// every violated keyword are seen
+	vm vm
+
+	samples []InvalidSample
+
+	// seen holds the keywords for which a sample has been found
+	seen map[string]bool
+}
+
+// walk tries to violate the keywords of the schemas at indexes, which apply to
+// the part of the instance at tokens, and then those of their subschemas
+// applying to the parts of that part.
+func (inv *invalidator) walk(indexes []int, tokens []string) error {
+	ptr := jsonpointer.Ptr{Tokens: tokens}
+	value, err := ptr.Eval(inv.root)
+	if err != nil {
+		return nil
+	}
+
+	for _, index := range indexes {
+		if err := inv.violate(index, tokens, *value); err != nil {
+			return err
+		}
+	}
+
+	switch value := (*value).(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(value) {
+			subschemas := []int{}
+			for _, index := range indexes {
+				sch := inv.s.v.registry.GetIndex(index)
+
+				matched := false
+				if i, ok := sch.Properties.Schemas[key]; ok {
+					subschemas = inv.s.expandStatic(i, subschemas)
+					matched = true
+				}
+
+				for _, i := range matchingPatterns(sch, key) {
+					subschemas = inv.s.expandStatic(i, subschemas)
+					matched = true
+				}
+
+				if !matched && sch.AdditionalProperties.IsSet {
+					subschemas = inv.s.expandStatic(sch.AdditionalProperties.Schema, subschemas)
+				}
+			}
+
+			if err := inv.walk(subschemas, append(tokens[:len(tokens):len(tokens)], key)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i := range value {
+			subschemas := []int{}
+			for _, index := range indexes {
+				sch := inv.s.v.registry.GetIndex(index)
+
+				switch {
+				case sch.Items.IsSet && sch.Items.IsSingle:
+					subschemas = inv.s.expandStatic(sch.Items.Schemas[0], subschemas)
+				case sch.Items.IsSet && i < len(sch.Items.Schemas):
+					subschemas = inv.s.expandStatic(sch.Items.Schemas[i], subschemas)
+				case sch.Items.IsSet && sch.AdditionalItems.IsSet:
+					subschemas = inv.s.expandStatic(sch.AdditionalItems.Schema, subschemas)
+				}
+			}
+
+			if err := inv.walk(subschemas, append(tokens[:len(tokens):len(tokens)], strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// violate tries to violate each keyword of the schema at index, by replacing
+// the part of the instance at tokens, which is currently value.
+func (inv *invalidator) violate(index int, tokens []string, value interface{}) error {
+	sch := inv.s.v.registry.GetIndex(index)
+
+	type attempt struct {
+		keyword    string
+		candidates []interface{}
+	}
+
+	attempts := []attempt{}
+	if sch.Type.IsSet {
+		candidates := []interface{}{}
+		for _, candidate := range []interface{}{nil, true, 0.5, 1.0, "", []interface{}{}, map[string]interface{}{}} {
+			if !sch.Type.accepts(candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		attempts = append(attempts, attempt{"type", candidates})
+	}
+
+	if sch.Const.IsSet {
+		attempts = append(attempts, attempt{"const", otherValues(sch.Const.Value)})
+	}
+
+	if sch.Enum.IsSet {
+		candidates := []interface{}{}
+		for _, value := range sch.Enum.Values {
+			for _, candidate := range otherValues(value) {
+				if !sch.Enum.Set.contains(candidate) {
+					candidates = append(candidates, candidate)
+				}
+			}
+		}
+
+		attempts = append(attempts, attempt{"enum", candidates})
+	}
+
+	if number, ok := value.(float64); ok {
+		if sch.MultipleOf.IsSet {
+			attempts = append(attempts, attempt{"multipleOf", []interface{}{number + sch.MultipleOf.Value/2, number + 1}})
+		}
+
+		if sch.Maximum.IsSet {
+			attempts = append(attempts, attempt{"maximum", []interface{}{sch.Maximum.Value + 1, sch.Maximum.Value + 0.5}})
+		}
+
+		if sch.ExclusiveMaximum.IsSet {
+			attempts = append(attempts, attempt{"exclusiveMaximum", []interface{}{sch.ExclusiveMaximum.Value}})
+		}
+
+		if sch.Minimum.IsSet {
+			attempts = append(attempts, attempt{"minimum", []interface{}{sch.Minimum.Value - 1, sch.Minimum.Value - 0.5}})
+		}
+
+		if sch.ExclusiveMinimum.IsSet {
+			attempts = append(attempts, attempt{"exclusiveMinimum", []interface{}{sch.ExclusiveMinimum.Value}})
+		}
+	}
+
+	if str, ok := value.(string); ok {
+		runes := []rune(str)
+		if sch.MaxLength.IsSet {
+			attempts = append(attempts, attempt{"maxLength", []interface{}{str + strings.Repeat("a", sch.MaxLength.Value+1-len(runes))}})
+		}
+
+		if sch.MinLength.IsSet && sch.MinLength.Value > 0 {
+			attempts = append(attempts, attempt{"minLength", []interface{}{string(runes[:sch.MinLength.Value-1])}})
+		}
+
+		if sch.Pattern.IsSet {
+			attempts = append(attempts, attempt{"pattern", []interface{}{"", "!", "0", "a", " ", str + "!", "!" + str}})
+		}
+	}
+
+	if array, ok := value.([]interface{}); ok {
+		if sch.MaxItems.IsSet && len(array) > 0 {
+			longer := append([]interface{}{}, array...)
+			for len(longer) <= sch.MaxItems.Value {
+				longer = append(longer, copyRawValue(array[len(array)-1]))
+			}
+
+			candidates := []interface{}{longer}
+
+			// duplicates may violate "uniqueItems", so new items are also tried
+			if sch.Items.IsSet && sch.Items.IsSingle {
+				for i := 0; i < 10; i++ {
+					longer := copyRawValue(array).([]interface{})
+					for len(longer) <= sch.MaxItems.Value {
+						item, ok := inv.s.generate([]int{sch.Items.Schemas[0]}, sampleMaxDepth)
+						if !ok {
+							break
+						}
+
+						longer = append(longer, item)
+					}
+
+					candidates = append(candidates, longer)
+				}
+			}
+
+			attempts = append(attempts, attempt{"maxItems", candidates})
+		}
+
+		if sch.MinItems.IsSet && sch.MinItems.Value > 0 {
+			attempts = append(attempts, attempt{"minItems", []interface{}{copyRawValue(array[:sch.MinItems.Value-1])}})
+		}
+
+		if sch.UniqueItems.IsSet && sch.UniqueItems.Value && len(array) > 0 {
+			candidates := []interface{}{append(copyRawValue(array).([]interface{}), copyRawValue(array[0]))}
+			if len(array) > 1 {
+				replaced := copyRawValue(array).([]interface{})
+				replaced[1] = copyRawValue(array[0])
+				candidates = append(candidates, replaced)
+			}
+
+			attempts = append(attempts, attempt{"uniqueItems", candidates})
+		}
+
+		if sch.Items.IsSet && !sch.Items.IsSingle && sch.AdditionalItems.IsSet && len(array) > 0 {
+			longer := append(copyRawValue(array).([]interface{}), copyRawValue(array[len(array)-1]))
+			attempts = append(attempts, attempt{"additionalItems", []interface{}{longer}})
+		}
+
+		if sch.Contains.IsSet {
+			attempts = append(attempts, attempt{"contains", []interface{}{[]interface{}{}}})
+		}
+	}
+
+	if object, ok := value.(map[string]interface{}); ok {
+		for _, key := range sch.Required.Properties {
+			if _, ok := object[key]; ok {
+				without := copyRawValue(object).(map[string]interface{})
+				delete(without, key)
+				attempts = append(attempts, attempt{"required", []interface{}{without}})
+			}
+		}
+
+		if sch.MaxProperties.IsSet {
+			larger := copyRawValue(object).(map[string]interface{})
+			for i := 1; len(larger) <= sch.MaxProperties.Value; i++ {
+				larger["property"+strconv.Itoa(i)] = nil
+			}
+
+			attempts = append(attempts, attempt{"maxProperties", []interface{}{larger}})
+		}
+
+		if sch.MinProperties.IsSet && sch.MinProperties.Value > 0 {
+			smaller := copyRawValue(object).(map[string]interface{})
+			for _, key := range sortedKeys(object) {
+				if len(smaller) < sch.MinProperties.Value {
+					break
+				}
+
+				delete(smaller, key)
+			}
+
+			attempts = append(attempts, attempt{"minProperties", []interface{}{smaller}})
+		}
+
+		if sch.AdditionalProperties.IsSet {
+			candidates := []interface{}{}
+			for _, extra := range []interface{}{nil, true, 0.0, ""} {
+				larger := copyRawValue(object).(map[string]interface{})
+				larger["additionalProperty"] = extra
+				candidates = append(candidates, larger)
+			}
+
+			attempts = append(attempts, attempt{"additionalProperties", candidates})
+		}
+
+		if sch.PropertyNames.IsSet {
+			candidates := []interface{}{}
+			for _, name := range []string{"", "!", "0", "a", "additionalProperty"} {
+				if _, ok := object[name]; !ok {
+					larger := copyRawValue(object).(map[string]interface{})
+					larger[name] = nil
+					candidates = append(candidates, larger)
+				}
+			}
+
+			attempts = append(attempts, attempt{"propertyNames", candidates})
+		}
+
+		for _, key := range sortedDependencies(sch.Dependencies.Deps) {
+			dep := sch.Dependencies.Deps[key]
+			if _, ok := object[key]; !ok || dep.IsSchema {
+				continue
+			}
+
+			for _, prop := range dep.Properties {
+				without := copyRawValue(object).(map[string]interface{})
+				delete(without, prop)
+				attempts = append(attempts, attempt{"dependencies", []interface{}{without}})
+			}
+		}
+	}
+
+	uri := inv.s.uris[index]
+	schemaPtr, _ := jsonpointer.New(uri.Fragment)
+	uri.Fragment = ""
+
+	for _, a := range attempts {
+		keyword := KeywordLocation{
+			URI:  uri,
+			Path: jsonpointer.Ptr{Tokens: append(schemaPtr.Tokens[:len(schemaPtr.Tokens):len(schemaPtr.Tokens)], a.keyword)},
+		}
+
+		key := keyword.URI.String() + "#" + keyword.Path.String()
+		if inv.seen[key] {
+			continue
+		}
+
+		for _, candidate := range a.candidates {
+			instance := replaceRawValue(inv.root, tokens, candidate)
+			ok, err := inv.violatesOnly(instance, keyword)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				inv.seen[key] = true
+				inv.samples = append(inv.samples, InvalidSample{Instance: instance, Keyword: keyword})
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// violatesOnly determines whether an instance is rejected, solely by the given
+// keyword.
+func (inv *invalidator) violatesOnly(instance interface{}, keyword KeywordLocation) (bool, error) {
+	inv.vm.reset()
+	if err := inv.vm.Exec(inv.uri, instance); err != nil {
+		return false, err
+	}
+
+	result := inv.vm.ValidationResult()
+
+	if result.IsValid() {
+		return false, nil
+	}
+
+	// errors from "required" and "dependencies" point into the keyword
+	for _, e := range result.Errors {
+		if e.URI != keyword.URI || len(e.SchemaPath.Tokens) < len(keyword.Path.Tokens) {
+			return false, nil
+		}
+
+		for i, token := range keyword.Path.Tokens {
+			if e.SchemaPath.Tokens[i] != token {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// otherValues returns values of the same type as value, but different from it,
+// followed by values of other types.
+func otherValues(value interface{}) []interface{} {
+	values := []interface{}{}
+	switch value := value.(type) {
+	case string:
+		values = append(values, value+"x", "")
+	case float64:
+		values = append(values, value+1, value-1)
+	case bool:
+		values = append(values, !value)
+	}
+
+	for _, other := range []interface{}{nil, false, 0.0, "", []interface{}{}, map[string]interface{}{}} {
+		if !jsonEqual(other, value) {
+			values = append(values, other)
+		}
+	}
+
+	return values
+}
+
+// replaceRawValue returns a copy of root with the value at tokens replaced.
+func replaceRawValue(root interface{}, tokens []string, value interface{}) interface{} {
+	if len(tokens) == 0 {
+		return value
+	}
+
+	switch root := root.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(root))
+		for key, elem := range root {
+			out[key] = elem
+		}
+
+		out[tokens[0]] = replaceRawValue(root[tokens[0]], tokens[1:], value)
+		return out
+	case []interface{}:
+		out := append([]interface{}{}, root...)
+		i, _ := strconv.Atoi(tokens[0])
+		out[i] = replaceRawValue(root[i], tokens[1:], value)
+		return out
+	}
+
+	return root
+}
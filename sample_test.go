@@ -0,0 +1,143 @@
+package jsonschema
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplerValid(t *testing.T) {
+	testCases := []string{
+		`true`,
+		`{"type": "null"}`,
+		`{"type": "integer", "minimum": 3, "exclusiveMaximum": 5}`,
+		`{"type": "number", "multipleOf": 0.25, "minimum": -1, "maximum": 1}`,
+		`{"type": "string", "minLength": 2, "maxLength": 4}`,
+		`{"type": "string", "pattern": "^[A-Z]{2}-[0-9]+$"}`,
+		`{"type": "string", "format": "date-time"}`,
+		`{"enum": ["a", 1, null], "type": "number"}`,
+		`{"const": {"a": [1, 2]}}`,
+		`{"type": "array", "items": {"type": "boolean"}, "minItems": 2, "uniqueItems": true}`,
+		`{"type": "array", "items": [{"type": "string"}, {"type": "integer"}], "additionalItems": false, "contains": {"type": "integer"}}`,
+		`{"required": ["a", "b"], "properties": {"a": {"type": "string"}, "c": {"type": "integer"}}, "additionalProperties": {"type": "boolean"}}`,
+		`{"type": "object", "minProperties": 3, "maxProperties": 3, "dependencies": {"a": ["b"]}}`,
+		`{"definitions": {"node": {"type": "object", "properties": {"next": {"$ref": "#/definitions/node"}}}}, "$ref": "#/definitions/node"}`,
+		`{"allOf": [{"type": "integer"}, {"minimum": 10}, {"maximum": 11}]}`,
+		`{"oneOf": [{"type": "string", "maxLength": 1}, {"type": "integer", "minimum": 100}]}`,
+		`{"if": {"type": "string"}, "then": {"minLength": 5}, "else": {"type": "boolean"}}`,
+		`{"patternProperties": {"^x-": {"type": "integer"}}, "propertyNames": {"pattern": "^x-"}, "minProperties": 1}`,
+	}
+
+	for _, tt := range testCases {
+		validator, err := NewValidator([]interface{}{mustDecode(t, tt)})
+		assert.NoError(t, err, tt)
+
+		for seed := int64(0); seed < 20; seed++ {
+			instance, err := NewSampler(&validator, seed).Valid(url.URL{})
+			assert.NoError(t, err, "%s (seed %d)", tt, seed)
+
+			valid, err := validator.IsValid(instance)
+			assert.NoError(t, err)
+			assert.True(t, valid, "%s: %v (seed %d)", tt, instance, seed)
+		}
+	}
+}
+
+func TestSamplerInvalid(t *testing.T) {
+	validator, err := NewValidator([]interface{}{mustDecode(t, `{
+		"type": "object",
+		"required": ["name", "tags"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 8, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"tags": {"type": "array", "items": {"enum": ["a", "b", "c"]}, "minItems": 1, "maxItems": 2, "uniqueItems": true},
+			"kind": {"const": "person"}
+		},
+		"additionalProperties": false
+	}`)})
+	assert.NoError(t, err)
+
+	samples, err := NewSampler(&validator, 1).Invalid(url.URL{})
+	assert.NoError(t, err)
+
+	keywords := []string{}
+	for _, sample := range samples {
+		keywords = append(keywords, sample.Keyword.Path.String())
+
+		result, err := validator.Validate(sample.Instance)
+		assert.NoError(t, err)
+		assert.False(t, result.IsValid(), "%v", sample.Instance)
+	}
+
+	assert.Equal(t, []string{
+		"/type",
+		"/required",
+		"/additionalProperties",
+		"/properties/age/type",
+		"/properties/age/maximum",
+		"/properties/age/minimum",
+		"/properties/kind/const",
+		"/properties/name/type",
+		"/properties/name/maxLength",
+		"/properties/name/minLength",
+		"/properties/name/pattern",
+		"/properties/tags/type",
+		"/properties/tags/maxItems",
+		"/properties/tags/minItems",
+		"/properties/tags/uniqueItems",
+		"/properties/tags/items/enum",
+	}, keywords)
+
+	// the same seed produces the same samples
+	again, err := NewSampler(&validator, 1).Invalid(url.URL{})
+	assert.NoError(t, err)
+	assert.Equal(t, samples, again)
+}
+
+func TestSamplerInvalidMaxErrors(t *testing.T) {
+	// strings too short for "minLength" also fail "pattern", which a limit on
+	// errors could hide
+	schema := mustDecode(t, `{"type": "string", "minLength": 3, "pattern": "^..."}`)
+
+	validator, err := NewValidator([]interface{}{schema})
+	assert.NoError(t, err)
+
+	expected, err := NewSampler(&validator, 1).Invalid(url.URL{})
+	assert.NoError(t, err)
+
+	for _, config := range []ValidatorConfig{
+		{MaxStackDepth: DefaultMaxStackDepth, MaxErrors: 1},
+		{MaxStackDepth: DefaultMaxStackDepth, MaxErrorsPerInstanceLocation: 1},
+		{MaxStackDepth: DefaultMaxStackDepth, MaxErrorsPerKeyword: 1},
+	} {
+		limited, err := NewValidatorWithConfig([]interface{}{schema}, config)
+		assert.NoError(t, err)
+
+		actual, err := NewSampler(&limited, 1).Invalid(url.URL{})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "%+v", config)
+	}
+
+	for _, sample := range expected {
+		result, err := validator.Validate(sample.Instance)
+		assert.NoError(t, err)
+		assert.Len(t, result.Errors, 1, "%v", sample.Instance)
+	}
+}
+
+func TestSamplerErrors(t *testing.T) {
+	validator, err := NewValidator([]interface{}{mustDecode(t, `{"type": "integer", "minimum": 2, "maximum": 1}`)})
+	assert.NoError(t, err)
+
+	sampler := NewSampler(&validator, 0)
+
+	_, err = sampler.Valid(url.URL{})
+	assert.Equal(t, ErrNoSample, err)
+
+	_, err = sampler.Invalid(url.URL{})
+	assert.Equal(t, ErrNoSample, err)
+
+	_, err = sampler.Valid(url.URL{Scheme: "http", Host: "example.com"})
+	assert.Equal(t, ErrNoSuchSchema, err)
+}
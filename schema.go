@@ -1,6 +1,7 @@
 package jsonschema
 
 import (
+	"math"
 	"net/url"
 	"regexp"
 
@@ -43,6 +44,9 @@ type schema struct {
 	AllOf                schemaAllOf
 	AnyOf                schemaAnyOf
 	OneOf                schemaOneOf
+	Discriminator        schemaDiscriminator
+	Keywords             []schemaKeyword
+	Program              schemaProgram
 }
 
 type schemaBool struct {
@@ -106,6 +110,31 @@ func (t schemaType) contains(typ jsonType) bool {
 	return false
 }
 
+// accepts determines whether an instance is of one of the types. Numbers with
+// no fractional part are integers.
+func (t schemaType) accepts(instance interface{}) bool {
+	switch val := instance.(type) {
+	case nil:
+		return t.contains(jsonTypeNull)
+	case bool:
+		return t.contains(jsonTypeBoolean)
+	case float64:
+		if t.contains(jsonTypeInteger) && val == math.Round(val) {
+			return true
+		}
+
+		return t.contains(jsonTypeNumber)
+	case string:
+		return t.contains(jsonTypeString)
+	case []interface{}:
+		return t.contains(jsonTypeArray)
+	case map[string]interface{}:
+		return t.contains(jsonTypeObject)
+	}
+
+	return false
+}
+
 type schemaItems struct {
 	IsSet    bool
 	IsSingle bool
@@ -120,6 +149,7 @@ type schemaConst struct {
 type schemaEnum struct {
 	IsSet  bool
 	Values []interface{}
+	Set    jsonSet
 }
 
 type schemaMultipleOf struct {
@@ -247,3 +277,14 @@ type schemaOneOf struct {
 	IsSet   bool
 	Schemas []int
 }
+
+// schemaDiscriminator is the "discriminator" keyword of OpenAPI. It is not part
+// of JSON Schema, and is only parsed in OpenAPI documents, where it picks the
+// alternative of "oneOf" an instance must match.
+type schemaDiscriminator struct {
+	IsSet    bool
+	Property string
+
+	// Mapping holds the URI of the schema for each value of the property.
+	Mapping map[string]url.URL
+}
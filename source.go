@@ -0,0 +1,518 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// maxDecodeDepth is the deepest nesting of arrays and objects DecodeJSON will
+// accept, so that hostile documents cannot exhaust the stack.
+const maxDecodeDepth = 10000
+
+// Position is a location within a source document.
+//
+// Lines and columns start at 1. As with go/token, columns count bytes, not
+// characters, and a column of 0 means the column is not known.
+type Position struct {
+	// The name of the document, typically a file name. It may be empty.
+	Filename string
+
+	// The offset of the position from the start of the document, in bytes.
+	Offset int
+
+	Line   int
+	Column int
+}
+
+// IsValid indicates whether the position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in the form "file:line:column", the form
+// understood by most editors. Parts which are not known are left out.
+func (p Position) String() string {
+	s := p.Filename
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+
+		s += strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			s += ":" + strconv.Itoa(p.Column)
+		}
+	}
+
+	if s == "" {
+		s = "-"
+	}
+
+	return s
+}
+
+// ErrSyntax indicates that a document could not be decoded.
+type ErrSyntax struct {
+	// Where in the document the problem was found.
+	Position Position
+
+	// A human-readable description of the problem.
+	Message string
+}
+
+// Error fulfills the error interface.
+func (e ErrSyntax) Error() string {
+	return fmt.Sprintf("%s: %s", e.Position.String(), e.Message)
+}
+
+// ErrUnsupportedValue indicates that a document holds a value with no
+// equivalent in JSON, such as a timestamp or a mapping with non-string keys.
+type ErrUnsupportedValue struct {
+	// Where in the document the value appears.
+	Position Position
+
+	// A JSON Pointer to the value within the document.
+	Path jsonpointer.Ptr
+
+	// A human-readable description of the problem.
+	Message string
+}
+
+// Error fulfills the error interface.
+func (e ErrUnsupportedValue) Error() string {
+	return fmt.Sprintf("%s: #%s: %s", e.Position.String(), e.Path.String(), e.Message)
+}
+
+// SourceMap records where each value in a decoded document appears in its
+// source, and the order in which the members of each object were written.
+//
+// Values are identified by JSON Pointers, in the same way as the InstancePath
+// and SchemaPath of a ValidationError.
+type SourceMap struct {
+	values map[string]sourceValue
+}
+
+type sourceValue struct {
+	position Position
+
+	// keys holds, for objects, the names of members in the order in which they
+	// first appear in the source.
+	keys []string
+}
+
+// Position returns the position of the start of the value identified by ptr.
+// If no such value exists, ok is false.
+func (m *SourceMap) Position(ptr jsonpointer.Ptr) (pos Position, ok bool) {
+	if m == nil {
+		return Position{}, false
+	}
+
+	value, ok := m.values[ptr.String()]
+	return value.position, ok
+}
+
+// Keys returns the member names of the object identified by ptr, in the order
+// in which they appear in the source. If no such object exists, ok is false.
+func (m *SourceMap) Keys(ptr jsonpointer.Ptr) (keys []string, ok bool) {
+	if m == nil {
+		return nil, false
+	}
+
+	value, ok := m.values[ptr.String()]
+	if !ok || value.keys == nil {
+		return nil, false
+	}
+
+	keys = make([]string, len(value.keys))
+	copy(keys, value.keys)
+	return keys, true
+}
+
+// annotate sets the InstancePosition of each error, and of their causes.
+func (m *SourceMap) annotate(errors []ValidationError) {
+	for i := range errors {
+		errors[i].InstancePosition = m.nearestPosition(errors[i].InstancePath)
+		m.annotate(errors[i].Causes)
+	}
+}
+
+// nearestPosition returns the position of the value identified by ptr, or of
+// its nearest ancestor which exists.
+func (m *SourceMap) nearestPosition(ptr jsonpointer.Ptr) Position {
+	for i := len(ptr.Tokens); i >= 0; i-- {
+		if pos, ok := m.Position(jsonpointer.Ptr{Tokens: ptr.Tokens[:i]}); ok {
+			return pos
+		}
+	}
+
+	return Position{}
+}
+
+// set records the position of the value at tokens.
+func (m *SourceMap) set(tokens []string, position Position, keys []string) {
+	m.values[jsonpointer.Ptr{Tokens: tokens}.String()] = sourceValue{
+		position: position,
+		keys:     keys,
+	}
+}
+
+// DecodeJSON decodes a JSON document into the same values encoding/json
+// produces when decoding into an interface{}, and records where each value
+// appears in data. The filename is used only in positions.
+//
+// If an object has duplicate member names, the last value wins, as with
+// encoding/json.
+func DecodeJSON(filename string, data []byte) (interface{}, *SourceMap, error) {
+	d := jsonDecoder{
+		data:     data,
+		filename: filename,
+		line:     1,
+		source:   &SourceMap{values: map[string]sourceValue{}},
+	}
+
+	value, err := d.decodeValue()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.skipWhitespace()
+	if d.offset < len(d.data) {
+		return nil, nil, d.errorf("unexpected %q after top-level value", d.data[d.offset])
+	}
+
+	return value, d.source, nil
+}
+
+// lineIndex converts lines and columns as reported by YAML and TOML parsers,
+// which count columns in characters, into Positions.
+type lineIndex struct {
+	filename string
+	data     []byte
+
+	// starts holds the offset of the start of each line
+	starts []int
+}
+
+func newLineIndex(filename string, data []byte) lineIndex {
+	starts := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+
+	return lineIndex{filename: filename, data: data, starts: starts}
+}
+
+func (l lineIndex) position(line, column int) Position {
+	if line < 1 || line > len(l.starts) {
+		return Position{Filename: l.filename}
+	}
+
+	if column < 1 {
+		return Position{Filename: l.filename, Offset: l.starts[line-1], Line: line}
+	}
+
+	offset := l.starts[line-1]
+	for i := 1; i < column && offset < len(l.data) && l.data[offset] != '\n'; i++ {
+		_, size := utf8.DecodeRune(l.data[offset:])
+		offset += size
+	}
+
+	return Position{
+		Filename: l.filename,
+		Offset:   offset,
+		Line:     line,
+		Column:   offset - l.starts[line-1] + 1,
+	}
+}
+
+type jsonDecoder struct {
+	data      []byte
+	filename  string
+	offset    int
+	line      int
+	lineStart int
+	tokens    []string
+	source    *SourceMap
+}
+
+func (d *jsonDecoder) position() Position {
+	return Position{
+		Filename: d.filename,
+		Offset:   d.offset,
+		Line:     d.line,
+		Column:   d.offset - d.lineStart + 1,
+	}
+}
+
+func (d *jsonDecoder) errorf(format string, args ...interface{}) error {
+	return ErrSyntax{Position: d.position(), Message: fmt.Sprintf(format, args...)}
+}
+
+// skipWhitespace advances past whitespace. As raw newlines may only appear in
+// whitespace, this is the only place lines need to be counted.
+func (d *jsonDecoder) skipWhitespace() {
+	for d.offset < len(d.data) {
+		switch d.data[d.offset] {
+		case '\n':
+			d.line++
+			d.lineStart = d.offset + 1
+		case ' ', '\t', '\r':
+		default:
+			return
+		}
+
+		d.offset++
+	}
+}
+
+func (d *jsonDecoder) decodeValue() (interface{}, error) {
+	d.skipWhitespace()
+	if d.offset == len(d.data) {
+		return nil, d.errorf("unexpected end of input")
+	}
+
+	if len(d.tokens) > maxDecodeDepth {
+		return nil, d.errorf("exceeded max depth of %d", maxDecodeDepth)
+	}
+
+	position := d.position()
+
+	switch c := d.data[d.offset]; {
+	case c == '{':
+		return d.decodeObject(position)
+	case c == '[':
+		return d.decodeArray(position)
+	case c == '"':
+		d.source.set(d.tokens, position, nil)
+		return d.decodeString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		d.source.set(d.tokens, position, nil)
+		return d.decodeNumber()
+	default:
+		d.source.set(d.tokens, position, nil)
+		return d.decodeLiteral()
+	}
+}
+
+func (d *jsonDecoder) decodeObject(position Position) (interface{}, error) {
+	d.offset++ // skip '{'
+
+	object := map[string]interface{}{}
+	keys := []string{}
+
+	d.skipWhitespace()
+	if d.offset < len(d.data) && d.data[d.offset] == '}' {
+		d.offset++
+		d.source.set(d.tokens, position, keys)
+		return object, nil
+	}
+
+	for {
+		d.skipWhitespace()
+		if d.offset == len(d.data) || d.data[d.offset] != '"' {
+			return nil, d.unexpected("object key")
+		}
+
+		key, err := d.decodeString()
+		if err != nil {
+			return nil, err
+		}
+
+		d.skipWhitespace()
+		if d.offset == len(d.data) || d.data[d.offset] != ':' {
+			return nil, d.unexpected("':' after object key")
+		}
+
+		d.offset++
+
+		d.tokens = append(d.tokens, key)
+		value, err := d.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+		d.tokens = d.tokens[:len(d.tokens)-1]
+
+		if _, ok := object[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		object[key] = value
+
+		d.skipWhitespace()
+		if d.offset == len(d.data) {
+			return nil, d.unexpected("',' or '}' after object value")
+		}
+
+		switch d.data[d.offset] {
+		case ',':
+			d.offset++
+		case '}':
+			d.offset++
+			d.source.set(d.tokens, position, keys)
+			return object, nil
+		default:
+			return nil, d.unexpected("',' or '}' after object value")
+		}
+	}
+}
+
+func (d *jsonDecoder) decodeArray(position Position) (interface{}, error) {
+	d.offset++ // skip '['
+	d.source.set(d.tokens, position, nil)
+
+	array := []interface{}{}
+
+	d.skipWhitespace()
+	if d.offset < len(d.data) && d.data[d.offset] == ']' {
+		d.offset++
+		return array, nil
+	}
+
+	for {
+		d.tokens = append(d.tokens, strconv.Itoa(len(array)))
+		value, err := d.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+		d.tokens = d.tokens[:len(d.tokens)-1]
+
+		array = append(array, value)
+
+		d.skipWhitespace()
+		if d.offset == len(d.data) {
+			return nil, d.unexpected("',' or ']' after array element")
+		}
+
+		switch d.data[d.offset] {
+		case ',':
+			d.offset++
+		case ']':
+			d.offset++
+			return array, nil
+		default:
+			return nil, d.unexpected("',' or ']' after array element")
+		}
+	}
+}
+
+func (d *jsonDecoder) decodeString() (string, error) {
+	start := d.offset
+	d.offset++ // skip '"'
+
+	escaped := false
+	for d.offset < len(d.data) {
+		switch c := d.data[d.offset]; {
+		case c == '"':
+			d.offset++
+
+			raw := d.data[start+1 : d.offset-1]
+			if !escaped && utf8.Valid(raw) {
+				return string(raw), nil
+			}
+
+			// escapes and invalid UTF-8 are rare, and handled by encoding/json
+			var str string
+			if err := json.Unmarshal(d.data[start:d.offset], &str); err != nil {
+				d.offset = start
+				return "", d.errorf("invalid string literal")
+			}
+
+			return str, nil
+		case c == '\\':
+			escaped = true
+			d.offset += 2
+		case c < 0x20:
+			return "", d.errorf("invalid character %q in string literal", c)
+		default:
+			d.offset++
+		}
+	}
+
+	return "", d.errorf("unexpected end of input in string literal")
+}
+
+func (d *jsonDecoder) decodeNumber() (interface{}, error) {
+	start := d.offset
+
+	if d.data[d.offset] == '-' {
+		d.offset++
+	}
+
+	if d.offset < len(d.data) && d.data[d.offset] == '0' {
+		d.offset++
+	} else if !d.skipDigits() {
+		return nil, d.unexpected("digit")
+	}
+
+	if d.offset < len(d.data) && d.data[d.offset] == '.' {
+		d.offset++
+		if !d.skipDigits() {
+			return nil, d.unexpected("digit after decimal point")
+		}
+	}
+
+	if d.offset < len(d.data) && (d.data[d.offset] == 'e' || d.data[d.offset] == 'E') {
+		d.offset++
+		if d.offset < len(d.data) && (d.data[d.offset] == '+' || d.data[d.offset] == '-') {
+			d.offset++
+		}
+
+		if !d.skipDigits() {
+			return nil, d.unexpected("digit in exponent")
+		}
+	}
+
+	literal := string(d.data[start:d.offset])
+	number, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		d.offset = start
+		return nil, d.errorf("number %s is out of range", literal)
+	}
+
+	return number, nil
+}
+
+// skipDigits advances past a run of digits, returning false if there were none.
+func (d *jsonDecoder) skipDigits() bool {
+	start := d.offset
+	for d.offset < len(d.data) && d.data[d.offset] >= '0' && d.data[d.offset] <= '9' {
+		d.offset++
+	}
+
+	return d.offset > start
+}
+
+func (d *jsonDecoder) decodeLiteral() (interface{}, error) {
+	switch {
+	case d.hasPrefix("true"):
+		d.offset += len("true")
+		return true, nil
+	case d.hasPrefix("false"):
+		d.offset += len("false")
+		return false, nil
+	case d.hasPrefix("null"):
+		d.offset += len("null")
+		return nil, nil
+	default:
+		return nil, d.unexpected("value")
+	}
+}
+
+func (d *jsonDecoder) hasPrefix(literal string) bool {
+	end := d.offset + len(literal)
+	return end <= len(d.data) && string(d.data[d.offset:end]) == literal
+}
+
+func (d *jsonDecoder) unexpected(expected string) error {
+	if d.offset == len(d.data) {
+		return d.errorf("unexpected end of input, expecting %s", expected)
+	}
+
+	return d.errorf("unexpected %q, expecting %s", d.data[d.offset], expected)
+}
@@ -0,0 +1,296 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucarion/json-pointer"
+)
+
+func TestDecodeJSONMatchesEncodingJSON(t *testing.T) {
+	paths, err := filepath.Glob("tests/*.json")
+	assert.NoError(t, err)
+
+	inputs := []string{
+		`null`, `true`, `false`, `0`, `-0.5e+3`, `1E2`, `"\u00e9\n\"\\"`, `"é"`,
+		`""`, "\"a\xffb\"", `[]`, `{}`, ` [ 1 , [ 2 ] , { "a" : { } } ] `, `{"a": 1, "a": 2}`,
+	}
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		inputs = append(inputs, string(data))
+	}
+
+	for _, input := range inputs {
+		var expected interface{}
+		assert.NoError(t, json.Unmarshal([]byte(input), &expected), input)
+
+		actual, _, err := DecodeJSON("", []byte(input))
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, actual, input)
+	}
+}
+
+func TestDecodeJSONPositions(t *testing.T) {
+	input := "{\n  \"b\": [true,\n    {\"c\": null}],\n  \"a\": \"x\",\n  \"a~/\": 1\n}"
+
+	_, source, err := DecodeJSON("doc.json", []byte(input))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		ptr    string
+		line   int
+		column int
+		offset int
+	}{
+		{"", 1, 1, 0},
+		{"/b", 2, 8, 9},
+		{"/b/0", 2, 9, 10},
+		{"/b/1", 3, 5, 20},
+		{"/b/1/c", 3, 11, 26},
+		{"/a", 4, 8, 41},
+		{"/a~0~1", 5, 10, 55},
+	}
+
+	for _, tt := range testCases {
+		ptr, err := jsonpointer.New(tt.ptr)
+		assert.NoError(t, err)
+
+		pos, ok := source.Position(ptr)
+		assert.True(t, ok, tt.ptr)
+		assert.Equal(t, Position{Filename: "doc.json", Offset: tt.offset, Line: tt.line, Column: tt.column}, pos, tt.ptr)
+	}
+
+	_, ok := source.Position(jsonpointer.Ptr{Tokens: []string{"nope"}})
+	assert.False(t, ok)
+
+	keys, ok := source.Keys(jsonpointer.Ptr{})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"b", "a", "a~/"}, keys)
+
+	_, ok = source.Keys(jsonpointer.Ptr{Tokens: []string{"b"}})
+	assert.False(t, ok)
+}
+
+func TestDecodeJSONSyntaxErrors(t *testing.T) {
+	testCases := []struct {
+		input   string
+		message string
+	}{
+		{"", "1:1: unexpected end of input"},
+		{"{\n  \"a\" 1}", "2:7: unexpected '1', expecting ':' after object key"},
+		{"[1,\n2,]", "2:3: unexpected ']', expecting value"},
+		{"[1 2]", "1:4: unexpected '2', expecting ',' or ']' after array element"},
+		{"{\"a\": 1,}", "1:9: unexpected '}', expecting object key"},
+		{"01", "1:2: unexpected '1' after top-level value"},
+		{"-", "1:2: unexpected end of input, expecting digit"},
+		{"1.", "1:3: unexpected end of input, expecting digit after decimal point"},
+		{"1e400", "1:1: number 1e400 is out of range"},
+		{"\"a\tb\"", "1:3: invalid character '\\t' in string literal"},
+		{"\"\\x\"", "1:1: invalid string literal"},
+		{"\"abc", "1:5: unexpected end of input in string literal"},
+		{"nul", "1:1: unexpected 'n', expecting value"},
+	}
+
+	for _, tt := range testCases {
+		_, _, err := DecodeJSON("", []byte(tt.input))
+		assert.Error(t, err, tt.input)
+		assert.IsType(t, ErrSyntax{}, err, tt.input)
+		if err != nil {
+			assert.Equal(t, tt.message, err.Error(), tt.input)
+		}
+	}
+}
+
+func TestPositionString(t *testing.T) {
+	assert.Equal(t, "-", Position{}.String())
+	assert.Equal(t, "a.json", Position{Filename: "a.json"}.String())
+	assert.Equal(t, "3:4", Position{Line: 3, Column: 4}.String())
+	assert.Equal(t, "a.json:3:4", Position{Filename: "a.json", Line: 3, Column: 4}.String())
+}
+
+func TestNewValidatorFromJSON(t *testing.T) {
+	validator, err := NewValidatorFromJSON([]Source{
+		{
+			Name: "root.json",
+			Data: []byte(`{
+  "properties": {
+    "age": {"type": "integer"},
+    "pet": {"$ref": "http://example.com/pet#/definitions/name"}
+  }
+}`),
+		},
+		{
+			Name: "pet.json",
+			Data: []byte(`{
+  "$id": "http://example.com/pet",
+  "definitions": {
+    "name": {"minLength": 2}
+  }
+}`),
+		},
+	}, ValidatorConfig{MaxStackDepth: DefaultMaxStackDepth})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate(map[string]interface{}{"age": "x", "pet": "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Errors))
+
+	positions := []string{}
+	for _, e := range result.Errors {
+		pos, ok := validator.SchemaPosition(e.URI, e.SchemaPath)
+		assert.True(t, ok)
+		positions = append(positions, pos.String())
+	}
+
+	assert.Equal(t, []string{"root.json:3:21", "pet.json:4:27"}, positions)
+
+	// schemas not given as JSON have no positions
+	validator, err = NewValidator([]interface{}{map[string]interface{}{}})
+	assert.NoError(t, err)
+
+	_, ok := validator.SchemaPosition(url.URL{}, jsonpointer.Ptr{})
+	assert.False(t, ok)
+}
+
+func TestNewValidatorFromJSONSyntaxError(t *testing.T) {
+	_, err := NewValidatorFromJSON([]Source{
+		{Name: "a.json", Data: []byte(`{}`)},
+		{Name: "b.json", Data: []byte("{\n  \"type\": }")},
+	}, ValidatorConfig{})
+
+	assert.Equal(t, ErrSyntax{
+		Position: Position{Filename: "b.json", Offset: 12, Line: 2, Column: 11},
+		Message:  "unexpected '}', expecting value",
+	}, err)
+}
+
+func TestNewValidatorFromJSONSchemaErrors(t *testing.T) {
+	_, err := NewValidatorFromJSON([]Source{
+		{
+			Name: "schema.json",
+			Data: []byte(`{
+  "zz-unknown": true,
+  "minLength": -1,
+  "aa-unknown": true
+}`),
+		},
+	}, ValidatorConfig{ValidateSchemas: true, StrictKeywords: true})
+
+	schemaErrors, ok := err.(ErrSchemaErrors)
+	assert.True(t, ok)
+
+	messages := []string{}
+	for _, e := range schemaErrors.Errors {
+		messages = append(messages, e.Error())
+	}
+
+	assert.Equal(t, []string{
+		`schema.json:2:17: #/zz-unknown: unknown keyword "zz-unknown"`,
+		"schema.json:3:16: #/minLength: rejected by meta-schema at http://json-schema.org/draft-07/schema#/definitions/nonNegativeInteger/minimum",
+		`schema.json:4:17: #/aa-unknown: unknown keyword "aa-unknown"`,
+	}, messages)
+}
+
+func TestNewValidatorFromJSONKeywords(t *testing.T) {
+	order := []string{}
+	keyword := func(name string) KeywordCompiler {
+		return func(value interface{}, location KeywordLocation) (KeywordEvaluator, error) {
+			if value != true {
+				return nil, errors.New("must be true")
+			}
+
+			return func(instance interface{}, reporter *KeywordReporter) error {
+				order = append(order, name)
+				return nil
+			}, nil
+		}
+	}
+
+	keywords := map[string]KeywordCompiler{"x-b": keyword("x-b"), "x-a": keyword("x-a")}
+
+	validator, err := NewValidatorFromJSON([]Source{
+		{Name: "schema.json", Data: []byte(`{"x-b": true, "x-a": true}`)},
+	}, ValidatorConfig{Keywords: keywords})
+	assert.NoError(t, err)
+
+	_, err = validator.Validate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x-b", "x-a"}, order)
+
+	_, err = NewValidatorFromJSON([]Source{
+		{Name: "schema.json", Data: []byte("{\n  \"x-a\": false\n}")},
+	}, ValidatorConfig{Keywords: keywords})
+
+	schemaErr, ok := err.(SchemaError)
+	assert.True(t, ok)
+	assert.Equal(t, "schema.json:2:10: #/x-a: invalid value for keyword \"x-a\": must be true", schemaErr.Error())
+}
+
+func TestValidatorValidateJSON(t *testing.T) {
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"servers": map[string]interface{}{
+					"items": map[string]interface{}{
+						"required": []interface{}{"host"},
+						"properties": map[string]interface{}{
+							"port": map[string]interface{}{"type": "integer"},
+						},
+					},
+				},
+				"mode": map[string]interface{}{
+					"anyOf": []interface{}{
+						map[string]interface{}{"const": "a"},
+						map[string]interface{}{"const": "b"},
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.ValidateJSON("config.json", []byte(`{
+  "servers": [
+    {"host": "a", "port": 80},
+    {"port": "x"}
+  ],
+  "mode": "c"
+}`))
+	assert.NoError(t, err)
+
+	positions := []string{}
+	for _, e := range result.Errors {
+		positions = append(positions, e.InstancePath.String()+" "+e.InstancePosition.String())
+		for _, cause := range e.Causes {
+			positions = append(positions, "  "+cause.InstancePath.String()+" "+cause.InstancePosition.String())
+		}
+	}
+
+	assert.Equal(t, []string{
+		"/mode config.json:6:11",
+		"  /mode config.json:6:11",
+		"  /mode config.json:6:11",
+		"/servers/1 config.json:4:5",
+		"/servers/1/port config.json:4:14",
+	}, positions)
+
+	assert.Equal(t, 61, result.Errors[2].InstancePosition.Offset)
+
+	_, err = validator.ValidateJSON("config.json", []byte(`{"servers": [}`))
+	assert.IsType(t, ErrSyntax{}, err)
+}
+
+func TestSourceMapNearestPosition(t *testing.T) {
+	_, source, err := DecodeJSON("", []byte(`{"a": {"b": 1}}`))
+	assert.NoError(t, err)
+
+	pos := source.nearestPosition(jsonpointer.Ptr{Tokens: []string{"a", "missing", "deeper"}})
+	assert.Equal(t, Position{Offset: 6, Line: 1, Column: 7}, pos)
+}
@@ -0,0 +1,179 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"github.com/ucarion/json-pointer"
+)
+
+// tomlErrorPattern matches the position out of errors from go-toml.
+var tomlErrorPattern = regexp.MustCompile(`(?s)^\((\d+), (\d+)\): (.*)$`)
+
+// DecodeTOML decodes a TOML document into the same values DecodeJSON produces
+// for the equivalent JSON document, and records where each value appears in
+// data. The filename is used only in positions.
+//
+// Dates and times have no equivalent in JSON, and are rejected with an
+// instance of ErrUnsupportedValue, as are infinite and NaN numbers. Integers
+// are converted to float64, as with JSON numbers.
+//
+// If data is not valid TOML, an instance of ErrSyntax is returned. Elements of
+// arrays are given the position of the array, as go-toml does not record the
+// positions of array elements.
+func DecodeTOML(filename string, data []byte) (interface{}, *SourceMap, error) {
+	d := tomlDecoder{
+		lines:  newLineIndex(filename, data),
+		source: &SourceMap{values: map[string]sourceValue{}},
+	}
+
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		if match := tomlErrorPattern.FindStringSubmatch(err.Error()); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			column, _ := strconv.Atoi(match[2])
+			return nil, nil, ErrSyntax{Position: d.lines.position(line, column), Message: strings.TrimSpace(match[3])}
+		}
+
+		return nil, nil, ErrSyntax{Position: Position{Filename: filename}, Message: err.Error()}
+	}
+
+	value, err := d.decodeTree(tree, d.lines.position(1, 1), false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return value, d.source, nil
+}
+
+type tomlDecoder struct {
+	lines  lineIndex
+	source *SourceMap
+	tokens []string
+}
+
+// decodeTree decodes a table. The positions go-toml records within inline tables
+// are unreliable, so values within them are given the position of the table.
+func (d *tomlDecoder) decodeTree(tree *toml.Tree, position Position, inline bool) (interface{}, error) {
+	keys := tree.Keys()
+
+	// go-toml does not keep the order of keys, so recover it from positions
+	sort.SliceStable(keys, func(i, j int) bool {
+		a := tree.GetPositionPath([]string{keys[i]})
+		b := tree.GetPositionPath([]string{keys[j]})
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		if a.Col != b.Col {
+			return a.Col < b.Col
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	object := map[string]interface{}{}
+	for _, key := range keys {
+		path := []string{key}
+
+		valuePosition := position
+		if keyPosition := tree.GetPositionPath(path); !inline && !keyPosition.Invalid() {
+			valuePosition = d.lines.position(keyPosition.Line, keyPosition.Col)
+		}
+
+		d.tokens = append(d.tokens, key)
+		value, err := d.decodeValue(tree.GetPath(path), valuePosition)
+		if err != nil {
+			return nil, err
+		}
+		d.tokens = d.tokens[:len(d.tokens)-1]
+
+		object[key] = value
+	}
+
+	d.source.set(d.tokens, position, keys)
+	return object, nil
+}
+
+func (d *tomlDecoder) decodeValue(value interface{}, position Position) (interface{}, error) {
+	switch value := value.(type) {
+	case *toml.Tree:
+		// go-toml only records the positions of tables with headers
+		treePosition := value.Position()
+		if treePosition.Invalid() {
+			return d.decodeTree(value, position, true)
+		}
+
+		return d.decodeTree(value, d.lines.position(treePosition.Line, treePosition.Col), false)
+	case []*toml.Tree:
+		array := make([]interface{}, len(value))
+		for i, tree := range value {
+			d.tokens = append(d.tokens, strconv.Itoa(i))
+			elem, err := d.decodeValue(tree, position)
+			if err != nil {
+				return nil, err
+			}
+			d.tokens = d.tokens[:len(d.tokens)-1]
+
+			array[i] = elem
+		}
+
+		d.source.set(d.tokens, position, nil)
+		return array, nil
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, v := range value {
+			d.tokens = append(d.tokens, strconv.Itoa(i))
+			elem, err := d.decodeValue(v, position)
+			if err != nil {
+				return nil, err
+			}
+			d.tokens = d.tokens[:len(d.tokens)-1]
+
+			array[i] = elem
+		}
+
+		d.source.set(d.tokens, position, nil)
+		return array, nil
+	}
+
+	d.source.set(d.tokens, position, nil)
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case bool:
+		return value, nil
+	case int64:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	case float64:
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, d.unsupported(position, "number %v cannot be represented in JSON", value)
+		}
+
+		return value, nil
+	case time.Time, toml.LocalDate, toml.LocalDateTime, toml.LocalTime:
+		return nil, d.unsupported(position, "date or time %v cannot be represented in JSON; quote it to make it a string", value)
+	default:
+		return nil, d.unsupported(position, "unsupported value of type %T", value)
+	}
+}
+
+func (d *tomlDecoder) unsupported(position Position, format string, args ...interface{}) error {
+	tokens := make([]string, len(d.tokens))
+	copy(tokens, d.tokens)
+
+	return ErrUnsupportedValue{
+		Position: position,
+		Path:     jsonpointer.Ptr{Tokens: tokens},
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,101 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucarion/json-pointer"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	testCases := []struct {
+		toml string
+		json string
+	}{
+		{"", `{}`},
+		{"a = 1\nb = [\"x\", 2.5]\n[c]\nd = \"é\"\n", `{"a": 1, "b": ["x", 2.5], "c": {"d": "é"}}`},
+		{"[[e]]\nf = 1\n[[e]]\nf = true\n", `{"e": [{"f": 1}, {"f": true}]}`},
+		{"a = {b = 1, c = [1, 2]}\n\"x.y\" = 3\n", `{"a": {"b": 1, "c": [1, 2]}, "x.y": 3}`},
+	}
+
+	for _, tt := range testCases {
+		expected, _, err := DecodeJSON("", []byte(tt.json))
+		assert.NoError(t, err, tt.json)
+
+		actual, _, err := DecodeTOML("", []byte(tt.toml))
+		assert.NoError(t, err, tt.toml)
+		assert.Equal(t, expected, actual, tt.toml)
+	}
+}
+
+func TestDecodeTOMLPositions(t *testing.T) {
+	input := "z = 1\ny = [1, 2]\n\n[server]\nport = 80\ninline = {a = 1}\n\n[[db]]\nhost = \"a\"\n"
+
+	_, source, err := DecodeTOML("conf.toml", []byte(input))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		ptr string
+		pos string
+	}{
+		{"", "conf.toml:1:1"},
+		{"/z", "conf.toml:1:1"},
+		{"/y", "conf.toml:2:1"},
+		{"/y/1", "conf.toml:2:1"},
+		{"/server", "conf.toml:4:1"},
+		{"/server/port", "conf.toml:5:1"},
+		{"/server/inline/a", "conf.toml:4:1"},
+		{"/db/0", "conf.toml:8:1"},
+		{"/db/0/host", "conf.toml:9:1"},
+	}
+
+	for _, tt := range testCases {
+		ptr, err := jsonpointer.New(tt.ptr)
+		assert.NoError(t, err)
+
+		pos, ok := source.Position(ptr)
+		assert.True(t, ok, tt.ptr)
+		assert.Equal(t, tt.pos, pos.String(), tt.ptr)
+	}
+
+	keys, ok := source.Keys(jsonpointer.Ptr{})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"z", "y", "server", "db"}, keys)
+}
+
+func TestDecodeTOMLErrors(t *testing.T) {
+	testCases := []struct {
+		input string
+		err   string
+	}{
+		{"a = \n", "conf.toml:2:1: expecting a value"},
+		{"a = inf\n", "conf.toml:1:1: #/a: number +Inf cannot be represented in JSON"},
+		{"a = 1979-05-27T07:32:00Z\n", "conf.toml:1:1: #/a: date or time 1979-05-27 07:32:00 +0000 UTC cannot be represented in JSON; quote it to make it a string"},
+		{"[t]\na = [1979-05-27T07:32:00]\n", "conf.toml:2:1: #/t/a/0: date or time 1979-05-27T07:32:00 cannot be represented in JSON; quote it to make it a string"},
+	}
+
+	for _, tt := range testCases {
+		_, _, err := DecodeTOML("conf.toml", []byte(tt.input))
+		if assert.Error(t, err, tt.input) {
+			assert.Equal(t, tt.err, err.Error(), tt.input)
+		}
+	}
+}
+
+func TestValidatorValidateTOML(t *testing.T) {
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"server": map[string]interface{}{
+					"required": []interface{}{"host"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.ValidateTOML("conf.toml", []byte("name = \"x\"\n\n[server]\nport = 80\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Errors))
+	assert.Equal(t, "conf.toml:3:1", result.Errors[0].InstancePosition.String())
+}
@@ -0,0 +1,210 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// TraceNode is a single step in the evaluation of an instance, as recorded when
+// the Trace option of ValidatorConfig is enabled.
+//
+// There are two kinds of nodes. Nodes whose SchemaPath points to a schema
+// record the evaluation of that schema, and have a child for each keyword
+// evaluated. Nodes whose SchemaPath points to a keyword record the evaluation of
+// that keyword, and have a child for each subschema the keyword evaluated,
+// including subschemas of "not", "if", "anyOf", "oneOf" and "contains" which do
+// not themselves produce errors.
+type TraceNode struct {
+	// A JSON Pointer to the part of the instance being evaluated.
+	InstancePath jsonpointer.Ptr
+
+	// A JSON Pointer to the schema or keyword being evaluated.
+	SchemaPath jsonpointer.Ptr
+
+	// The URI of the schema being evaluated.
+	URI url.URL
+
+	// Keyword is the name of the keyword being evaluated. It is empty for nodes
+	// representing the evaluation of a schema.
+	Keyword string
+
+	// Valid indicates whether the instance was accepted.
+	Valid bool
+
+	// Children holds the steps taken while evaluating this step.
+	Children []*TraceNode
+}
+
+// String renders the trace as an indented tree, with one step per line.
+func (n *TraceNode) String() string {
+	var b strings.Builder
+	n.write(&b, 0)
+	return b.String()
+}
+
+func (n *TraceNode) write(b *strings.Builder, depth int) {
+	status := "pass"
+	if !n.Valid {
+		status = "fail"
+	}
+
+	uri := n.URI
+	uri.Fragment = ""
+
+	fmt.Fprintf(b, "%s%s %s#%s at %q\n", strings.Repeat("  ", depth), status,
+		uri.String(), n.SchemaPath.String(), n.InstancePath.String())
+
+	for _, child := range n.Children {
+		child.write(b, depth+1)
+	}
+}
+
+// MarshalJSON fulfills the json.Marshaler interface. Paths and URIs are encoded
+// as strings.
+func (n *TraceNode) MarshalJSON() ([]byte, error) {
+	children := n.Children
+	if children == nil {
+		children = []*TraceNode{}
+	}
+
+	return json.Marshal(struct {
+		InstancePath string       `json:"instancePath"`
+		SchemaPath   string       `json:"schemaPath"`
+		URI          string       `json:"uri"`
+		Keyword      string       `json:"keyword,omitempty"`
+		Valid        bool         `json:"valid"`
+		Children     []*TraceNode `json:"children"`
+	}{
+		InstancePath: n.InstancePath.String(),
+		SchemaPath:   n.SchemaPath.String(),
+		URI:          n.URI.String(),
+		Keyword:      n.Keyword,
+		Valid:        n.Valid,
+		Children:     children,
+	})
+}
+
+// tracer builds up a tree of TraceNode as the vm evaluates an instance.
+type tracer struct {
+	// root is the first node entered
+	root *TraceNode
+
+	// frames is a stack of the nodes currently being evaluated
+	frames []traceFrame
+}
+
+type traceFrame struct {
+	// node is the node being evaluated
+	node *TraceNode
+
+	// errors is the number of errors the vm had when the node was entered
+	errors int
+
+	// failed is whether errors were reported while the node was entered before
+	// being suspended
+	failed bool
+}
+
+// traceEnter starts a new step of the trace. If keyword is empty, the step is
+// the evaluation of the current schema; otherwise, it is the evaluation of the
+// given keyword of the current schema.
+func (vm *vm) traceEnter(keyword string) {
+	// coverage is recorded at the same steps as the trace
+	vm.coverageEnter(keyword)
+
+	if vm.trace == nil {
+		return
+	}
+
+	schemaPath := vm.schemaPath()
+	if keyword != "" {
+		schemaPath.Tokens = append(schemaPath.Tokens, keyword)
+	}
+
+	node := &TraceNode{
+		InstancePath: vm.instancePath(),
+		SchemaPath:   schemaPath,
+		URI:          vm.schemaID(),
+		Keyword:      keyword,
+	}
+
+	if len(vm.trace.frames) == 0 {
+		vm.trace.root = node
+	} else {
+		parent := vm.trace.frames[len(vm.trace.frames)-1].node
+		parent.Children = append(parent.Children, node)
+	}
+
+	vm.trace.frames = append(vm.trace.frames, traceFrame{
+		node:   node,
+		errors: vm.errors.count,
+	})
+}
+
+// traceExit ends the most recently entered step of the trace. The step is
+// considered valid if no errors were reported since it was entered.
+func (vm *vm) traceExit() {
+	vm.coverageExit()
+
+	if vm.trace == nil {
+		return
+	}
+
+	frame := vm.trace.frames[len(vm.trace.frames)-1]
+	frame.node.Valid = !frame.failed && vm.errors.count == frame.errors
+	vm.trace.frames = vm.trace.frames[:len(vm.trace.frames)-1]
+}
+
+// traceStep is a step of the trace which has been suspended. See traceSuspend.
+type traceStep struct {
+	trace    traceFrame
+	coverage coverageFrame
+}
+
+// traceSuspend leaves the most recently entered step of the trace without
+// ending it, so that it can be resumed with traceResume. This lets a keyword
+// which is evaluated for each property in turn with other keywords, such as
+// "properties", be recorded as a single step.
+func (vm *vm) traceSuspend() traceStep {
+	step := traceStep{coverage: vm.coverageSuspend()}
+	if vm.trace == nil {
+		return step
+	}
+
+	step.trace = vm.trace.frames[len(vm.trace.frames)-1]
+	step.trace.failed = step.trace.failed || vm.errors.count != step.trace.errors
+	vm.trace.frames = vm.trace.frames[:len(vm.trace.frames)-1]
+
+	return step
+}
+
+// traceResume re-enters a step of the trace suspended by traceSuspend.
+func (vm *vm) traceResume(step traceStep) {
+	vm.coverageResume(step.coverage)
+	if vm.trace == nil {
+		return
+	}
+
+	step.trace.errors = vm.errors.count
+	vm.trace.frames = append(vm.trace.frames, step.trace)
+}
+
+// traceAbort ends every step of the trace which has not yet been exited, as
+// happens when evaluation quits early. Such steps are all considered invalid.
+func (vm *vm) traceAbort() {
+	vm.coverageAbort()
+
+	if vm.trace == nil {
+		return
+	}
+
+	for _, frame := range vm.trace.frames {
+		frame.node.Valid = false
+	}
+
+	vm.trace.frames = vm.trace.frames[:0]
+}
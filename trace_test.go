@@ -0,0 +1,88 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatorTrace(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"foo": map[string]interface{}{
+					"oneOf": []interface{}{
+						map[string]interface{}{"type": "string"},
+						map[string]interface{}{"minLength": 3.0},
+					},
+				},
+			},
+		},
+	}
+
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		Trace:         true,
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate(map[string]interface{}{"foo": "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		"fail # at \"\"\n"+
+		"  fail #/properties at \"\"\n"+
+		"    fail #/properties/foo at \"/foo\"\n"+
+		"      fail #/properties/foo/oneOf at \"/foo\"\n"+
+		"        pass #/properties/foo/oneOf/0 at \"/foo\"\n"+
+		"          pass #/properties/foo/oneOf/0/type at \"/foo\"\n"+
+		"        pass #/properties/foo/oneOf/1 at \"/foo\"\n"+
+		"          pass #/properties/foo/oneOf/1/minLength at \"/foo\"\n",
+		result.Trace.String())
+
+	data, err := json.Marshal(result.Trace.Children[0].Children[0].Children[0].Children[0].Children[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"instancePath": "/foo",
+		"schemaPath": "/properties/foo/oneOf/0/type",
+		"uri": "",
+		"keyword": "type",
+		"valid": true,
+		"children": []
+	}`, string(data))
+}
+
+func TestValidatorTraceProperties(t *testing.T) {
+	validator, err := NewValidatorWithConfig([]interface{}{mustDecode(t, `{
+		"properties": {"a": {"type": "integer"}, "b": {"type": "string"}},
+		"additionalProperties": {"type": "null"}
+	}`)}, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		Trace:         true,
+	})
+	assert.NoError(t, err)
+
+	// each keyword is one step, however many properties it applies to
+	result, err := validator.Validate(mustDecode(t, `{"a": 1, "b": 2, "c": null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		"fail # at \"\"\n"+
+		"  fail #/properties at \"\"\n"+
+		"    pass #/properties/a at \"/a\"\n"+
+		"      pass #/properties/a/type at \"/a\"\n"+
+		"    fail #/properties/b at \"/b\"\n"+
+		"      fail #/properties/b/type at \"/b\"\n"+
+		"  pass #/additionalProperties at \"\"\n"+
+		"    pass #/additionalProperties at \"/c\"\n"+
+		"      pass #/additionalProperties/type at \"/c\"\n",
+		result.Trace.String())
+}
+
+func TestValidatorTraceDisabled(t *testing.T) {
+	validator, err := NewValidator([]interface{}{map[string]interface{}{}})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result.Trace)
+}
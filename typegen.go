@@ -0,0 +1,922 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ucarion/json-pointer"
+)
+
+// GenerateTypesConfig contains configuration for GenerateGoTypes.
+type GenerateTypesConfig struct {
+	// Package is the name of the package the generated code belongs to.
+	Package string
+
+	// Validator holds the schemas to generate types from.
+	Validator *Validator
+
+	// Types describes the types to generate. Types for the schemas they refer
+	// to are generated as needed.
+	Types []GenerateType
+}
+
+// GenerateType describes a type to be generated by GenerateGoTypes.
+type GenerateType struct {
+	// Name is the name of the type. If empty, it is taken from the schema, as
+	// for the types of the schemas it refers to.
+	Name string
+
+	// URI identifies the schema in Validator to generate the type from. The
+	// empty URI identifies the default schema.
+	URI url.URL
+}
+
+// GenerateGoTypes writes the source code of a Go file holding types which
+// instances of schemas can be decoded into with encoding/json.
+//
+// Schemas map to Go types as follows:
+//
+//   - objects with "properties" become structs, with a field for each property
+//     and for the properties of any object schemas in "allOf". Properties which
+//     are not required, or which may be null, become pointers, unless their
+//     type is a slice, map or interface.
+//   - objects without "properties" become maps, of the type of
+//     "additionalProperties".
+//   - arrays become slices, of the type of "items".
+//   - strings with an "enum" become named string types, with a constant for
+//     each value.
+//   - "oneOf" of objects becomes an interface implemented by the struct of each
+//     alternative. A function named Unmarshal followed by the name of the
+//     interface decodes the alternative an instance holds, by a property which
+//     has a different "const" in each alternative if there is one, or else by
+//     the first alternative which has every property of the instance. Structs
+//     holding such interfaces decode them with an UnmarshalJSON method. A
+//     property telling alternatives apart is not a field of their structs:
+//     its value is fixed by the type, and written by a MarshalJSON method.
+//   - anything else becomes interface{}, or the Go type of its "type".
+//
+// Structs, enums and unions are named types. Their names are taken from
+// "title", or else from the key of the definition or name of the file a "$ref"
+// refers to, or else from the names of the type and property containing them.
+// The "description" of a schema becomes the doc comment of its type or field.
+//
+// Generated types do not validate instances; use GenerateGo, or a Validator,
+// to do that.
+func GenerateGoTypes(w io.Writer, config GenerateTypesConfig) error {
+	g := typegen{
+		v:       config.Validator,
+		uris:    schemaURIs(config.Validator.registry),
+		names:   map[int]string{},
+		used:    map[string]bool{},
+		unions:  map[int][]string{},
+		tags:    map[int]map[string]string{},
+		imports: map[string]bool{},
+	}
+
+	for _, t := range config.Types {
+		index, ok := g.v.registry.schemas[t.URI]
+		if !ok {
+			return ErrNoSuchSchema
+		}
+
+		index = g.resolve(index)
+		if t.Name != "" {
+			if g.used[t.Name] {
+				return fmt.Errorf("type %s is generated twice", t.Name)
+			}
+
+			if name, ok := g.names[index]; ok {
+				return fmt.Errorf("type %s would duplicate type %s", t.Name, name)
+			}
+
+			g.used[t.Name] = true
+			g.names[index] = t.Name
+			g.pending = append(g.pending, index)
+			continue
+		}
+
+		if g.kind(index) == goKindStruct || g.kind(index) == goKindEnum || g.kind(index) == goKindUnion {
+			g.named(index, "")
+			continue
+		}
+
+		return fmt.Errorf("schema %s is not an object, enum or union, and so needs a type name", t.URI.String())
+	}
+
+	for len(g.pending) > 0 {
+		index := g.pending[0]
+		g.pending = g.pending[1:]
+
+		g.genType(index)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by jsonschema. DO NOT EDIT.\n\npackage %s\n\n", config.Package)
+
+	if len(g.imports) > 0 {
+		imports := []string{}
+		for path := range g.imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+
+		out.WriteString("import (\n")
+		for _, path := range imports {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n\n")
+	}
+
+	out.Write(g.types.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+// typegen holds the state of GenerateGoTypes.
+type typegen struct {
+	v    *Validator
+	uris map[int]url.URL
+
+	// types holds the generated declarations
+	types bytes.Buffer
+
+	// names holds the name of the type of each schema which has a named type
+	names map[int]string
+
+	// used holds every name taken by a type
+	used map[string]bool
+
+	// pending holds schemas which are named, but whose types have not been
+	// generated
+	pending []int
+
+	// unions holds the names of the unions each struct is an alternative of
+	unions map[int][]string
+
+	// tags holds, for each struct which is an alternative of a union told apart
+	// by a property, the value of that property
+	tags map[int]map[string]string
+
+	imports map[string]bool
+}
+
+type goKind int
+
+const (
+	goKindAny goKind = iota
+	goKindBool
+	goKindInt
+	goKindNumber
+	goKindString
+	goKindEnum
+	goKindSlice
+	goKindMap
+	goKindStruct
+	goKindUnion
+)
+
+// goType is the Go type of a schema.
+type goType struct {
+	kind goKind
+
+	// name is the name of named types
+	name string
+
+	// elem is the type of the elements of slices and maps
+	elem *goType
+}
+
+func (t goType) String() string {
+	switch t.kind {
+	case goKindBool:
+		return "bool"
+	case goKindInt:
+		return "int64"
+	case goKindNumber:
+		return "float64"
+	case goKindString:
+		return "string"
+	case goKindEnum, goKindStruct, goKindUnion:
+		return t.name
+	case goKindSlice:
+		return "[]" + t.elem.String()
+	case goKindMap:
+		return "map[string]" + t.elem.String()
+	}
+
+	return "interface{}"
+}
+
+// nilable determines whether the zero value of the type is nil.
+func (t goType) nilable() bool {
+	return t.kind == goKindAny || t.kind == goKindSlice || t.kind == goKindMap || t.kind == goKindUnion
+}
+
+// hasUnion determines whether decoding the type involves a union.
+func (t goType) hasUnion() bool {
+	return t.kind == goKindUnion || (t.elem != nil && t.elem.hasUnion())
+}
+
+// resolve follows references which make up the whole of a schema.
+func (g *typegen) resolve(index int) int {
+	for i := 0; i < len(g.v.registry.arena.schemas); i++ {
+		s := g.v.registry.GetIndex(index)
+		if !s.Ref.IsSet || !isRefOnly(s) {
+			break
+		}
+
+		index = s.Ref.Schema
+	}
+
+	return index
+}
+
+// raw returns the schema at index as it was given to the Validator.
+func (g *typegen) raw(index int) map[string]interface{} {
+	return g.v.rawSchema(g.uris[index])
+}
+
+func (g *typegen) rawString(index int, keyword string) string {
+	str, _ := g.raw(index)[keyword].(string)
+	return str
+}
+
+// schemaTypes returns the types a schema allows besides null, and whether it allows
+// null.
+func schemaTypes(s *schema) (types []jsonType, nullable bool) {
+	for _, t := range s.Type.Types {
+		if t == jsonTypeNull {
+			nullable = true
+		} else {
+			types = append(types, t)
+		}
+	}
+
+	// every integer is a number
+	if len(types) == 2 && s.Type.contains(jsonTypeInteger) && s.Type.contains(jsonTypeNumber) {
+		types = []jsonType{jsonTypeNumber}
+	}
+
+	return types, nullable
+}
+
+func (g *typegen) kind(index int) goKind {
+	s := g.v.registry.GetIndex(index)
+	if s.Bool.IsSet {
+		return goKindAny
+	}
+
+	types, _ := schemaTypes(s)
+	if s.Type.IsSet && len(types) != 1 {
+		return goKindAny
+	}
+
+	typ := jsonType(0)
+	if len(types) == 1 {
+		typ = types[0]
+	}
+
+	switch typ {
+	case jsonTypeBoolean:
+		return goKindBool
+	case jsonTypeInteger:
+		return goKindInt
+	case jsonTypeNumber:
+		return goKindNumber
+	case jsonTypeString:
+		if isStringEnum(s) {
+			return goKindEnum
+		}
+
+		return goKindString
+	case jsonTypeArray:
+		return goKindSlice
+	case jsonTypeObject:
+		if kind := g.objectKind(s); kind != goKindAny {
+			return kind
+		}
+
+		return goKindMap
+	}
+
+	// without a type, guess from the keywords present
+	switch {
+	case isStringEnum(s):
+		return goKindEnum
+	case g.objectKind(s) != goKindAny:
+		return g.objectKind(s)
+	case s.Items.IsSet:
+		return goKindSlice
+	case s.AdditionalProperties.IsSet:
+		return goKindMap
+	}
+
+	if _, ok := constString(s); ok {
+		return goKindString
+	}
+
+	return goKindAny
+}
+
+// objectKind returns the kind of a schema which describes objects, or goKindAny
+// if it is neither a struct nor a union.
+func (g *typegen) objectKind(s *schema) goKind {
+	if s.Properties.IsSet {
+		return goKindStruct
+	}
+
+	if s.OneOf.IsSet {
+		for _, alternative := range s.OneOf.Schemas {
+			if g.kind(g.resolve(alternative)) != goKindStruct {
+				return goKindAny
+			}
+		}
+
+		return goKindUnion
+	}
+
+	if s.AllOf.IsSet {
+		for _, member := range s.AllOf.Schemas {
+			if g.kind(g.resolve(member)) != goKindStruct {
+				return goKindAny
+			}
+		}
+
+		return goKindStruct
+	}
+
+	return goKindAny
+}
+
+// isStringEnum determines whether a schema allows a choice of strings. Schemas
+// allowing a single string are plain strings.
+func isStringEnum(s *schema) bool {
+	if !s.Enum.IsSet || len(s.Enum.Values) < 2 {
+		return false
+	}
+
+	for _, value := range s.Enum.Values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// goType returns the type of the schema at index. hint is the name to give
+// the type if it is named, and has no name of its own.
+func (g *typegen) goType(index int, hint string) goType {
+	resolved := g.resolve(index)
+	if resolved != index {
+		// a referred-to schema is named after the reference, not its user
+		hint = ""
+	}
+
+	s := g.v.registry.GetIndex(resolved)
+
+	t := goType{kind: g.kind(resolved)}
+	switch t.kind {
+	case goKindEnum, goKindStruct, goKindUnion:
+		t.name = g.named(resolved, hint)
+	case goKindSlice:
+		elem := goType{kind: goKindAny}
+		if s.Items.IsSet && s.Items.IsSingle {
+			elem = g.goType(s.Items.Schemas[0], hint+"Item")
+		}
+
+		t.elem = &elem
+	case goKindMap:
+		elem := goType{kind: goKindAny}
+		if s.AdditionalProperties.IsSet {
+			elem = g.goType(s.AdditionalProperties.Schema, hint+"Value")
+		}
+
+		t.elem = &elem
+	}
+
+	return t
+}
+
+// named returns the name of the type of a schema, choosing one and scheduling
+// its declaration if it has none yet.
+func (g *typegen) named(index int, hint string) string {
+	if name, ok := g.names[index]; ok {
+		return name
+	}
+
+	base := exportName(g.rawString(index, "title"))
+	if base == "" {
+		uri := g.uris[index]
+		ptr, _ := jsonpointer.New(uri.Fragment)
+
+		if len(ptr.Tokens) == 0 {
+			base = exportName(strings.TrimSuffix(path.Base(uri.Path), path.Ext(uri.Path)))
+		} else if hint == "" || (len(ptr.Tokens) >= 2 && ptr.Tokens[len(ptr.Tokens)-2] == "definitions") {
+			base = exportName(ptr.Tokens[len(ptr.Tokens)-1])
+		}
+	}
+
+	if base == "" {
+		base = hint
+	}
+
+	if base == "" {
+		base = "Type"
+	}
+
+	name := base
+	for i := 2; g.used[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	g.used[name] = true
+	g.names[index] = name
+	g.pending = append(g.pending, index)
+
+	return name
+}
+
+// exportName converts a string into an exported Go identifier, or the empty
+// string if it has no letters or digits.
+func exportName(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var name strings.Builder
+	for _, part := range parts {
+		switch lower := strings.ToLower(part); lower {
+		case "id", "url", "uri", "api", "http", "json", "uuid":
+			name.WriteString(strings.ToUpper(lower))
+		default:
+			runes := []rune(part)
+			name.WriteRune(unicode.ToUpper(runes[0]))
+			name.WriteString(string(runes[1:]))
+		}
+	}
+
+	if name.Len() > 0 && unicode.IsDigit([]rune(name.String())[0]) {
+		return "N" + name.String()
+	}
+
+	return name.String()
+}
+
+// writeDoc writes a doc comment for a declaration of name.
+func (g *typegen) writeDoc(index int, indent, name, summary string) {
+	uri := g.uris[index]
+	location := uri.String()
+	if location == "" {
+		location = "#"
+	}
+
+	fmt.Fprintf(&g.types, "%s// %s %s, generated from %s.\n", indent, name, summary, location)
+	if description := g.rawString(index, "description"); description != "" {
+		fmt.Fprintf(&g.types, "%s//\n", indent)
+		for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+			fmt.Fprintf(&g.types, "%s// %s\n", indent, strings.TrimSpace(line))
+		}
+	}
+}
+
+func (g *typegen) genType(index int) {
+	name := g.names[index]
+
+	switch g.kind(index) {
+	case goKindStruct:
+		g.genStruct(index, name)
+	case goKindEnum:
+		g.genEnum(index, name)
+	case goKindUnion:
+		g.genUnion(index, name)
+	default:
+		t := g.goType(index, name)
+		g.writeDoc(index, "", name, "is a "+t.String())
+		fmt.Fprintf(&g.types, "type %s %s\n\n", name, t.String())
+	}
+}
+
+// structField is a property of a struct.
+type structField struct {
+	key      string
+	index    int
+	required bool
+}
+
+// fields returns the properties of a struct schema, including those of the
+// object schemas in its "allOf", in source order if known.
+func (g *typegen) fields(index int, visited map[int]bool) []structField {
+	if visited[index] {
+		return nil
+	}
+
+	visited[index] = true
+	s := g.v.registry.GetIndex(index)
+
+	keys := []string{}
+	for key := range s.Properties.Schemas {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	uri := g.uris[index]
+	ptr, _ := jsonpointer.New(uri.Fragment)
+	uri.Fragment = ""
+	if sourceKeys, ok := g.v.sources[uri].Keys(jsonpointer.Ptr{Tokens: append(ptr.Tokens, "properties")}); ok {
+		keys = sourceKeys
+	}
+
+	fields := []structField{}
+	for _, key := range keys {
+		fields = append(fields, structField{
+			key:      key,
+			index:    s.Properties.Schemas[key],
+			required: containsString(s.Required.Properties, key),
+		})
+	}
+
+	for _, member := range s.AllOf.Schemas {
+		for _, field := range g.fields(g.resolve(member), visited) {
+			field.required = field.required || containsString(s.Required.Properties, field.key)
+			fields = append(fields, field)
+		}
+	}
+
+	// properties appearing more than once are described by their first
+	// appearance, but required if any appearance requires them
+	deduped := []structField{}
+	positions := map[string]int{}
+	for _, field := range fields {
+		if i, ok := positions[field.key]; ok {
+			deduped[i].required = deduped[i].required || field.required
+			continue
+		}
+
+		positions[field.key] = len(deduped)
+		deduped = append(deduped, field)
+	}
+
+	return deduped
+}
+
+// isValidTagKey determines whether encoding/json accepts a property name as
+// the key of a field tag.
+func isValidTagKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, r := range key {
+		if strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r) {
+			continue
+		}
+
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g *typegen) genStruct(index int, name string) {
+	type field struct {
+		name string
+		key  string
+		typ  goType
+		ptr  bool
+		omit bool
+		doc  int
+	}
+
+	fields := []field{}
+	fieldNames := map[string]bool{}
+	for _, f := range g.fields(index, map[int]bool{}) {
+		if _, ok := g.tags[index][f.key]; ok || !isValidTagKey(f.key) {
+			continue
+		}
+
+		base := exportName(f.key)
+		if base == "" {
+			base = "Field"
+		}
+
+		fieldName := base
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = base + strconv.Itoa(i)
+		}
+
+		fieldNames[fieldName] = true
+
+		_, nullable := schemaTypes(g.v.registry.GetIndex(g.resolve(f.index)))
+		typ := g.goType(f.index, name+fieldName)
+
+		fields = append(fields, field{
+			name: fieldName,
+			key:  f.key,
+			typ:  typ,
+			ptr:  (nullable || !f.required) && !typ.nilable(),
+			omit: !f.required,
+			doc:  f.index,
+		})
+	}
+
+	g.writeDoc(index, "", name, "is an object")
+	fmt.Fprintf(&g.types, "type %s struct {\n", name)
+	for i, f := range fields {
+		if description := g.rawString(f.doc, "description"); description != "" {
+			if i > 0 {
+				g.types.WriteString("\n")
+			}
+
+			for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+				fmt.Fprintf(&g.types, "\t// %s\n", strings.TrimSpace(line))
+			}
+		}
+
+		typ := f.typ.String()
+		if f.ptr {
+			typ = "*" + typ
+		}
+
+		tag := f.key
+		if f.omit {
+			tag += ",omitempty"
+		}
+
+		fmt.Fprintf(&g.types, "\t%s %s `json:%q`\n", f.name, typ, tag)
+	}
+	g.types.WriteString("}\n\n")
+
+	for _, union := range g.unions[index] {
+		fmt.Fprintf(&g.types, "func (%s) is%s() {}\n\n", name, union)
+	}
+
+	// properties telling alternatives apart are not fields, as their values
+	// are fixed by the type, and so are written by hand
+	if len(g.tags[index]) > 0 {
+		g.genMarshalTags(index, name)
+	}
+
+	// fields holding unions are decoded by hand, as encoding/json cannot decode
+	// into interfaces with methods
+	unionFields := []field{}
+	for _, f := range fields {
+		if f.typ.hasUnion() {
+			unionFields = append(unionFields, f)
+		}
+	}
+
+	if len(unionFields) == 0 {
+		return
+	}
+
+	g.imports["encoding/json"] = true
+
+	g.types.WriteString("// UnmarshalJSON implements json.Unmarshaler.\n")
+	fmt.Fprintf(&g.types, "func (v *%s) UnmarshalJSON(data []byte) error {\n", name)
+	fmt.Fprintf(&g.types, "type plain %s\n", name)
+	g.types.WriteString("var raw struct {\n*plain\n")
+	for _, f := range unionFields {
+		fmt.Fprintf(&g.types, "%s %s `json:%q`\n", f.name, rawGoType(f.typ), f.key)
+	}
+	g.types.WriteString("}\n\n")
+	g.types.WriteString("raw.plain = (*plain)(v)\n")
+	g.types.WriteString("if err := json.Unmarshal(data, &raw); err != nil {\nreturn err\n}\n\n")
+	for _, f := range unionFields {
+		g.genDecode(f.typ, "raw."+f.name, "v."+f.name, 0)
+		g.types.WriteString("\n")
+	}
+	g.types.WriteString("return nil\n}\n\n")
+}
+
+// genMarshalTags writes a MarshalJSON method which adds the properties telling
+// a struct apart from the other alternatives of its unions.
+func (g *typegen) genMarshalTags(index int, name string) {
+	g.imports["encoding/json"] = true
+
+	keys := []string{}
+	for key := range g.tags[index] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := []string{}
+	for _, key := range keys {
+		tags = append(tags, fmt.Sprintf("%q to %q", key, g.tags[index][key]))
+	}
+
+	fmt.Fprintf(&g.types, "// MarshalJSON implements json.Marshaler, setting %s.\n", strings.Join(tags, " and "))
+	fmt.Fprintf(&g.types, "func (v %s) MarshalJSON() ([]byte, error) {\n", name)
+	fmt.Fprintf(&g.types, "type plain %s\n", name)
+	g.types.WriteString("return json.Marshal(struct {\n")
+	for i, key := range keys {
+		fmt.Fprintf(&g.types, "Tag%d string `json:%q`\n", i, key)
+	}
+	g.types.WriteString("plain\n}{\n")
+	for i, key := range keys {
+		fmt.Fprintf(&g.types, "Tag%d: %q,\n", i, g.tags[index][key])
+	}
+	g.types.WriteString("plain: plain(v),\n})\n}\n\n")
+}
+
+// rawGoType returns the type to decode a value of type t into, before its
+// unions are decoded.
+func rawGoType(t goType) string {
+	switch t.kind {
+	case goKindUnion:
+		return "json.RawMessage"
+	case goKindSlice:
+		return "[]" + rawGoType(*t.elem)
+	case goKindMap:
+		return "map[string]" + rawGoType(*t.elem)
+	}
+
+	return t.String()
+}
+
+// genDecode writes statements decoding src, of the type returned by
+// rawGoType, into dst, of type t.
+func (g *typegen) genDecode(t goType, src, dst string, depth int) {
+	switch t.kind {
+	case goKindUnion:
+		fmt.Fprintf(&g.types, "if len(%s) > 0 && string(%s) != \"null\" {\n", src, src)
+		fmt.Fprintf(&g.types, "decoded, err := Unmarshal%s(%s)\nif err != nil {\nreturn err\n}\n\n%s = decoded\n}\n", t.name, src, dst)
+	case goKindSlice:
+		i, elem := fmt.Sprintf("i%d", depth), fmt.Sprintf("elem%d", depth)
+		fmt.Fprintf(&g.types, "if %s != nil {\n%s = make(%s, len(%s))\n", src, dst, t.String(), src)
+		fmt.Fprintf(&g.types, "for %s, %s := range %s {\n", i, elem, src)
+		g.genDecode(*t.elem, elem, fmt.Sprintf("%s[%s]", dst, i), depth+1)
+		g.types.WriteString("}\n}\n")
+	case goKindMap:
+		key, elem, value := fmt.Sprintf("key%d", depth), fmt.Sprintf("elem%d", depth), fmt.Sprintf("value%d", depth)
+		fmt.Fprintf(&g.types, "if %s != nil {\n%s = make(%s, len(%s))\n", src, dst, t.String(), src)
+		fmt.Fprintf(&g.types, "for %s, %s := range %s {\n", key, elem, src)
+		fmt.Fprintf(&g.types, "var %s %s\n", value, t.elem.String())
+		g.genDecode(*t.elem, elem, value, depth+1)
+		fmt.Fprintf(&g.types, "\n%s[%s] = %s\n}\n}\n", dst, key, value)
+	}
+}
+
+func (g *typegen) genEnum(index int, name string) {
+	s := g.v.registry.GetIndex(index)
+
+	g.writeDoc(index, "", name, "is a string")
+	fmt.Fprintf(&g.types, "type %s string\n\n", name)
+	fmt.Fprintf(&g.types, "// Values of %s.\nconst (\n", name)
+
+	constNames := map[string]bool{}
+	for i, value := range s.Enum.Values {
+		base := name + exportName(value.(string))
+		if base == name {
+			base = name + strconv.Itoa(i)
+		}
+
+		constName := base
+		for j := 2; constNames[constName] || g.used[constName]; j++ {
+			constName = base + strconv.Itoa(j)
+		}
+
+		constNames[constName] = true
+		g.used[constName] = true
+		fmt.Fprintf(&g.types, "%s %s = %q\n", constName, name, value.(string))
+	}
+	g.types.WriteString(")\n\n")
+}
+
+func (g *typegen) genUnion(index int, name string) {
+	s := g.v.registry.GetIndex(index)
+
+	alternatives := []int{}
+	names := []string{}
+	for _, alternative := range s.OneOf.Schemas {
+		alternative = g.resolve(alternative)
+		alternatives = append(alternatives, alternative)
+		names = append(names, g.goType(alternative, name+"Option"+strconv.Itoa(len(names))).name)
+		g.unions[alternative] = append(g.unions[alternative], name)
+	}
+
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+
+	g.writeDoc(index, "", name, "is one of "+joinOr(names))
+	fmt.Fprintf(&g.types, "type %s interface {\nis%s()\n}\n\n", name, name)
+
+	fmt.Fprintf(&g.types, "// Unmarshal%s decodes JSON data holding one of the types of %s.\n", name, name)
+	fmt.Fprintf(&g.types, "func Unmarshal%s(data []byte) (%s, error) {\n", name, name)
+
+	if key, values, ok := g.discriminator(alternatives); ok {
+		for i, alternative := range alternatives {
+			if g.tags[alternative] == nil {
+				g.tags[alternative] = map[string]string{}
+			}
+
+			g.tags[alternative][key] = values[i]
+		}
+
+		fmt.Fprintf(&g.types, "var probe struct {\nValue string `json:%q`\n}\n\n", key)
+		g.types.WriteString("if err := json.Unmarshal(data, &probe); err != nil {\nreturn nil, err\n}\n\n")
+		g.types.WriteString("switch probe.Value {\n")
+		for i, value := range values {
+			fmt.Fprintf(&g.types, "case %q:\nvar value %s\nerr := json.Unmarshal(data, &value)\nreturn value, err\n", value, names[i])
+		}
+		g.types.WriteString("}\n\n")
+		fmt.Fprintf(&g.types, "return nil, fmt.Errorf(\"unknown %s %%q of %s\", probe.Value)\n}\n\n", key, name)
+		return
+	}
+
+	g.imports["bytes"] = true
+	for _, alternative := range names {
+		fmt.Fprintf(&g.types, "{\nvar value %s\ndecoder := json.NewDecoder(bytes.NewReader(data))\ndecoder.DisallowUnknownFields()\n", alternative)
+		g.types.WriteString("if err := decoder.Decode(&value); err == nil {\nreturn value, nil\n}\n}\n\n")
+	}
+	fmt.Fprintf(&g.types, "return nil, fmt.Errorf(\"value is none of the types of %s\")\n}\n\n", name)
+}
+
+// joinOr joins names into a list such as "A, B or C".
+func joinOr(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// discriminator finds a property which every alternative of a union has, with
+// a different constant string value in each.
+func (g *typegen) discriminator(alternatives []int) (key string, values []string, ok bool) {
+	candidates := map[string]bool{}
+	for i, alternative := range alternatives {
+		present := map[string]bool{}
+		for _, field := range g.fields(alternative, map[int]bool{}) {
+			if _, ok := constString(g.v.registry.GetIndex(g.resolve(field.index))); ok {
+				present[field.key] = true
+			}
+		}
+
+		if i == 0 {
+			candidates = present
+			continue
+		}
+
+		for key := range candidates {
+			candidates[key] = present[key]
+		}
+	}
+
+	for _, key := range sortedSet(candidates) {
+		if !candidates[key] {
+			continue
+		}
+
+		values := []string{}
+		seen := map[string]bool{}
+		for _, alternative := range alternatives {
+			for _, field := range g.fields(alternative, map[int]bool{}) {
+				if field.key == key {
+					value, _ := constString(g.v.registry.GetIndex(g.resolve(field.index)))
+					values = append(values, value)
+					seen[value] = true
+					break
+				}
+			}
+		}
+
+		if len(seen) == len(alternatives) {
+			return key, values, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// constString returns the single string a schema allows, through "const" or a
+// one-value "enum".
+func constString(s *schema) (string, bool) {
+	if s.Const.IsSet {
+		str, ok := s.Const.Value.(string)
+		return str, ok
+	}
+
+	if s.Enum.IsSet && len(s.Enum.Values) == 1 {
+		str, ok := s.Enum.Values[0].(string)
+		return str, ok
+	}
+
+	return "", false
+}
@@ -0,0 +1,135 @@
+package jsonschema
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateGoTypes(t *testing.T) {
+	validator, err := NewValidator([]interface{}{
+		mustDecode(t, `{
+			"$id": "http://example.com/shape.json",
+			"title": "shape list",
+			"type": "object",
+			"required": ["shapes"],
+			"properties": {
+				"shapes": {"type": "array", "items": {"$ref": "#/definitions/shape"}},
+				"unit": {"enum": ["cm", "in"], "description": "Unit of\nlengths."},
+				"origin": {"$ref": "point.json"}
+			},
+			"definitions": {
+				"shape": {"oneOf": [{"$ref": "#/definitions/circle"}, {"$ref": "#/definitions/square"}]},
+				"circle": {"properties": {"radius": {"type": "number"}}},
+				"square": {"properties": {"side": {"type": "number"}, "tags": {"additionalProperties": {"type": "string"}}}}
+			}
+		}`),
+		mustDecode(t, `{
+			"$id": "http://example.com/point.json",
+			"properties": {"x": {"type": ["integer", "null"]}, "y": {"type": "integer"}},
+			"required": ["x", "y"]
+		}`),
+	})
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	err = GenerateGoTypes(&out, GenerateTypesConfig{
+		Package:   "shapes",
+		Validator: &validator,
+		Types:     []GenerateType{{URI: url.URL{Scheme: "http", Host: "example.com", Path: "/shape.json"}}},
+	})
+	assert.NoError(t, err)
+
+	src := out.String()
+	for _, decl := range []string{
+		"package shapes\n",
+		"// ShapeList is an object, generated from http://example.com/shape.json.\ntype ShapeList struct {\n" +
+			"\tOrigin *Point  `json:\"origin,omitempty\"`\n" +
+			"\tShapes []Shape `json:\"shapes\"`\n\n" +
+			"\t// Unit of\n\t// lengths.\n" +
+			"\tUnit *ShapeListUnit `json:\"unit,omitempty\"`\n}\n",
+		"func (v *ShapeList) UnmarshalJSON(data []byte) error {\n",
+		"\t\t\t\tdecoded, err := UnmarshalShape(elem0)\n",
+		"type Point struct {\n\tX *int64 `json:\"x\"`\n\tY int64  `json:\"y\"`\n}\n",
+		"// Shape is one of Circle or Square, generated from http://example.com/shape.json#/definitions/shape.\n",
+		"type Shape interface {\n\tisShape()\n}\n",
+		"func UnmarshalShape(data []byte) (Shape, error) {\n",
+		"type ShapeListUnit string\n",
+		"\tShapeListUnitCm ShapeListUnit = \"cm\"\n\tShapeListUnitIn ShapeListUnit = \"in\"\n",
+		"type Circle struct {\n\tRadius *float64 `json:\"radius,omitempty\"`\n}\n\nfunc (Circle) isShape() {}\n",
+		"\tTags map[string]string `json:\"tags,omitempty\"`\n",
+		"func (Square) isShape() {}\n",
+	} {
+		assert.True(t, strings.Contains(src, decl), "missing %q in:\n%s", decl, src)
+	}
+}
+
+func TestGenerateGoTypesDiscriminator(t *testing.T) {
+	validator, err := NewValidator([]interface{}{mustDecode(t, `{
+		"oneOf": [
+			{"title": "cat", "properties": {"kind": {"const": "cat"}, "lives": {"type": "integer"}}},
+			{"title": "dog", "properties": {"kind": {"enum": ["dog"]}}}
+		]
+	}`)})
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	err = GenerateGoTypes(&out, GenerateTypesConfig{
+		Package:   "pets",
+		Validator: &validator,
+		Types:     []GenerateType{{Name: "Pet"}},
+	})
+	assert.NoError(t, err)
+
+	src := out.String()
+	for _, decl := range []string{
+		"type Cat struct {\n\tLives *int64 `json:\"lives,omitempty\"`\n}\n",
+		"type Dog struct {\n}\n",
+		"// MarshalJSON implements json.Marshaler, setting \"kind\" to \"cat\".\n" +
+			"func (v Cat) MarshalJSON() ([]byte, error) {\n" +
+			"\ttype plain Cat\n" +
+			"\treturn json.Marshal(struct {\n" +
+			"\t\tTag0 string `json:\"kind\"`\n" +
+			"\t\tplain\n" +
+			"\t}{\n" +
+			"\t\tTag0:  \"cat\",\n" +
+			"\t\tplain: plain(v),\n" +
+			"\t})\n}\n",
+		"\t\tValue string `json:\"kind\"`\n",
+		"\tcase \"cat\":\n\t\tvar value Cat\n",
+		"\tcase \"dog\":\n\t\tvar value Dog\n",
+		"return nil, fmt.Errorf(\"unknown kind %q of Pet\", probe.Value)",
+	} {
+		assert.True(t, strings.Contains(src, decl), "missing %q in:\n%s", decl, src)
+	}
+}
+
+func TestGenerateGoTypesErrors(t *testing.T) {
+	validator, err := NewValidator([]interface{}{mustDecode(t, `{"type": "string"}`)})
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	err = GenerateGoTypes(&out, GenerateTypesConfig{
+		Package:   "x",
+		Validator: &validator,
+		Types:     []GenerateType{{URI: url.URL{Scheme: "http", Host: "example.com"}}},
+	})
+	assert.Equal(t, ErrNoSuchSchema, err)
+
+	err = GenerateGoTypes(&out, GenerateTypesConfig{
+		Package:   "x",
+		Validator: &validator,
+		Types:     []GenerateType{{}},
+	})
+	assert.EqualError(t, err, "schema  is not an object, enum or union, and so needs a type name")
+
+	err = GenerateGoTypes(&out, GenerateTypesConfig{
+		Package:   "x",
+		Validator: &validator,
+		Types:     []GenerateType{{Name: "A"}, {Name: "B"}},
+	})
+	assert.EqualError(t, err, "type B would duplicate type A")
+}
@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"net/url"
+	"sync"
 
 	"github.com/ucarion/json-pointer"
 )
@@ -12,9 +13,39 @@ const DefaultMaxStackDepth = 128
 
 // Validator compiles schemas and evaluates instances.
 type Validator struct {
-	registry      registry
-	maxStackDepth int
-	maxErrors     int
+	registry             registry
+	maxStackDepth        int
+	maxErrors            int
+	maxErrorsPerInstance int
+	maxErrorsPerKeyword  int
+	validateSchemas      bool
+	strictKeywords       bool
+	keywords             map[string]KeywordCompiler
+	trace                bool
+
+	// coverage holds the counts collected if the Coverage option is enabled.
+	coverage *coverage
+
+	// sources holds, for schemas compiled from JSON documents, where each part
+	// of the schema appears in its document. It is keyed by the fragment-less
+	// URI of each schema.
+	sources map[url.URL]*SourceMap
+
+	// rawSchemas holds the schemas given to the Validator, before they were
+	// parsed, keyed by their fragment-less URI.
+	rawSchemas map[url.URL]interface{}
+
+	// entries holds the URIs of subschemas to compile even if nothing refers to
+	// them, so that instances can be validated against them.
+	entries []url.URL
+
+	// discriminators indicates whether "oneOf" obeys the "discriminator"
+	// keyword, as it does in OpenAPI documents.
+	discriminators bool
+
+	// vms holds virtual machines which can be reused between calls to
+	// ValidateURI, so that evaluation does not need to allocate stacks afresh.
+	vms *sync.Pool
 }
 
 // ValidatorConfig contains configuration for a Validator.
@@ -26,15 +57,92 @@ type ValidatorConfig struct {
 	// MaxErrors is the maximum number of errors to return before the Validator
 	// quits early.
 	//
+	// If MaxErrors is one, cheap keywords, such as "type", are evaluated before
+	// those which evaluate subschemas, so that invalid instances are rejected
+	// sooner. The error reported may then differ from the first of the errors
+	// reported with any other value of MaxErrors.
+	//
 	// A value of zero indicates to produce all errors.
 	MaxErrors int
+
+	// MaxErrorsPerInstanceLocation is the maximum number of errors to return for
+	// any one part of the instance, as identified by InstancePath. Further errors
+	// for that part of the instance are suppressed, but evaluation continues.
+	//
+	// A value of zero indicates no limit.
+	MaxErrorsPerInstanceLocation int
+
+	// MaxErrorsPerKeyword is the maximum number of errors to return from any one
+	// keyword of a schema, as identified by URI and SchemaPath. Further errors
+	// from that keyword are suppressed, but evaluation continues. This keeps a
+	// single keyword applied to every element of a large array from crowding out
+	// other errors.
+	//
+	// A value of zero indicates no limit.
+	MaxErrorsPerKeyword int
+
+	// ValidateSchemas indicates whether each schema should be validated against
+	// the meta-schema of its dialect before being compiled. The dialect is taken
+	// from the "$schema" keyword, and defaults to draft-07.
+	//
+	// If any schema is invalid, an instance of ErrSchemaErrors is returned,
+	// listing the location of each problem. Values encoding/json would not
+	// produce, such as Go ints, are reported as problems.
+	ValidateSchemas bool
+
+	// StrictKeywords indicates whether schemas using keywords unknown to the
+	// Validator should be rejected. This catches misspelled keywords, such as
+	// "minLenght", which would otherwise be silently ignored.
+	//
+	// Unknown keywords are reported in the same way as with ValidateSchemas.
+	StrictKeywords bool
+
+	// Keywords holds custom keywords, keyed by name. Custom keywords are
+	// evaluated after all of the standard keywords in the same schema, in the
+	// order in which they appear in its source if known, and otherwise in sorted
+	// order. See KeywordCompiler for details.
+	//
+	// Custom keywords are considered known for the purposes of StrictKeywords.
+	Keywords map[string]KeywordCompiler
+
+	// Trace indicates whether to record every step of evaluation. If enabled, the
+	// Trace field of each ValidationResult will be populated.
+	//
+	// Tracing is meant for debugging schemas, and makes validation considerably
+	// slower.
+	Trace bool
+
+	// Coverage indicates whether to count how often each schema and keyword is
+	// evaluated, accepts, and rejects an instance, across every call to Validate
+	// and its variants. See Validator.Coverage.
+	//
+	// IsValid and its variants evaluate only as much as they need to, and are
+	// not counted.
+	Coverage bool
 }
 
 // ValidationResult contains information on whether an instance successfully
 // validated, as well as any relevant validation errors.
 type ValidationResult struct {
-	Errors     []ValidationError
+	// Errors are reported in a deterministic order: keywords are evaluated in a
+	// fixed order, and the properties of an object are evaluated in sorted
+	// order of their names.
+	Errors []ValidationError
+
+	// Overflowed indicates that Errors is not a complete list of errors, either
+	// because evaluation found more than MaxErrors errors and quit, or because
+	// some errors were suppressed. See Suppressed. If there are exactly
+	// MaxErrors errors, Overflowed is false.
 	Overflowed bool
+
+	// Suppressed is the number of errors left out of Errors because of the
+	// MaxErrorsPerInstanceLocation and MaxErrorsPerKeyword options.
+	Suppressed int
+
+	// Trace is the root of a tree describing every keyword evaluated, and whether
+	// it accepted the instance. It is only populated if Trace was enabled in the
+	// ValidatorConfig.
+	Trace *TraceNode
 }
 
 // IsValid checks whether the result of schema validation found the instance to
@@ -57,6 +165,26 @@ type ValidationError struct {
 
 	// The URI of the schema which rejected part of the instance.
 	URI url.URL
+
+	// Causes holds the errors which led to this error, for errors produced by
+	// the "anyOf", "oneOf" and "contains" keywords. For "anyOf" and "oneOf", these
+	// are the errors produced by each subschema. For "contains", these are the
+	// errors produced by each element of the array.
+	//
+	// Errors produced by "not" never have causes, as "not" fails precisely when
+	// its subschema produces no errors.
+	Causes []ValidationError
+
+	// Matches holds, for errors produced by "oneOf" because more than one of its
+	// subschemas accepted the instance, the indices of those subschemas.
+	Matches []int
+
+	// InstancePosition is where the part of the instance identified by
+	// InstancePath appears in its source. It is only known for instances given
+	// to ValidateJSON, ValidateYAML, ValidateTOML and their URI variants. If
+	// InstancePath points to a value which does not exist, such as a missing
+	// property, the position of its nearest existing parent is used.
+	InstancePosition Position
 }
 
 // NewValidator constructs a new Validator that will use the given schemas.
@@ -83,23 +211,108 @@ func NewValidator(schemas []interface{}) (Validator, error) {
 // See NewValidator for how schemas will be used. See ValidatorConfig for
 // configuration options.
 func NewValidatorWithConfig(schemas []interface{}, config ValidatorConfig) (Validator, error) {
-	v := Validator{
-		maxStackDepth: config.MaxStackDepth,
-		maxErrors:     config.MaxErrors,
+	v := newValidator(config)
+
+	err := v.seal(schemas, make([]*SourceMap, len(schemas)))
+	return v, err
+}
+
+func newValidator(config ValidatorConfig) Validator {
+	return Validator{
+		maxStackDepth:        config.MaxStackDepth,
+		maxErrors:            config.MaxErrors,
+		maxErrorsPerInstance: config.MaxErrorsPerInstanceLocation,
+		maxErrorsPerKeyword:  config.MaxErrorsPerKeyword,
+		validateSchemas:      config.ValidateSchemas,
+		strictKeywords:       config.StrictKeywords,
+		keywords:             config.Keywords,
+		trace:                config.Trace,
+		coverage:             newCoverage(config.Coverage),
+	}
+}
+
+// Source is a schema in its encoded form, such as the contents of a file.
+type Source struct {
+	// The name of the source, typically a file name. It is used only in
+	// positions.
+	Name string
+
+	Data []byte
+}
+
+// NewValidatorFromJSON constructs a new Validator from schemas encoded as JSON.
+//
+// It is equivalent to decoding each source and passing the results to
+// NewValidatorWithConfig, except that the Validator remembers where each part
+// of a schema appears in its source. See SchemaPosition. Any SchemaError
+// returned has its Position set, and errors from ValidateSchemas and
+// StrictKeywords are listed in the order in which they appear in each source.
+//
+// If a source is not valid JSON, an instance of ErrSyntax is returned.
+func NewValidatorFromJSON(sources []Source, config ValidatorConfig) (Validator, error) {
+	schemas := make([]interface{}, len(sources))
+	sourceMaps := make([]*SourceMap, len(sources))
+
+	for i, source := range sources {
+		schema, sourceMap, err := DecodeJSON(source.Name, source.Data)
+		if err != nil {
+			return Validator{}, err
+		}
+
+		schemas[i] = schema
+		sourceMaps[i] = sourceMap
 	}
 
-	err := v.seal(schemas)
+	v := newValidator(config)
+
+	err := v.seal(schemas, sourceMaps)
 	return v, err
 }
 
-func (v *Validator) seal(schemas []interface{}) error {
+// SchemaPosition returns where the part of a schema identified by uri and path
+// appears in its source. The uri and path are typically the URI and SchemaPath
+// of a ValidationError, or the URI and Path of a SchemaError.
+//
+// Positions are only known for schemas given to NewValidatorFromJSON. If the
+// position is not known, ok is false.
+func (v *Validator) SchemaPosition(uri url.URL, path jsonpointer.Ptr) (pos Position, ok bool) {
+	uri.Fragment = ""
+	return v.sources[uri].Position(path)
+}
+
+// schemaErrorPosition fills in the Position of err, if it is a SchemaError.
+func (v *Validator) schemaErrorPosition(err error) error {
+	if schemaErr, ok := err.(SchemaError); ok {
+		schemaErr.Position, _ = v.SchemaPosition(schemaErr.URI, schemaErr.Path)
+		return schemaErr
+	}
+
+	return err
+}
+
+func (v *Validator) seal(schemas []interface{}, sources []*SourceMap) error {
+	v.sources = map[url.URL]*SourceMap{}
+	for i, schema := range schemas {
+		if sources[i] != nil {
+			id := rawSchemaID(schema)
+			id.Fragment = ""
+			v.sources[id] = sources[i]
+		}
+	}
+
+	if v.validateSchemas || v.strictKeywords {
+		if err := v.checkSchemas(schemas, sources); err != nil {
+			return err
+		}
+	}
+
 	registry := newRegistry(32)
 	rawSchemas := map[url.URL]interface{}{}
 
-	for _, schema := range schemas {
-		parsed, err := parseRootSchema(&registry, schema)
+	for i, schema := range schemas {
+		parsed, err := parseRootSchema(&registry, v.keywords, v.discriminators, sources[i], schema)
 		if err != nil {
-			return err
+			return v.schemaErrorPosition(err)
 		}
 
 		rawSchemas[parsed.ID] = schema
@@ -108,6 +321,12 @@ func (v *Validator) seal(schemas []interface{}) error {
 	missingURIs := registry.PopulateRefs() // uris which must be accounted for
 	undefinedURIs := []url.URL{}           // uris which cannot be accounted for
 
+	for _, uri := range v.entries {
+		if _, ok := registry.Get(uri); !ok {
+			missingURIs = append(missingURIs, uri)
+		}
+	}
+
 	for len(missingURIs) > 0 && len(undefinedURIs) == 0 {
 		for _, uri := range missingURIs {
 			baseURI := uri
@@ -124,9 +343,9 @@ func (v *Validator) seal(schemas []interface{}) error {
 					return err
 				}
 
-				_, err = parseSubSchema(&registry, baseURI, ptr.Tokens, *rawRefSchema)
+				_, err = parseSubSchema(&registry, v.keywords, v.discriminators, v.sources[baseURI], baseURI, ptr.Tokens, *rawRefSchema)
 				if err != nil {
-					return err
+					return v.schemaErrorPosition(err)
 				}
 			} else {
 				undefinedURIs = append(undefinedURIs, baseURI)
@@ -140,10 +359,40 @@ func (v *Validator) seal(schemas []interface{}) error {
 		return ErrMissingURIs{URIs: undefinedURIs}
 	}
 
+	registry.Compile()
 	v.registry = registry
+	v.rawSchemas = rawSchemas
+
+	maxStackDepth, maxErrors := v.maxStackDepth, v.maxErrors
+	maxErrorsPerInstance, maxErrorsPerKeyword := v.maxErrorsPerInstance, v.maxErrorsPerKeyword
+	v.vms = &sync.Pool{
+		New: func() interface{} {
+			vm := newVM(registry, maxStackDepth, maxErrors, maxErrorsPerInstance, maxErrorsPerKeyword)
+			return &vm
+		},
+	}
+
 	return nil
 }
 
+// rawSchema returns the object schema with the given URI as it was given to
+// the Validator, or nil if it is not an object.
+func (v *Validator) rawSchema(uri url.URL) map[string]interface{} {
+	ptr, err := jsonpointer.New(uri.Fragment)
+	if err != nil {
+		return nil
+	}
+
+	uri.Fragment = ""
+	value, err := ptr.Eval(v.rawSchemas[uri])
+	if err != nil {
+		return nil
+	}
+
+	object, _ := (*value).(map[string]interface{})
+	return object
+}
+
 // Validate evaluates the given instance against the default schema of the
 // Validator.
 //
@@ -155,15 +404,135 @@ func (v *Validator) Validate(instance interface{}) (ValidationResult, error) {
 // ValidateURI evaluates the given instance against the schema identified by the
 // given URI.
 //
+// If the URI has a fragment, the instance is evaluated against the subschema
+// the fragment points to. Errors then have the URI of the document, without the
+// fragment, and a SchemaPath which starts with the fragment's tokens.
+//
 // If no schema with the given URI exists for the validator, ErrNoSuchSchema is
 // returned.
 func (v *Validator) ValidateURI(uri url.URL, instance interface{}) (ValidationResult, error) {
-	vm := newVM(v.registry, v.maxStackDepth, v.maxErrors)
+	vm := v.getVM()
+	defer v.putVM(vm)
+
+	if v.trace {
+		vm.trace = &tracer{}
+	}
+
+	if v.coverage != nil {
+		vm.coverage = &coverageRecorder{counts: map[coverageKey]coverageCounts{}}
+	}
 
 	err := vm.Exec(uri, instance)
 	if err != nil {
 		return ValidationResult{}, err
 	}
 
+	if v.coverage != nil {
+		v.coverage.merge(vm.coverage)
+	}
+
 	return vm.ValidationResult(), nil
 }
+
+// ValidateJSON decodes an instance encoded as JSON, and evaluates it against
+// the default schema of the Validator. The InstancePosition of each error is
+// set to where the invalid part of the instance appears in data. The filename
+// is used only in positions.
+//
+// If data is not valid JSON, an instance of ErrSyntax is returned.
+func (v *Validator) ValidateJSON(filename string, data []byte) (ValidationResult, error) {
+	return v.ValidateJSONURI(url.URL{}, filename, data)
+}
+
+// ValidateJSONURI is like ValidateJSON, but evaluates the instance against the
+// schema with the given URI, in the same way as ValidateURI.
+func (v *Validator) ValidateJSONURI(uri url.URL, filename string, data []byte) (ValidationResult, error) {
+	return v.validateDecoded(uri, DecodeJSON, filename, data)
+}
+
+// ValidateYAML decodes an instance encoded as YAML, and evaluates it against
+// the default schema of the Validator. The instance is decoded with DecodeYAML,
+// and the InstancePosition of each error is set as with ValidateJSON.
+func (v *Validator) ValidateYAML(filename string, data []byte) (ValidationResult, error) {
+	return v.ValidateYAMLURI(url.URL{}, filename, data)
+}
+
+// ValidateYAMLURI is like ValidateYAML, but evaluates the instance against the
+// schema with the given URI, in the same way as ValidateURI.
+func (v *Validator) ValidateYAMLURI(uri url.URL, filename string, data []byte) (ValidationResult, error) {
+	return v.validateDecoded(uri, DecodeYAML, filename, data)
+}
+
+// ValidateTOML decodes an instance encoded as TOML, and evaluates it against
+// the default schema of the Validator. The instance is decoded with DecodeTOML,
+// and the InstancePosition of each error is set as with ValidateJSON.
+func (v *Validator) ValidateTOML(filename string, data []byte) (ValidationResult, error) {
+	return v.ValidateTOMLURI(url.URL{}, filename, data)
+}
+
+// ValidateTOMLURI is like ValidateTOML, but evaluates the instance against the
+// schema with the given URI, in the same way as ValidateURI.
+func (v *Validator) ValidateTOMLURI(uri url.URL, filename string, data []byte) (ValidationResult, error) {
+	return v.validateDecoded(uri, DecodeTOML, filename, data)
+}
+
+func (v *Validator) validateDecoded(uri url.URL, decode func(string, []byte) (interface{}, *SourceMap, error), filename string, data []byte) (ValidationResult, error) {
+	instance, source, err := decode(filename, data)
+	if err != nil {
+		return ValidationResult{}, err
+	}
+
+	result, err := v.ValidateURI(uri, instance)
+	if err != nil {
+		return ValidationResult{}, err
+	}
+
+	source.annotate(result.Errors)
+	return result, nil
+}
+
+// IsValid determines whether the default schema of the Validator accepts the
+// given instance.
+//
+// IsValid is faster than Validate, as it does not produce validation errors, and
+// quits at the first error it finds. The Trace, Coverage and error-limiting
+// options of the ValidatorConfig do not apply to IsValid.
+//
+// If no default schema exists for the validator, ErrNoSuchSchema is returned.
+func (v *Validator) IsValid(instance interface{}) (bool, error) {
+	return v.IsValidURI(url.URL{}, instance)
+}
+
+// IsValidURI determines whether the schema identified by the given URI accepts
+// the given instance. See IsValid.
+//
+// If no schema with the given URI exists for the validator, ErrNoSuchSchema is
+// returned.
+func (v *Validator) IsValidURI(uri url.URL, instance interface{}) (bool, error) {
+	vm := v.getVM()
+	defer v.putVM(vm)
+
+	return vm.Check(uri, instance)
+}
+
+// getVM returns a virtual machine ready to evaluate an instance.
+func (v *Validator) getVM() *vm {
+	if v.vms == nil {
+		// the Validator was not constructed by NewValidator, and so has no
+		// schemas; the vm will only ever return ErrNoSuchSchema
+		vm := newVM(v.registry, v.maxStackDepth, v.maxErrors, v.maxErrorsPerInstance, v.maxErrorsPerKeyword)
+		return &vm
+	}
+
+	return v.vms.Get().(*vm)
+}
+
+// putVM returns a virtual machine to the pool once it is no longer in use.
+func (v *Validator) putVM(vm *vm) {
+	if v.vms == nil {
+		return
+	}
+
+	vm.reset()
+	v.vms.Put(vm)
+}
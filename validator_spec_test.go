@@ -7,7 +7,6 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,9 +26,11 @@ type instanceCase struct {
 }
 
 type instanceError struct {
-	InstancePath string `json:"instancePath"`
-	SchemaPath   string `json:"schemaPath"`
-	URI          string `json:"uri"`
+	InstancePath string          `json:"instancePath"`
+	SchemaPath   string          `json:"schemaPath"`
+	URI          string          `json:"uri"`
+	Causes       []instanceError `json:"causes"`
+	Matches      []int           `json:"matches"`
 }
 
 func TestValidatorSpec(t *testing.T) {
@@ -67,42 +68,16 @@ func TestValidatorSpec(t *testing.T) {
 							result, err := validator.Validate(instance.Instance)
 							assert.Nil(t, err)
 
-							expected := make([]ValidationError, len(instance.Errors))
-							for i, e := range instance.Errors {
-								instancePath, _ := jsonpointer.New(e.InstancePath)
-								schemaPath, _ := jsonpointer.New(e.SchemaPath)
-								uri, _ := url.Parse(e.URI)
-
-								expected[i] = ValidationError{
-									InstancePath: instancePath,
-									SchemaPath:   schemaPath,
-									URI:          *uri,
-								}
+							expected := parseInstanceErrors(instance.Errors)
+							if expected == nil {
+								expected = []ValidationError{}
 							}
 
-							sort.Slice(expected, func(i, j int) bool {
-								a := expected[i]
-								b := expected[j]
-
-								if a.SchemaPath.String() == b.SchemaPath.String() {
-									return a.InstancePath.String() < b.InstancePath.String()
-								}
-
-								return a.SchemaPath.String() < b.SchemaPath.String()
-							})
-
-							sort.Slice(result.Errors, func(i, j int) bool {
-								a := result.Errors[i]
-								b := result.Errors[j]
-
-								if a.SchemaPath.String() == b.SchemaPath.String() {
-									return a.InstancePath.String() < b.InstancePath.String()
-								}
-
-								return a.SchemaPath.String() < b.SchemaPath.String()
-							})
-
 							assert.Equal(t, expected, result.Errors)
+
+							isValid, err := validator.IsValid(instance.Instance)
+							assert.Nil(t, err)
+							assert.Equal(t, len(expected) == 0, isValid)
 						})
 					}
 				})
@@ -114,3 +89,26 @@ func TestValidatorSpec(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func parseInstanceErrors(errors []instanceError) []ValidationError {
+	if errors == nil {
+		return nil
+	}
+
+	out := make([]ValidationError, len(errors))
+	for i, e := range errors {
+		instancePath, _ := jsonpointer.New(e.InstancePath)
+		schemaPath, _ := jsonpointer.New(e.SchemaPath)
+		uri, _ := url.Parse(e.URI)
+
+		out[i] = ValidationError{
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath,
+			URI:          *uri,
+			Causes:       parseInstanceErrors(e.Causes),
+			Matches:      e.Matches,
+		}
+	}
+
+	return out
+}
@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"net/url"
+	"sync"
 	"testing"
 
 	"github.com/ucarion/json-pointer"
@@ -617,6 +618,119 @@ func TestValidatorMaxErrors(t *testing.T) {
 	result, err := validator.Validate(true)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResult, result.Errors)
+	assert.True(t, result.Overflowed)
+	assert.Equal(t, 0, result.Suppressed)
+}
+
+func TestValidatorMaxErrorsNotReached(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"items": map[string]interface{}{
+				"type": "null",
+			},
+		},
+	}
+
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxErrors:     3,
+		MaxStackDepth: DefaultMaxStackDepth,
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate([]interface{}{1.0, 2.0})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Errors))
+	assert.False(t, result.Overflowed)
+}
+
+func TestValidatorMaxErrorsReached(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"items": map[string]interface{}{
+				"type": "null",
+			},
+		},
+	}
+
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxErrors:     2,
+		MaxStackDepth: DefaultMaxStackDepth,
+	})
+	assert.NoError(t, err)
+
+	// exactly MaxErrors errors are a complete list
+	result, err := validator.Validate([]interface{}{1.0, 2.0})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Errors))
+	assert.False(t, result.Overflowed)
+
+	result, err = validator.Validate([]interface{}{1.0, 2.0, 3.0})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Errors))
+	assert.True(t, result.Overflowed)
+
+	validator, err = NewValidatorWithConfig([]interface{}{map[string]interface{}{"type": "string"}}, ValidatorConfig{
+		MaxErrors:     1,
+		MaxStackDepth: DefaultMaxStackDepth,
+	})
+	assert.NoError(t, err)
+
+	result, err = validator.Validate(1.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Errors))
+	assert.False(t, result.Overflowed)
+}
+
+func TestValidatorMaxErrorsPerLocation(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"a": map[string]interface{}{
+					"items": map[string]interface{}{
+						"type":    "string",
+						"minimum": 10.0,
+						"maximum": 0.0,
+					},
+				},
+				"b": map[string]interface{}{
+					"type": "string",
+				},
+			},
+		},
+	}
+
+	elems := []interface{}{}
+	for i := 0; i < 100; i++ {
+		elems = append(elems, 5.0)
+	}
+
+	instance := map[string]interface{}{"a": elems, "b": 3.0}
+
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxStackDepth:                DefaultMaxStackDepth,
+		MaxErrorsPerInstanceLocation: 2,
+		MaxErrorsPerKeyword:          10,
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate(instance)
+	assert.NoError(t, err)
+
+	counts := map[string]int{}
+	for _, e := range result.Errors {
+		counts[e.SchemaPath.String()]++
+	}
+
+	// each element has three errors, of which only two are kept per element;
+	// once a keyword has reported ten errors, it makes room for the others
+	assert.Equal(t, map[string]int{
+		"/properties/a/items/type":    10,
+		"/properties/a/items/minimum": 10,
+		"/properties/a/items/maximum": 10,
+		"/properties/b/type":          1,
+	}, counts)
+	assert.True(t, result.Overflowed)
+	assert.Equal(t, 300+1-31, result.Suppressed)
 }
 
 func TestValidatorIsValid(t *testing.T) {
@@ -667,3 +781,434 @@ func TestValidatorValidateURI(t *testing.T) {
 	_, err = validator.ValidateURI(*uriBaz, nil)
 	assert.Equal(t, ErrNoSuchSchema, err)
 }
+
+func TestValidatorValidateURIFragment(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"$id": "http://example.com/foo",
+			"properties": map[string]interface{}{
+				"bar": map[string]interface{}{"type": "null"},
+			},
+		},
+	}
+
+	validator, err := NewValidator(schemas)
+	assert.NoError(t, err)
+
+	uriBar, err := url.Parse("http://example.com/foo#/properties/bar")
+	assert.NoError(t, err)
+
+	result, err := validator.ValidateURI(*uriBar, 3.14)
+	assert.NoError(t, err)
+
+	// the fragment is part of the schema path, not the URI
+	uriFoo, err := url.Parse("http://example.com/foo")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"properties", "bar", "type"}},
+			URI:          *uriFoo,
+		},
+	}, result.Errors)
+}
+
+func TestValidatorIsValidURI(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"$id":  "http://example.com/foo",
+			"type": "null",
+		},
+		map[string]interface{}{
+			"$id":   "http://example.com/bar",
+			"items": map[string]interface{}{"$ref": "#"},
+		},
+	}
+
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{MaxStackDepth: 3})
+	assert.NoError(t, err)
+
+	uriFoo, err := url.Parse("http://example.com/foo")
+	assert.NoError(t, err)
+
+	ok, err := validator.IsValidURI(*uriFoo, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = validator.IsValidURI(*uriFoo, 3.14)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	uriBar, err := url.Parse("http://example.com/bar")
+	assert.NoError(t, err)
+
+	_, err = validator.IsValidURI(*uriBar, []interface{}{[]interface{}{[]interface{}{[]interface{}{}}}})
+	assert.Equal(t, ErrStackOverflow, err)
+
+	uriBaz, err := url.Parse("http://example.com/baz")
+	assert.NoError(t, err)
+
+	_, err = validator.IsValidURI(*uriBaz, nil)
+	assert.Equal(t, ErrNoSuchSchema, err)
+
+	_, err = validator.IsValid(nil)
+	assert.Equal(t, ErrNoSuchSchema, err)
+}
+
+func TestValidatorValidateSchemas(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  ValidatorConfig
+		schemas []interface{}
+		paths   []string
+	}{
+		{
+			"valid schema",
+			ValidatorConfig{ValidateSchemas: true, StrictKeywords: true},
+			[]interface{}{
+				map[string]interface{}{
+					"$schema": "http://json-schema.org/draft-07/schema#",
+					"title":   "person",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"type":      "string",
+							"minLength": 1.0,
+						},
+					},
+					"required": []interface{}{"name"},
+				},
+			},
+			nil,
+		},
+		{
+			"misspelled keywords ignored without strict mode",
+			ValidatorConfig{ValidateSchemas: true},
+			[]interface{}{
+				map[string]interface{}{
+					"requried": []interface{}{"name"},
+				},
+			},
+			nil,
+		},
+		{
+			"misspelled keywords rejected in strict mode",
+			ValidatorConfig{StrictKeywords: true},
+			[]interface{}{
+				map[string]interface{}{
+					"requried": []interface{}{"name"},
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"minLenght": 1.0,
+						},
+					},
+					"definitions": map[string]interface{}{
+						"foo": map[string]interface{}{
+							"itmes": true,
+						},
+					},
+				},
+			},
+			[]string{"#/requried", "#/definitions/foo/itmes", "#/properties/name/minLenght"},
+		},
+		{
+			"keywords with invalid values",
+			ValidatorConfig{ValidateSchemas: true},
+			[]interface{}{
+				map[string]interface{}{
+					"$id": "http://example.com/foo",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"type": "strin",
+						},
+					},
+				},
+				map[string]interface{}{
+					"$id":       "http://example.com/bar",
+					"minLength": -1.0,
+				},
+			},
+			[]string{"http://example.com/foo#/properties/name/type", "http://example.com/bar#/minLength"},
+		},
+		{
+			"unsupported dialect",
+			ValidatorConfig{ValidateSchemas: true},
+			[]interface{}{
+				map[string]interface{}{
+					"$schema": "http://json-schema.org/draft-03/schema#",
+				},
+			},
+			[]string{"#/$schema"},
+		},
+		{
+			"values not produced by encoding/json",
+			ValidatorConfig{ValidateSchemas: true},
+			[]interface{}{
+				map[string]interface{}{
+					"minLength": 3,
+					"enum":      []interface{}{1.0, int64(2)},
+					"required":  []string{"name"},
+				},
+			},
+			[]string{"#/enum/1", "#/minLength", "#/required"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewValidatorWithConfig(tt.schemas, tt.config)
+			if tt.paths == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			schemaErrors, ok := err.(ErrSchemaErrors)
+			assert.True(t, ok)
+
+			paths := []string{}
+			for _, schemaError := range schemaErrors.Errors {
+				uri := schemaError.URI
+				uri.Fragment = schemaError.Path.String()
+				paths = append(paths, uri.String())
+			}
+
+			assert.Equal(t, tt.paths, paths)
+		})
+	}
+}
+
+func TestValidatorCauses(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"anyOf": []interface{}{
+				map[string]interface{}{
+					"type": "null",
+				},
+				map[string]interface{}{
+					"oneOf": []interface{}{
+						map[string]interface{}{"type": "string"},
+						map[string]interface{}{"type": "boolean"},
+					},
+				},
+			},
+		},
+	}
+
+	// causes do not count towards the maximum number of errors
+	validator, err := NewValidatorWithConfig(schemas, ValidatorConfig{
+		MaxErrors:     1,
+		MaxStackDepth: DefaultMaxStackDepth,
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.Validate(3.0)
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationError{
+		{
+			InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+			SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf"}},
+			Causes: []ValidationError{
+				{
+					InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+					SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf", "0", "type"}},
+				},
+				{
+					InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+					SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf", "1", "oneOf"}},
+					Causes: []ValidationError{
+						{
+							InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+							SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf", "1", "oneOf", "0", "type"}},
+						},
+						{
+							InstancePath: jsonpointer.Ptr{Tokens: []string{}},
+							SchemaPath:   jsonpointer.Ptr{Tokens: []string{"anyOf", "1", "oneOf", "1", "type"}},
+						},
+					},
+				},
+			},
+		},
+	}, result.Errors)
+}
+
+// benchmarkSchema is a schema typical of those used to validate API payloads.
+var benchmarkSchema = map[string]interface{}{
+	"definitions": map[string]interface{}{
+		"tag": map[string]interface{}{
+			"type":      "string",
+			"minLength": 1.0,
+			"pattern":   "^[a-z-]+$",
+		},
+	},
+	"type":     "object",
+	"required": []interface{}{"id", "name", "status"},
+	"properties": map[string]interface{}{
+		"id":     map[string]interface{}{"type": "integer", "minimum": 1.0},
+		"name":   map[string]interface{}{"type": "string", "maxLength": 64.0},
+		"status": map[string]interface{}{"enum": []interface{}{"active", "inactive", "deleted"}},
+		"tags": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"$ref": "#/definitions/tag"},
+			"uniqueItems": true,
+		},
+	},
+	"additionalProperties": false,
+}
+
+var benchmarkValidInstance = map[string]interface{}{
+	"id":     1.0,
+	"name":   "example",
+	"status": "active",
+	"tags":   []interface{}{"foo", "bar-baz"},
+}
+
+var benchmarkInvalidInstance = map[string]interface{}{
+	"id":     0.0,
+	"status": "unknown",
+	"tags":   []interface{}{"foo", "BAR", ""},
+	"extra":  true,
+}
+
+func TestValidatorZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations cannot be measured with the race detector")
+	}
+
+	validator, err := NewValidator([]interface{}{benchmarkSchema})
+	assert.NoError(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		result, err := validator.Validate(benchmarkValidInstance)
+		if err != nil || !result.IsValid() {
+			t.Fatal("expected instance to be valid")
+		}
+	})
+
+	assert.Equal(t, 0.0, allocs)
+}
+
+func TestValidatorErrorOrder(t *testing.T) {
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"c": map[string]interface{}{"type": "string"},
+				"a": map[string]interface{}{"type": "string"},
+			},
+			"patternProperties": map[string]interface{}{
+				"^b": map[string]interface{}{"type": "string"},
+			},
+			"additionalProperties": map[string]interface{}{"type": "string"},
+			"propertyNames":        map[string]interface{}{"maxLength": 1.0},
+		},
+	})
+	assert.NoError(t, err)
+
+	instance := map[string]interface{}{
+		"e":  1.0,
+		"c":  1.0,
+		"b":  1.0,
+		"a":  1.0,
+		"dd": "x",
+	}
+
+	expected := []string{
+		"/a /properties/a/type",
+		"/b /patternProperties/^b/type",
+		"/c /properties/c/type",
+		"/e /additionalProperties/type",
+		"/dd /propertyNames/maxLength",
+	}
+
+	for i := 0; i < 20; i++ {
+		result, err := validator.Validate(instance)
+		assert.NoError(t, err)
+
+		actual := []string{}
+		for _, e := range result.Errors {
+			actual = append(actual, e.InstancePath.String()+" "+e.SchemaPath.String())
+		}
+
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestValidatorConcurrent(t *testing.T) {
+	validator, err := NewValidator([]interface{}{benchmarkSchema})
+	assert.NoError(t, err)
+
+	expected, err := validator.Validate(benchmarkInvalidInstance)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				result, err := validator.Validate(benchmarkInvalidInstance)
+				assert.NoError(t, err)
+				assert.Equal(t, expected.Errors, result.Errors)
+
+				result, err = validator.Validate(benchmarkValidInstance)
+				assert.NoError(t, err)
+				assert.Equal(t, []ValidationError{}, result.Errors)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkValidateValid(b *testing.B) {
+	validator, err := NewValidator([]interface{}{benchmarkSchema})
+	assert.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Validate(benchmarkValidInstance)
+	}
+}
+
+func BenchmarkValidateInvalid(b *testing.B) {
+	validator, err := NewValidator([]interface{}{benchmarkSchema})
+	assert.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Validate(benchmarkInvalidInstance)
+	}
+}
+
+func BenchmarkValidateInvalidMaxErrors(b *testing.B) {
+	validator, err := NewValidatorWithConfig([]interface{}{benchmarkSchema}, ValidatorConfig{
+		MaxStackDepth: DefaultMaxStackDepth,
+		MaxErrors:     1,
+	})
+	assert.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Validate(benchmarkInvalidInstance)
+	}
+}
+
+func BenchmarkIsValidValid(b *testing.B) {
+	validator, err := NewValidator([]interface{}{benchmarkSchema})
+	assert.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.IsValid(benchmarkValidInstance)
+	}
+}
+
+func BenchmarkIsValidInvalid(b *testing.B) {
+	validator, err := NewValidator([]interface{}{benchmarkSchema})
+	assert.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.IsValid(benchmarkInvalidInstance)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"math"
 	"net/url"
-	"reflect"
+	"sort"
 	"strconv"
 	"unicode/utf8"
 
@@ -15,6 +15,10 @@ const epsilon = 1e-3
 
 var errMaxErrors = errors.New("internal error for maximum errors")
 
+// errInvalid is an internal flag to quit evaluation at the first error, used
+// when the vm is in quick mode.
+var errInvalid = errors.New("internal error for invalid instance")
+
 type vm struct {
 	// registry holds an arena of schemas
 	registry registry
@@ -30,11 +34,59 @@ type vm struct {
 
 	// maxErrors is the most number of errors that can be reported
 	maxErrors int
+
+	// maxErrorsPerInstance is the most number of errors that can be reported for
+	// a single part of the instance
+	maxErrorsPerInstance int
+
+	// maxErrorsPerKeyword is the most number of errors that can be reported by
+	// a single keyword
+	maxErrorsPerKeyword int
+
+	// limits counts the errors reported so far, for the purpose of enforcing
+	// maxErrorsPerInstance and maxErrorsPerKeyword
+	limits vmLimits
+
+	// trace, if non-nil, records each step of evaluation
+	trace *tracer
+
+	// coverage, if non-nil, counts the evaluations of each schema and keyword
+	coverage *coverageRecorder
+
+	// pseudoDepth is the number of pseudoExec calls currently in progress
+	pseudoDepth int
+
+	// quick indicates that the errors being produced will never be reported, so
+	// that evaluation can quit at the first error, and evaluate cheap keywords
+	// first
+	quick bool
+
+	// keys is a buffer of the property names of the objects being evaluated
+	keys []string
 }
 
 type vmErrors struct {
 	hasErrors bool
 	errors    []ValidationError
+
+	// count is the number of errors reported, including suppressed ones
+	count int
+}
+
+type vmLimits struct {
+	// overflowed is whether evaluation quit early because of maxErrors, having
+	// found more errors than it allows
+	overflowed bool
+
+	// suppressed is the number of errors not reported because of
+	// maxErrorsPerInstance or maxErrorsPerKeyword
+	suppressed int
+
+	// instances counts errors by instance path
+	instances map[string]int
+
+	// keywords counts errors by schema URI and path
+	keywords map[string]int
 }
 
 // stack keeps track of where we are in an instance and schema. It is meant to
@@ -43,12 +95,15 @@ type vmErrors struct {
 type stack struct {
 	// instance is a stack of tokens into the instance, meant to construct a JSON
 	// Pointer.
-	instance []string
+	instance []pathToken
 
-	// schema is a stack of stacks of tokens into the schema, meant to construct a
-	// JSON Pointer. Each schema gets its own stack; because of cross-references,
+	// schema is a stack of schemas being evaluated. Because of cross-references,
 	// there may be many schemas in use.
 	schemas []schemaStack
+
+	// schemaTokens holds the tokens into each schema in schemas, back to back.
+	// They are meant to construct a JSON Pointer.
+	schemaTokens []pathToken
 }
 
 // schemaStack keeps track of where we are in a schema, and which schema we are
@@ -57,31 +112,80 @@ type schemaStack struct {
 	// id is the (non-relative) ID of the schema
 	id url.URL
 
-	// tokens is a stack of tokens into the schema, meant to construct a JSON
-	// Pointer.
-	tokens []string
+	// start is the index in schemaTokens of the first token into the schema
+	start int
 }
 
-func newVM(registry registry, maxStackDepth, maxErrors int) vm {
+// pathToken is a token in a JSON Pointer. Array indices are only formatted as
+// strings when an error is reported, so that valid instances can be evaluated
+// without allocating.
+type pathToken struct {
+	str     string
+	index   int
+	isIndex bool
+}
+
+func (t pathToken) String() string {
+	if t.isIndex {
+		return strconv.FormatInt(int64(t.index), 10)
+	}
+
+	return t.str
+}
+
+func newVM(registry registry, maxStackDepth, maxErrors, maxErrorsPerInstance, maxErrorsPerKeyword int) vm {
 	return vm{
 		registry: registry,
 		stack: stack{
-			instance: []string{},
-			schemas:  []schemaStack{},
+			instance:     []pathToken{},
+			schemas:      []schemaStack{},
+			schemaTokens: []pathToken{},
 		},
 		errors: vmErrors{
 			hasErrors: false,
 			errors:    []ValidationError{},
 		},
-		maxStackDepth: maxStackDepth,
-		maxErrors:     maxErrors,
+		maxStackDepth:        maxStackDepth,
+		maxErrors:            maxErrors,
+		maxErrorsPerInstance: maxErrorsPerInstance,
+		maxErrorsPerKeyword:  maxErrorsPerKeyword,
 	}
 }
 
+// reset prepares a vm to be reused for another evaluation, keeping the memory
+// it has already allocated.
+func (vm *vm) reset() {
+	vm.stack.instance = vm.stack.instance[:0]
+	vm.stack.schemas = vm.stack.schemas[:0]
+	vm.stack.schemaTokens = vm.stack.schemaTokens[:0]
+	vm.errors = vmErrors{errors: vm.errors.errors[:0]}
+	vm.limits = vmLimits{}
+	vm.trace = nil
+	vm.coverage = nil
+	vm.pseudoDepth = 0
+	vm.quick = false
+	vm.keys = vm.keys[:0]
+}
+
 func (vm *vm) ValidationResult() ValidationResult {
-	return ValidationResult{
-		Errors: vm.errors.errors,
+	// the vm's buffer of errors is reused, so it cannot be handed out
+	errors := []ValidationError{}
+	if len(vm.errors.errors) > 0 {
+		errors = make([]ValidationError, len(vm.errors.errors))
+		copy(errors, vm.errors.errors)
+	}
+
+	result := ValidationResult{
+		Errors:     errors,
+		Overflowed: vm.limits.overflowed || vm.limits.suppressed > 0,
+		Suppressed: vm.limits.suppressed,
+	}
+
+	if vm.trace != nil {
+		result.Trace = vm.trace.root
 	}
+
+	return result
 }
 
 func (vm *vm) Exec(uri url.URL, instance interface{}) error {
@@ -90,55 +194,83 @@ func (vm *vm) Exec(uri url.URL, instance interface{}) error {
 		return ErrNoSuchSchema
 	}
 
-	fragPtr, err := jsonpointer.New(uri.Fragment)
-	if err != nil {
-		return err
+	fragPtr := jsonpointer.Ptr{}
+	if uri.Fragment != "" {
+		ptr, err := jsonpointer.New(uri.Fragment)
+		if err != nil {
+			return err
+		}
+
+		fragPtr = ptr
 	}
 
-	vm.pushNewSchema(uri, fragPtr.Tokens)
-	err = vm.execSchema(schema, instance)
+	// the fragment is given by the schema path, so errors have the same URI
+	// however the schema was reached
+	id := uri
+	id.Fragment = ""
+
+	vm.pushNewSchema(id, fragPtr.Tokens)
+	err := vm.execSchema(schema, instance)
 	if err == errMaxErrors {
 		// not a real error -- just an internal flag to quit early
+		vm.limits.overflowed = true
+		vm.traceAbort()
 		return nil
 	}
 
 	return err
 }
 
-func (vm *vm) execSchema(schema schema, instance interface{}) error {
-	if schema.Bool.IsSet {
-		if !schema.Bool.Value {
-			if err := vm.reportError(); err != nil {
-				return err
-			}
-		}
+func (vm *vm) execSchema(schema *schema, instance interface{}) error {
+	vm.traceEnter("")
 
-		return nil
+	kind := kindOf(instance)
+	code := schema.Program.code[kind]
+	if vm.cheapFirst() {
+		code = schema.Program.fast[kind]
+	}
+
+	for _, inst := range code {
+		if err := vm.execInstruction(schema, inst, instance); err != nil {
+			return err
+		}
 	}
 
-	if schema.Ref.IsSet {
+	vm.traceExit()
+	return nil
+}
+
+func (vm *vm) execInstruction(schema *schema, inst instruction, instance interface{}) error {
+	switch inst.op {
+	case opFalse:
+		return vm.reportError()
+	case opRef:
+		vm.traceEnter("$ref")
+
 		if len(vm.stack.schemas) == vm.maxStackDepth {
 			return ErrStackOverflow
 		}
 
 		refSchema := vm.registry.GetIndex(schema.Ref.Schema)
 
-		schemaTokens := make([]string, len(schema.Ref.Ptr.Tokens))
-		copy(schemaTokens, schema.Ref.Ptr.Tokens)
-
-		vm.pushNewSchema(schema.Ref.BaseURI, schemaTokens)
+		vm.pushNewSchema(schema.Ref.BaseURI, schema.Ref.Ptr.Tokens)
 		if err := vm.execSchema(refSchema, instance); err != nil {
 			return err
 		}
 		vm.popSchema()
-	}
 
-	if schema.Not.IsSet {
+		vm.traceExit()
+	case opNot:
+		vm.traceEnter("not")
+
 		notSchema := vm.registry.GetIndex(schema.Not.Schema)
-		notErrors, err := vm.pseudoExec(notSchema, instance)
+
+		vm.pushSchemaToken("not")
+		notErrors, err := vm.pseudoCheck(notSchema, instance)
 		if err != nil {
 			return err
 		}
+		vm.popSchemaToken()
 
 		if !notErrors {
 			vm.pushSchemaToken("not")
@@ -147,17 +279,26 @@ func (vm *vm) execSchema(schema schema, instance interface{}) error {
 			}
 			vm.popSchemaToken()
 		}
-	}
 
-	if schema.If.IsSet {
+		vm.traceExit()
+	case opIf:
+		vm.traceEnter("if")
+
 		ifSchema := vm.registry.GetIndex(schema.If.Schema)
-		ifErrors, err := vm.pseudoExec(ifSchema, instance)
+
+		vm.pushSchemaToken("if")
+		ifErrors, err := vm.pseudoCheck(ifSchema, instance)
 		if err != nil {
 			return err
 		}
+		vm.popSchemaToken()
+
+		vm.traceExit()
 
 		if !ifErrors {
 			if schema.Then.IsSet {
+				vm.traceEnter("then")
+
 				thenSchema := vm.registry.GetIndex(schema.Then.Schema)
 
 				vm.pushSchemaToken("then")
@@ -165,9 +306,13 @@ func (vm *vm) execSchema(schema schema, instance interface{}) error {
 					return err
 				}
 				vm.popSchemaToken()
+
+				vm.traceExit()
 			}
 		} else {
 			if schema.Else.IsSet {
+				vm.traceEnter("else")
+
 				elseSchema := vm.registry.GetIndex(schema.Else.Schema)
 
 				vm.pushSchemaToken("else")
@@ -175,46 +320,41 @@ func (vm *vm) execSchema(schema schema, instance interface{}) error {
 					return err
 				}
 				vm.popSchemaToken()
+
+				vm.traceExit()
 			}
 		}
-	}
+	case opConst:
+		vm.traceEnter("const")
 
-	if schema.Const.IsSet {
-		if !reflect.DeepEqual(instance, schema.Const.Value) {
-			vm.pushSchemaToken("const")
-			if err := vm.reportError(); err != nil {
+		// a single comparison gains nothing from hashing, as jsonEqual quits at
+		// the first difference it finds
+		if !jsonEqual(instance, schema.Const.Value) {
+			if err := vm.reportKeywordError("const"); err != nil {
 				return err
 			}
-			vm.popSchemaToken()
 		}
-	}
 
-	if schema.Enum.IsSet {
-		enumOk := false
-		for _, value := range schema.Enum.Values {
-			if reflect.DeepEqual(instance, value) {
-				enumOk = true
-				break
-			}
-		}
+		vm.traceExit()
+	case opEnum:
+		vm.traceEnter("enum")
 
-		if !enumOk {
-			vm.pushSchemaToken("enum")
-			if err := vm.reportError(); err != nil {
+		if !schema.Enum.Set.contains(instance) {
+			if err := vm.reportKeywordError("enum"); err != nil {
 				return err
 			}
-			vm.popSchemaToken()
 		}
-	}
 
-	if schema.AllOf.IsSet {
+		vm.traceExit()
+	case opAllOf:
+		vm.traceEnter("allOf")
+
 		vm.pushSchemaToken("allOf")
 
 		for i, index := range schema.AllOf.Schemas {
 			allOfSchema := vm.registry.GetIndex(index)
-			token := strconv.FormatInt(int64(i), 10)
 
-			vm.pushSchemaToken(token)
+			vm.pushSchemaIndex(i)
 			if err := vm.execSchema(allOfSchema, instance); err != nil {
 				return err
 			}
@@ -222,243 +362,295 @@ func (vm *vm) execSchema(schema schema, instance interface{}) error {
 		}
 
 		vm.popSchemaToken()
-	}
 
-	if schema.AnyOf.IsSet {
+		vm.traceExit()
+	case opAnyOf:
+		vm.traceEnter("anyOf")
+
 		anyOfOk := false
-		for _, index := range schema.AnyOf.Schemas {
+		anyOfCauses := []ValidationError(nil)
+
+		vm.pushSchemaToken("anyOf")
+		for i, index := range schema.AnyOf.Schemas {
 			anyOfSchema := vm.registry.GetIndex(index)
-			anyOfErrors, err := vm.pseudoExec(anyOfSchema, instance)
+
+			vm.pushSchemaIndex(i)
+			anyOfErrors, anyOfSchemaErrors, err := vm.pseudoExec(anyOfSchema, instance)
 			if err != nil {
 				return err
 			}
+			vm.popSchemaToken()
 
 			if !anyOfErrors {
 				anyOfOk = true
 				break
 			}
+
+			anyOfCauses = append(anyOfCauses, anyOfSchemaErrors...)
 		}
+		vm.popSchemaToken()
 
 		if !anyOfOk {
 			vm.pushSchemaToken("anyOf")
-			if err := vm.reportError(); err != nil {
+			if err := vm.reportErrorWithCauses(anyOfCauses, nil); err != nil {
 				return err
 			}
 			vm.popSchemaToken()
 		}
-	}
 
-	if schema.OneOf.IsSet {
-		oneOfOk := false
-		for _, index := range schema.OneOf.Schemas {
+		vm.traceExit()
+	case opOneOf:
+		vm.traceEnter("oneOf")
+
+		// when the instance picks its alternative by a discriminator property,
+		// only that alternative is evaluated, and its errors are reported as
+		// they are
+		if schema.Program.oneOf != nil {
+			if i, ok := schema.Program.oneOf.alternative(instance); ok {
+				oneOfSchema := vm.registry.GetIndex(schema.OneOf.Schemas[i])
+
+				vm.pushSchemaToken("oneOf")
+				vm.pushSchemaIndex(i)
+				if err := vm.execSchema(oneOfSchema, instance); err != nil {
+					return err
+				}
+				vm.popSchemaToken()
+				vm.popSchemaToken()
+
+				vm.traceExit()
+				break
+			}
+		}
+
+		oneOfCauses := []ValidationError(nil)
+		oneOfMatches := []int(nil)
+
+		vm.pushSchemaToken("oneOf")
+		for i, index := range schema.OneOf.Schemas {
 			oneOfSchema := vm.registry.GetIndex(index)
-			oneOfErrors, err := vm.pseudoExec(oneOfSchema, instance)
+
+			vm.pushSchemaIndex(i)
+			oneOfErrors, oneOfSchemaErrors, err := vm.pseudoExec(oneOfSchema, instance)
 			if err != nil {
 				return err
 			}
+			vm.popSchemaToken()
+
+			if oneOfErrors {
+				oneOfCauses = append(oneOfCauses, oneOfSchemaErrors...)
+			} else {
+				oneOfMatches = append(oneOfMatches, i)
 
-			if !oneOfErrors {
-				if oneOfOk {
-					oneOfOk = false
+				// when errors will not be reported, the other subschemas cannot
+				// change the outcome once two have matched
+				if vm.quick && len(oneOfMatches) > 1 {
 					break
-				} else {
-					oneOfOk = true
 				}
 			}
 		}
+		vm.popSchemaToken()
+
+		if len(oneOfMatches) != 1 {
+			if len(oneOfMatches) > 1 {
+				// the errors of the other subschemas are not why oneOf failed
+				oneOfCauses = nil
+			} else {
+				oneOfMatches = nil
+			}
 
-		if !oneOfOk {
 			vm.pushSchemaToken("oneOf")
-			if err := vm.reportError(); err != nil {
+			if err := vm.reportErrorWithCauses(oneOfCauses, oneOfMatches); err != nil {
 				return err
 			}
 			vm.popSchemaToken()
 		}
-	}
 
-	switch val := instance.(type) {
-	case nil:
-		if schema.Type.IsSet && !schema.Type.contains(jsonTypeNull) {
-			vm.pushSchemaToken("type")
-			if err := vm.reportError(); err != nil {
+		vm.traceExit()
+	case opType:
+		vm.traceEnter("type")
+
+		if !schema.Type.accepts(instance) {
+			if err := vm.reportKeywordError("type"); err != nil {
 				return err
 			}
-			vm.popSchemaToken()
 		}
-	case bool:
-		if schema.Type.IsSet && !schema.Type.contains(jsonTypeBoolean) {
-			vm.pushSchemaToken("type")
-			if err := vm.reportError(); err != nil {
+
+		vm.traceExit()
+	case opMultipleOf:
+		vm.traceEnter("multipleOf")
+
+		if math.Abs(math.Mod(instance.(float64), schema.MultipleOf.Value)) > epsilon {
+			if err := vm.reportKeywordError("multipleOf"); err != nil {
 				return err
 			}
-			vm.popSchemaToken()
 		}
-	case float64:
-		if schema.Type.IsSet {
-			typeOk := false
-			if schema.Type.contains(jsonTypeInteger) {
-				typeOk = val == math.Round(val)
-			}
 
-			if !typeOk && !schema.Type.contains(jsonTypeNumber) {
-				vm.pushSchemaToken("type")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opMaximum:
+		vm.traceEnter("maximum")
+
+		if instance.(float64) > schema.Maximum.Value {
+			if err := vm.reportKeywordError("maximum"); err != nil {
+				return err
 			}
 		}
 
-		if schema.MultipleOf.IsSet {
-			if math.Abs(math.Mod(val, schema.MultipleOf.Value)) > epsilon {
-				vm.pushSchemaToken("multipleOf")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opMinimum:
+		vm.traceEnter("minimum")
+
+		if instance.(float64) < schema.Minimum.Value {
+			if err := vm.reportKeywordError("minimum"); err != nil {
+				return err
 			}
 		}
 
-		if schema.Maximum.IsSet {
-			if val > schema.Maximum.Value {
-				vm.pushSchemaToken("maximum")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opExclusiveMaximum:
+		vm.traceEnter("exclusiveMaximum")
+
+		if instance.(float64) > schema.ExclusiveMaximum.Value-epsilon {
+			if err := vm.reportKeywordError("exclusiveMaximum"); err != nil {
+				return err
 			}
 		}
 
-		if schema.Minimum.IsSet {
-			if val < schema.Minimum.Value {
-				vm.pushSchemaToken("minimum")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opExclusiveMinimum:
+		vm.traceEnter("exclusiveMinimum")
+
+		if instance.(float64) < schema.ExclusiveMinimum.Value+epsilon {
+			if err := vm.reportKeywordError("exclusiveMinimum"); err != nil {
+				return err
 			}
 		}
 
-		if schema.ExclusiveMaximum.IsSet {
-			if val > schema.ExclusiveMaximum.Value-epsilon {
-				vm.pushSchemaToken("exclusiveMaximum")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opMaxLength:
+		vm.traceEnter("maxLength")
+
+		if utf8.RuneCountInString(instance.(string)) > schema.MaxLength.Value {
+			if err := vm.reportKeywordError("maxLength"); err != nil {
+				return err
 			}
 		}
 
-		if schema.ExclusiveMinimum.IsSet {
-			if val < schema.ExclusiveMinimum.Value+epsilon {
-				vm.pushSchemaToken("exclusiveMinimum")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opMinLength:
+		vm.traceEnter("minLength")
+
+		if utf8.RuneCountInString(instance.(string)) < schema.MinLength.Value {
+			if err := vm.reportKeywordError("minLength"); err != nil {
+				return err
 			}
 		}
-	case string:
-		if schema.Type.IsSet && !schema.Type.contains(jsonTypeString) {
-			vm.pushSchemaToken("type")
-			if err := vm.reportError(); err != nil {
+
+		vm.traceExit()
+	case opPattern:
+		vm.traceEnter("pattern")
+
+		if !schema.Pattern.Value.MatchString(instance.(string)) {
+			if err := vm.reportKeywordError("pattern"); err != nil {
 				return err
 			}
-			vm.popSchemaToken()
 		}
 
-		if schema.MaxLength.IsSet {
-			if utf8.RuneCountInString(val) > schema.MaxLength.Value {
-				vm.pushSchemaToken("maxLength")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opMaxItems:
+		vm.traceEnter("maxItems")
+
+		if len(instance.([]interface{})) > schema.MaxItems.Value {
+			if err := vm.reportKeywordError("maxItems"); err != nil {
+				return err
 			}
 		}
 
-		if schema.MinLength.IsSet {
-			if utf8.RuneCountInString(val) < schema.MinLength.Value {
-				vm.pushSchemaToken("minLength")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opMinItems:
+		vm.traceEnter("minItems")
+
+		if len(instance.([]interface{})) < schema.MinItems.Value {
+			if err := vm.reportKeywordError("minItems"); err != nil {
+				return err
 			}
 		}
 
-		if schema.Pattern.IsSet {
-			if !schema.Pattern.Value.MatchString(val) {
-				vm.pushSchemaToken("pattern")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opUniqueItems:
+		vm.traceEnter("uniqueItems")
+
+		if !jsonUnique(instance.([]interface{})) {
+			if err := vm.reportKeywordError("uniqueItems"); err != nil {
+				return err
 			}
 		}
-	case []interface{}:
-		if schema.Type.IsSet && !schema.Type.contains(jsonTypeArray) {
-			vm.pushSchemaToken("type")
-			if err := vm.reportError(); err != nil {
+
+		vm.traceExit()
+	case opContains:
+		vm.traceEnter("contains")
+
+		containsOk := false
+		containsCauses := []ValidationError(nil)
+		containsSchema := vm.registry.GetIndex(schema.Contains.Schema)
+
+		vm.pushSchemaToken("contains")
+		for i, elem := range instance.([]interface{}) {
+			vm.pushInstanceIndex(i)
+			containsErrors, containsElemErrors, err := vm.pseudoExec(containsSchema, elem)
+			if err != nil {
 				return err
 			}
-			vm.popSchemaToken()
-		}
+			vm.popInstanceToken()
 
-		if schema.MaxItems.IsSet {
-			if len(val) > schema.MaxItems.Value {
-				vm.pushSchemaToken("maxItems")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+			if !containsErrors {
+				containsOk = true
+				break
 			}
+
+			containsCauses = append(containsCauses, containsElemErrors...)
 		}
+		vm.popSchemaToken()
 
-		if schema.MinItems.IsSet {
-			if len(val) < schema.MinItems.Value {
-				vm.pushSchemaToken("minItems")
-				if err := vm.reportError(); err != nil {
-					return err
-				}
-				vm.popSchemaToken()
+		if !containsOk {
+			vm.pushSchemaToken("contains")
+			if err := vm.reportErrorWithCauses(containsCauses, nil); err != nil {
+				return err
 			}
+			vm.popSchemaToken()
 		}
 
-		if schema.UniqueItems.IsSet && schema.UniqueItems.Value {
-		loop:
-			for i := 0; i < len(val); i++ {
-				for j := i + 1; j < len(val); j++ {
-					if reflect.DeepEqual(val[i], val[j]) {
-						vm.pushSchemaToken("uniqueItems")
-						if err := vm.reportError(); err != nil {
-							return err
-						}
-						vm.popSchemaToken()
+		vm.traceExit()
+	case opItems:
+		return vm.execItems(schema, instance.([]interface{}))
+	case opMaxProperties:
+		vm.traceEnter("maxProperties")
 
-						break loop
-					}
-				}
+		if len(instance.(map[string]interface{})) > schema.MaxProperties.Value {
+			if err := vm.reportKeywordError("maxProperties"); err != nil {
+				return err
 			}
 		}
 
-		if schema.Contains.IsSet {
-			containsOk := false
-			for _, elem := range val {
-				containsSchema := vm.registry.GetIndex(schema.Contains.Schema)
-				containsErrors, err := vm.pseudoExec(containsSchema, elem)
-				if err != nil {
-					return err
-				}
+		vm.traceExit()
+	case opMinProperties:
+		vm.traceEnter("minProperties")
 
-				if !containsErrors {
-					containsOk = true
-					break
-				}
+		if len(instance.(map[string]interface{})) < schema.MinProperties.Value {
+			if err := vm.reportKeywordError("minProperties"); err != nil {
+				return err
 			}
+		}
 
-			if !containsOk {
-				vm.pushSchemaToken("contains")
+		vm.traceExit()
+	case opRequired:
+		vm.traceEnter("required")
+
+		vm.pushSchemaToken("required")
+
+		val := instance.(map[string]interface{})
+		for i, property := range schema.Required.Properties {
+			if _, ok := val[property]; !ok {
+				vm.pushSchemaIndex(i)
 				if err := vm.reportError(); err != nil {
 					return err
 				}
@@ -466,272 +658,557 @@ func (vm *vm) execSchema(schema schema, instance interface{}) error {
 			}
 		}
 
-		if schema.Items.IsSet {
-			if schema.Items.IsSingle {
-				vm.pushSchemaToken("items")
+		vm.popSchemaToken()
 
-				itemSchema := vm.registry.GetIndex(schema.Items.Schemas[0])
-				for i, elem := range val {
-					vm.pushInstanceToken(strconv.FormatInt(int64(i), 10))
-					if err := vm.execSchema(itemSchema, elem); err != nil {
-						return err
-					}
-					vm.popInstanceToken()
-				}
-				vm.popSchemaToken()
-			} else {
-				vm.pushSchemaToken("items")
-				for i := 0; i < len(schema.Items.Schemas) && i < len(val); i++ {
-					itemSchema := vm.registry.GetIndex(schema.Items.Schemas[i])
-					token := strconv.FormatInt(int64(i), 10)
-
-					vm.pushInstanceToken(token)
-					vm.pushSchemaToken(token)
-					if err := vm.execSchema(itemSchema, val[i]); err != nil {
-						return err
-					}
-					vm.popInstanceToken()
-					vm.popSchemaToken()
-				}
-				vm.popSchemaToken()
+		vm.traceExit()
+	case opProperties:
+		return vm.execProperties(schema, instance.(map[string]interface{}))
+	case opDependencies:
+		return vm.execDependencies(schema, instance.(map[string]interface{}))
+	case opPropertyNames:
+		return vm.execPropertyNames(schema, instance.(map[string]interface{}))
+	case opKeyword:
+		keyword := schema.Keywords[inst.keyword]
+		reporter := KeywordReporter{vm: vm}
+
+		vm.traceEnter(keyword.Name)
+		vm.pushSchemaToken(keyword.Name)
+		if err := keyword.Evaluator(instance, &reporter); err != nil {
+			return err
+		}
+		vm.popSchemaToken()
+		vm.traceExit()
+	}
 
-				if schema.AdditionalItems.IsSet {
-					vm.pushSchemaToken("additionalItems")
+	return nil
+}
 
-					additionalItemSchema := vm.registry.GetIndex(schema.AdditionalItems.Schema)
-					for i := len(schema.Items.Schemas); i < len(val); i++ {
-						token := strconv.FormatInt(int64(i), 10)
+func (vm *vm) execItems(schema *schema, val []interface{}) error {
+	vm.traceEnter("items")
 
-						vm.pushInstanceToken(token)
-						if err := vm.execSchema(additionalItemSchema, val[i]); err != nil {
-							return err
-						}
-						vm.popInstanceToken()
-					}
-					vm.popSchemaToken()
-				}
+	if schema.Items.IsSingle {
+		vm.pushSchemaToken("items")
+
+		itemSchema := vm.registry.GetIndex(schema.Items.Schemas[0])
+		for i, elem := range val {
+			vm.pushInstanceIndex(i)
+			if err := vm.execSchema(itemSchema, elem); err != nil {
+				return err
 			}
+			vm.popInstanceToken()
 		}
-	case map[string]interface{}:
-		if schema.Type.IsSet && !schema.Type.contains(jsonTypeObject) {
-			vm.pushSchemaToken("type")
-			if err := vm.reportError(); err != nil {
+		vm.popSchemaToken()
+	} else {
+		vm.pushSchemaToken("items")
+		for i := 0; i < len(schema.Items.Schemas) && i < len(val); i++ {
+			itemSchema := vm.registry.GetIndex(schema.Items.Schemas[i])
+
+			vm.pushInstanceIndex(i)
+			vm.pushSchemaIndex(i)
+			if err := vm.execSchema(itemSchema, val[i]); err != nil {
 				return err
 			}
+			vm.popInstanceToken()
 			vm.popSchemaToken()
 		}
+		vm.popSchemaToken()
 
-		if schema.MaxProperties.IsSet {
-			if len(val) > schema.MaxProperties.Value {
-				vm.pushSchemaToken("maxProperties")
-				if err := vm.reportError(); err != nil {
+		if schema.AdditionalItems.IsSet {
+			vm.traceEnter("additionalItems")
+
+			vm.pushSchemaToken("additionalItems")
+
+			additionalItemSchema := vm.registry.GetIndex(schema.AdditionalItems.Schema)
+			for i := len(schema.Items.Schemas); i < len(val); i++ {
+				vm.pushInstanceIndex(i)
+				if err := vm.execSchema(additionalItemSchema, val[i]); err != nil {
 					return err
 				}
-				vm.popSchemaToken()
+				vm.popInstanceToken()
 			}
+			vm.popSchemaToken()
+
+			vm.traceExit()
 		}
+	}
 
-		if schema.MinProperties.IsSet {
-			if len(val) < schema.MinProperties.Value {
-				vm.pushSchemaToken("minProperties")
-				if err := vm.reportError(); err != nil {
+	vm.traceExit()
+	return nil
+}
+
+// execProperties evaluates "properties", "patternProperties" and
+// "additionalProperties" together, as whether a property is additional depends
+// on the other two.
+func (vm *vm) execProperties(schema *schema, val map[string]interface{}) error {
+	// each keyword is a single step of the trace, suspended while the others
+	// are evaluated for the same property
+	var properties, patternProperties, additionalProperties traceStep
+	if schema.Properties.IsSet {
+		vm.traceEnter("properties")
+		properties = vm.traceSuspend()
+	}
+
+	if schema.PatternProperties.IsSet {
+		vm.traceEnter("patternProperties")
+		patternProperties = vm.traceSuspend()
+	}
+
+	if schema.AdditionalProperties.IsSet {
+		vm.traceEnter("additionalProperties")
+		additionalProperties = vm.traceSuspend()
+	}
+
+	keys := vm.pushKeys(val)
+	for _, key := range keys {
+		value := val[key]
+		isAdditional := true
+
+		if schema.Properties.IsSet {
+			if index, ok := schema.Properties.Schemas[key]; ok {
+				isAdditional = false
+				propertySchema := vm.registry.GetIndex(index)
+
+				vm.traceResume(properties)
+				vm.pushSchemaToken("properties")
+				vm.pushSchemaToken(key)
+				vm.pushInstanceToken(key)
+				if err := vm.execSchema(propertySchema, value); err != nil {
 					return err
 				}
+				vm.popInstanceToken()
 				vm.popSchemaToken()
+				vm.popSchemaToken()
+				properties = vm.traceSuspend()
 			}
 		}
 
-		if schema.Required.IsSet {
-			vm.pushSchemaToken("required")
+		for _, patternProperty := range schema.Program.patternProperties {
+			if patternProperty.pattern.MatchString(key) {
+				isAdditional = false
+				propertySchema := vm.registry.GetIndex(patternProperty.schema)
 
-			for i, property := range schema.Required.Properties {
-				if _, ok := val[property]; !ok {
-					vm.pushSchemaToken(strconv.FormatInt(int64(i), 10))
-					if err := vm.reportError(); err != nil {
-						return err
-					}
-					vm.popSchemaToken()
+				vm.traceResume(patternProperties)
+				vm.pushSchemaToken("patternProperties")
+				vm.pushSchemaToken(patternProperty.pattern.String())
+				vm.pushInstanceToken(key)
+				if err := vm.execSchema(propertySchema, value); err != nil {
+					return err
 				}
+				vm.popInstanceToken()
+				vm.popSchemaToken()
+				vm.popSchemaToken()
+				patternProperties = vm.traceSuspend()
 			}
+		}
+
+		if schema.AdditionalProperties.IsSet && isAdditional {
+			propertySchema := vm.registry.GetIndex(schema.AdditionalProperties.Schema)
 
+			vm.traceResume(additionalProperties)
+			vm.pushSchemaToken("additionalProperties")
+			vm.pushInstanceToken(key)
+			if err := vm.execSchema(propertySchema, value); err != nil {
+				return err
+			}
+			vm.popInstanceToken()
 			vm.popSchemaToken()
+			additionalProperties = vm.traceSuspend()
 		}
+	}
+	vm.popKeys(keys)
 
-		for key, value := range val {
-			isAdditional := true
+	if schema.Properties.IsSet {
+		vm.traceResume(properties)
+		vm.traceExit()
+	}
 
-			if schema.Properties.IsSet {
-				if index, ok := schema.Properties.Schemas[key]; ok {
-					isAdditional = false
-					propertySchema := vm.registry.GetIndex(index)
+	if schema.PatternProperties.IsSet {
+		vm.traceResume(patternProperties)
+		vm.traceExit()
+	}
 
-					vm.pushSchemaToken("properties")
-					vm.pushSchemaToken(key)
-					vm.pushInstanceToken(key)
-					if err := vm.execSchema(propertySchema, value); err != nil {
-						return err
-					}
-					vm.popInstanceToken()
-					vm.popSchemaToken()
-					vm.popSchemaToken()
-				}
-			}
+	if schema.AdditionalProperties.IsSet {
+		vm.traceResume(additionalProperties)
+		vm.traceExit()
+	}
 
-			if schema.PatternProperties.IsSet {
-				for pattern, index := range schema.PatternProperties.Schemas {
-					if pattern.MatchString(key) {
-						isAdditional = false
-						propertySchema := vm.registry.GetIndex(index)
+	return nil
+}
 
-						vm.pushSchemaToken("patternProperties")
-						vm.pushSchemaToken(pattern.String())
-						vm.pushInstanceToken(key)
-						if err := vm.execSchema(propertySchema, value); err != nil {
-							return err
-						}
-						vm.popInstanceToken()
-						vm.popSchemaToken()
-						vm.popSchemaToken()
-					}
-				}
-			}
+func (vm *vm) execDependencies(schema *schema, val map[string]interface{}) error {
+	vm.traceEnter("dependencies")
+
+	vm.pushSchemaToken("dependencies")
 
-			if schema.AdditionalProperties.IsSet && isAdditional {
-				propertySchema := vm.registry.GetIndex(schema.AdditionalProperties.Schema)
+	for _, key := range schema.Program.dependencies {
+		dep := schema.Dependencies.Deps[key]
+		vm.pushSchemaToken(key)
+
+		if value, ok := val[key]; ok {
+			if dep.IsSchema {
+				propertySchema := vm.registry.GetIndex(dep.Schema)
 
-				vm.pushSchemaToken("additionalProperties")
 				vm.pushInstanceToken(key)
 				if err := vm.execSchema(propertySchema, value); err != nil {
 					return err
 				}
 				vm.popInstanceToken()
-				vm.popSchemaToken()
-			}
-		}
-
-		if schema.Dependencies.IsSet {
-			vm.pushSchemaToken("dependencies")
-
-			for key, dep := range schema.Dependencies.Deps {
-				vm.pushSchemaToken(key)
-
-				if value, ok := val[key]; ok {
-					if dep.IsSchema {
-						propertySchema := vm.registry.GetIndex(dep.Schema)
+			} else {
+				// dependencies on properties have no schema to record coverage
+				// for, so they are recorded here
+				vm.coverageEnter("")
 
-						vm.pushInstanceToken(key)
-						if err := vm.execSchema(propertySchema, value); err != nil {
+				for i, property := range dep.Properties {
+					if _, ok := val[property]; !ok {
+						vm.pushSchemaIndex(i)
+						if err := vm.reportError(); err != nil {
 							return err
 						}
-						vm.popInstanceToken()
-					} else {
-						for i, property := range dep.Properties {
-							if _, ok := val[property]; !ok {
-								vm.pushSchemaToken(strconv.FormatInt(int64(i), 10))
-								if err := vm.reportError(); err != nil {
-									return err
-								}
-								vm.popSchemaToken()
-							}
-						}
+						vm.popSchemaToken()
 					}
 				}
 
-				vm.popSchemaToken()
+				vm.coverageExit()
 			}
-
-			vm.popSchemaToken()
 		}
 
-		if schema.PropertyNames.IsSet {
-			vm.pushSchemaToken("propertyNames")
+		vm.popSchemaToken()
+	}
 
-			propertyNameSchema := vm.registry.GetIndex(schema.PropertyNames.Schema)
-			for key := range val {
-				vm.pushInstanceToken(key)
-				if err := vm.execSchema(propertyNameSchema, key); err != nil {
-					return err
-				}
-				vm.popInstanceToken()
-			}
+	vm.popSchemaToken()
 
-			vm.popSchemaToken()
+	vm.traceExit()
+	return nil
+}
+
+func (vm *vm) execPropertyNames(schema *schema, val map[string]interface{}) error {
+	vm.traceEnter("propertyNames")
+
+	vm.pushSchemaToken("propertyNames")
+
+	propertyNameSchema := vm.registry.GetIndex(schema.PropertyNames.Schema)
+
+	keys := vm.pushKeys(val)
+	for _, key := range keys {
+		vm.pushInstanceToken(key)
+		if err := vm.execSchema(propertyNameSchema, key); err != nil {
+			return err
 		}
-	default:
-		// TODO a better error here
-		panic("unexpected non-json input")
+		vm.popInstanceToken()
 	}
+	vm.popKeys(keys)
+
+	vm.popSchemaToken()
 
+	vm.traceExit()
 	return nil
 }
 
+// pushKeys returns the property names of an object, sorted so that errors are
+// reported in a stable order. The names are kept in a buffer owned by the vm,
+// and must be released with popKeys.
+//
+// In quick mode, the order of errors does not matter, and so the names are not
+// sorted.
+func (vm *vm) pushKeys(object map[string]interface{}) []string {
+	start := len(vm.keys)
+	for key := range object {
+		vm.keys = append(vm.keys, key)
+	}
+
+	keys := vm.keys[start:]
+	if !vm.quick {
+		sort.Strings(keys)
+	}
+
+	return keys
+}
+
+func (vm *vm) popKeys(keys []string) {
+	vm.keys = vm.keys[:len(vm.keys)-len(keys)]
+}
+
+// Check determines whether the schema identified by uri accepts an instance.
+// It runs in quick mode, quitting at the first error.
+func (vm *vm) Check(uri url.URL, instance interface{}) (bool, error) {
+	schema, ok := vm.registry.Get(uri)
+	if !ok {
+		return false, ErrNoSuchSchema
+	}
+
+	if uri.Fragment != "" {
+		if _, err := jsonpointer.New(uri.Fragment); err != nil {
+			return false, err
+		}
+	}
+
+	vm.quick = true
+	vm.pushNewSchema(uri, nil)
+
+	err := vm.execSchema(schema, instance)
+	if err == errInvalid {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// custom keywords may have ignored the error from their reporter
+	return !vm.errors.hasErrors, nil
+}
+
+// cheapFirst determines whether cheap keywords can be evaluated first. They can
+// in quick mode, and when only the first error will be reported, in which case
+// any error will do. Errors reported as the causes of another error, traces,
+// and coverage are still produced in the usual order.
+func (vm *vm) cheapFirst() bool {
+	if vm.quick {
+		return true
+	}
+
+	return vm.maxErrors == 1 && vm.pseudoDepth == 0 && vm.trace == nil && vm.coverage == nil
+}
+
 // pseudoExec determines whether a given schema accepts an instance, with the
 // guarantee that the vm exits this function in the same state it was in when
 // the function was called.
-func (vm *vm) pseudoExec(schema schema, instance interface{}) (bool, error) {
+//
+// The errors the schema produced are returned, so that they may be reported as
+// the causes of another error. They do not count towards maxErrors.
+func (vm *vm) pseudoExec(schema *schema, instance interface{}) (bool, []ValidationError, error) {
 	prevErrors := vm.errors
 	vm.errors = vmErrors{
 		hasErrors: false,
 		errors:    []ValidationError{},
 	}
 
-	if err := vm.execSchema(schema, instance); err != nil {
-		return false, err
+	instanceLen := len(vm.stack.instance)
+	schemasLen := len(vm.stack.schemas)
+	schemaTokensLen := len(vm.stack.schemaTokens)
+	keysLen := len(vm.keys)
+
+	vm.pseudoDepth++
+	err := vm.execSchema(schema, instance)
+	vm.pseudoDepth--
+
+	if err == errInvalid {
+		// the schema quit at its first error, leaving the stacks as they were at
+		// that point
+		vm.stack.instance = vm.stack.instance[:instanceLen]
+		vm.stack.schemas = vm.stack.schemas[:schemasLen]
+		vm.stack.schemaTokens = vm.stack.schemaTokens[:schemaTokensLen]
+		vm.keys = vm.keys[:keysLen]
+		vm.errors = prevErrors
+
+		return true, nil, nil
+	}
+
+	if err != nil {
+		return false, nil, err
 	}
 
 	pseudoErrors := vm.errors
 	vm.errors = prevErrors
 
-	return pseudoErrors.hasErrors, nil
+	return pseudoErrors.hasErrors, pseudoErrors.errors, nil
 }
 
+// pseudoCheck is like pseudoExec, except that it does not produce the errors of
+// the schema. It evaluates cheap keywords first, and quits at the first error.
+//
+// Tracing and coverage require every keyword to be evaluated, so when the vm is
+// tracing or recording coverage, pseudoCheck is no different from pseudoExec.
+func (vm *vm) pseudoCheck(schema *schema, instance interface{}) (bool, error) {
+	if vm.trace != nil || vm.coverage != nil {
+		hasErrors, _, err := vm.pseudoExec(schema, instance)
+		return hasErrors, err
+	}
+
+	prevQuick := vm.quick
+	vm.quick = true
+	hasErrors, _, err := vm.pseudoExec(schema, instance)
+	vm.quick = prevQuick
+
+	return hasErrors, err
+}
+
+// In quick mode, no errors are produced, so the stacks of tokens used to build
+// their paths are left untouched. The stack of schemas is kept regardless, to
+// enforce maxStackDepth.
+
 func (vm *vm) pushNewSchema(id url.URL, tokens []string) {
 	vm.stack.schemas = append(vm.stack.schemas, schemaStack{
-		id:     id,
-		tokens: tokens,
+		id:    id,
+		start: len(vm.stack.schemaTokens),
 	})
+
+	if vm.quick {
+		return
+	}
+
+	for _, token := range tokens {
+		vm.stack.schemaTokens = append(vm.stack.schemaTokens, pathToken{str: token})
+	}
 }
 
 func (vm *vm) popSchema() {
+	s := vm.stack.schemas[len(vm.stack.schemas)-1]
+	vm.stack.schemaTokens = vm.stack.schemaTokens[:s.start]
 	vm.stack.schemas = vm.stack.schemas[:len(vm.stack.schemas)-1]
 }
 
 func (vm *vm) pushSchemaToken(token string) {
-	s := &vm.stack.schemas[len(vm.stack.schemas)-1]
-	s.tokens = append(s.tokens, token)
+	if vm.quick {
+		return
+	}
+
+	vm.stack.schemaTokens = append(vm.stack.schemaTokens, pathToken{str: token})
+}
+
+func (vm *vm) pushSchemaIndex(index int) {
+	if vm.quick {
+		return
+	}
+
+	vm.stack.schemaTokens = append(vm.stack.schemaTokens, pathToken{index: index, isIndex: true})
 }
 
 func (vm *vm) popSchemaToken() {
-	s := &vm.stack.schemas[len(vm.stack.schemas)-1]
-	s.tokens = s.tokens[:len(s.tokens)-1]
+	if vm.quick {
+		return
+	}
+
+	vm.stack.schemaTokens = vm.stack.schemaTokens[:len(vm.stack.schemaTokens)-1]
 }
 
 func (vm *vm) pushInstanceToken(token string) {
-	vm.stack.instance = append(vm.stack.instance, token)
+	if vm.quick {
+		return
+	}
+
+	vm.stack.instance = append(vm.stack.instance, pathToken{str: token})
+}
+
+func (vm *vm) pushInstanceIndex(index int) {
+	if vm.quick {
+		return
+	}
+
+	vm.stack.instance = append(vm.stack.instance, pathToken{index: index, isIndex: true})
 }
 
 func (vm *vm) popInstanceToken() {
+	if vm.quick {
+		return
+	}
+
 	vm.stack.instance = vm.stack.instance[:len(vm.stack.instance)-1]
 }
 
+// schemaID returns the ID of the schema currently being evaluated.
+func (vm *vm) schemaID() url.URL {
+	return vm.stack.schemas[len(vm.stack.schemas)-1].id
+}
+
+// instancePath formats the current location in the instance as a JSON Pointer.
+func (vm *vm) instancePath() jsonpointer.Ptr {
+	return formatPathTokens(vm.stack.instance)
+}
+
+// schemaPath formats the current location in the current schema as a JSON
+// Pointer.
+func (vm *vm) schemaPath() jsonpointer.Ptr {
+	s := vm.stack.schemas[len(vm.stack.schemas)-1]
+	return formatPathTokens(vm.stack.schemaTokens[s.start:])
+}
+
+func formatPathTokens(tokens []pathToken) jsonpointer.Ptr {
+	strs := make([]string, len(tokens))
+	for i, token := range tokens {
+		strs[i] = token.String()
+	}
+
+	return jsonpointer.Ptr{Tokens: strs}
+}
+
 func (vm *vm) reportError() error {
-	schemaStack := vm.stack.schemas[len(vm.stack.schemas)-1]
-	instancePath := make([]string, len(vm.stack.instance))
-	schemaPath := make([]string, len(schemaStack.tokens))
+	return vm.reportErrorWithCauses(nil, nil)
+}
 
-	copy(instancePath, vm.stack.instance)
-	copy(schemaPath, schemaStack.tokens)
+// reportKeywordError reports an error from a keyword whose value is not a
+// subschema, such as "type" or "maxLength".
+func (vm *vm) reportKeywordError(keyword string) error {
+	vm.pushSchemaToken(keyword)
+	if err := vm.reportError(); err != nil {
+		return err
+	}
+	vm.popSchemaToken()
 
+	return nil
+}
+
+func (vm *vm) reportErrorWithCauses(causes []ValidationError, matches []int) error {
 	vm.errors.hasErrors = true
-	vm.errors.errors = append(vm.errors.errors, ValidationError{
-		InstancePath: jsonpointer.Ptr{Tokens: instancePath},
-		SchemaPath:   jsonpointer.Ptr{Tokens: schemaPath},
-		URI:          schemaStack.id,
-	})
+	vm.errors.count++
+
+	if vm.quick {
+		return errInvalid
+	}
 
-	if len(vm.errors.errors) == vm.maxErrors {
+	instancePath := vm.instancePath()
+	schemaPath := vm.schemaPath()
+	id := vm.schemaID()
+
+	if vm.pseudoDepth == 0 && vm.suppressError(instancePath, schemaPath, id) {
+		vm.limits.suppressed++
+		return nil
+	}
+
+	// only an error beyond the limit means errors were left out, so reaching
+	// the limit is not enough to quit
+	if vm.pseudoDepth == 0 && vm.maxErrors > 0 && len(vm.errors.errors) == vm.maxErrors {
 		return errMaxErrors
 	}
 
+	vm.errors.errors = append(vm.errors.errors, ValidationError{
+		InstancePath: instancePath,
+		SchemaPath:   schemaPath,
+		URI:          id,
+		Causes:       causes,
+		Matches:      matches,
+	})
+
 	return nil
 }
+
+// suppressError determines whether an error at the current location ought to
+// be left out of the results, because too many errors have already been
+// reported for the same part of the instance or the same keyword.
+func (vm *vm) suppressError(instancePath, schemaPath jsonpointer.Ptr, id url.URL) bool {
+	if vm.maxErrorsPerInstance == 0 && vm.maxErrorsPerKeyword == 0 {
+		return false
+	}
+
+	if vm.limits.instances == nil {
+		vm.limits.instances = map[string]int{}
+		vm.limits.keywords = map[string]int{}
+	}
+
+	instanceKey := instancePath.String()
+	if vm.maxErrorsPerInstance != 0 && vm.limits.instances[instanceKey] == vm.maxErrorsPerInstance {
+		return true
+	}
+
+	keywordURI := id
+	keywordURI.Fragment = schemaPath.String()
+
+	keywordKey := keywordURI.String()
+	if vm.maxErrorsPerKeyword != 0 && vm.limits.keywords[keywordKey] == vm.maxErrorsPerKeyword {
+		return true
+	}
+
+	vm.limits.instances[instanceKey]++
+	vm.limits.keywords[keywordKey]++
+	return false
+}
@@ -0,0 +1,81 @@
+package jsonschema
+
+import (
+	"sort"
+	"strconv"
+)
+
+// walkRawSchema calls fn for every object-valued schema within input, including
+// input itself and any schemas in "definitions". Subschemas are found using the
+// keywords of draft-07; boolean schemas are skipped, as are values which are not
+// schemas at all.
+//
+// Keywords and property names are visited in sorted order. The tokens passed to
+// fn are only valid for the duration of the call.
+func walkRawSchema(input interface{}, tokens []string, fn func(tokens []string, input map[string]interface{})) {
+	object, ok := input.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	fn(tokens, object)
+
+	for _, keyword := range sortedKeys(object) {
+		value := object[keyword]
+
+		switch keyword {
+		case "not", "if", "then", "else", "additionalItems", "contains",
+			"additionalProperties", "propertyNames":
+			walkRawSchema(value, append(tokens, keyword), fn)
+		case "items":
+			if items, ok := value.([]interface{}); ok {
+				walkRawSchemaArray(items, append(tokens, keyword), fn)
+			} else {
+				walkRawSchema(value, append(tokens, keyword), fn)
+			}
+		case "allOf", "anyOf", "oneOf":
+			if schemas, ok := value.([]interface{}); ok {
+				walkRawSchemaArray(schemas, append(tokens, keyword), fn)
+			}
+		case "definitions", "properties", "patternProperties":
+			if schemas, ok := value.(map[string]interface{}); ok {
+				walkRawSchemaObject(schemas, append(tokens, keyword), fn)
+			}
+		case "dependencies":
+			if deps, ok := value.(map[string]interface{}); ok {
+				for _, key := range sortedKeys(deps) {
+					if dep := deps[key]; !isArray(dep) {
+						walkRawSchema(dep, append(tokens, keyword, key), fn)
+					}
+				}
+			}
+		}
+	}
+}
+
+func walkRawSchemaArray(schemas []interface{}, tokens []string, fn func(tokens []string, input map[string]interface{})) {
+	for i, schema := range schemas {
+		walkRawSchema(schema, append(tokens, strconv.FormatInt(int64(i), 10)), fn)
+	}
+}
+
+func walkRawSchemaObject(schemas map[string]interface{}, tokens []string, fn func(tokens []string, input map[string]interface{})) {
+	for _, key := range sortedKeys(schemas) {
+		walkRawSchema(schemas[key], append(tokens, key), fn)
+	}
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func isArray(value interface{}) bool {
+	_, ok := value.([]interface{})
+	return ok
+}
@@ -0,0 +1,321 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ucarion/json-pointer"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlErrorPattern matches the line number out of errors from yaml.v3.
+var yamlErrorPattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// DecodeYAML decodes a YAML document into the same values DecodeJSON produces
+// for the equivalent JSON document, and records where each value appears in
+// data. The filename is used only in positions.
+//
+// Values with no equivalent in JSON are rejected with an instance of
+// ErrUnsupportedValue. These include mapping keys which are not strings,
+// timestamps, binary data, infinite and NaN numbers, and values with custom
+// tags. Anchors, aliases and merge keys are expanded.
+//
+// Only a single document is accepted. If data is not valid YAML, or holds more
+// than one document, an instance of ErrSyntax is returned.
+func DecodeYAML(filename string, data []byte) (interface{}, *SourceMap, error) {
+	d := yamlDecoder{
+		lines:    newLineIndex(filename, data),
+		source:   &SourceMap{values: map[string]sourceValue{}},
+		anchored: map[*yaml.Node]bool{},
+
+		// expanding aliases can make a document arbitrarily large; allow plenty
+		// of room for legitimate use, but not for exponential blowups
+		budget: 10000 + 100*len(data),
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var document yaml.Node
+	if err := decoder.Decode(&document); err != nil {
+		if err == io.EOF {
+			// an empty document is null
+			d.source.set(nil, d.lines.position(1, 1), nil)
+			return nil, d.source, nil
+		}
+
+		return nil, nil, d.syntaxError(err)
+	}
+
+	var next yaml.Node
+	if err := decoder.Decode(&next); err != io.EOF {
+		if err != nil {
+			return nil, nil, d.syntaxError(err)
+		}
+
+		return nil, nil, ErrSyntax{
+			Position: d.lines.position(next.Line, next.Column),
+			Message:  "multiple YAML documents are not supported",
+		}
+	}
+
+	value, err := d.decode(&document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return value, d.source, nil
+}
+
+type yamlDecoder struct {
+	lines  lineIndex
+	source *SourceMap
+	tokens []string
+	budget int
+
+	// anchored holds the nodes with anchors being decoded, to detect aliases
+	// which refer to their own anchors
+	anchored map[*yaml.Node]bool
+}
+
+func (d *yamlDecoder) syntaxError(err error) error {
+	if match := yamlErrorPattern.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		pos := d.lines.position(line, 0)
+		return ErrSyntax{Position: pos, Message: match[2]}
+	}
+
+	return ErrSyntax{
+		Position: Position{Filename: d.lines.filename},
+		Message:  strings.TrimPrefix(err.Error(), "yaml: "),
+	}
+}
+
+func (d *yamlDecoder) unsupported(node *yaml.Node, format string, args ...interface{}) error {
+	tokens := make([]string, len(d.tokens))
+	copy(tokens, d.tokens)
+
+	return ErrUnsupportedValue{
+		Position: d.lines.position(node.Line, node.Column),
+		Path:     jsonpointer.Ptr{Tokens: tokens},
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (d *yamlDecoder) decode(node *yaml.Node) (interface{}, error) {
+	d.budget--
+	if d.budget < 0 {
+		return nil, d.unsupported(node, "document expands to too many values through aliases")
+	}
+
+	if len(d.tokens) > maxDecodeDepth {
+		return nil, d.unsupported(node, "exceeded max depth of %d", maxDecodeDepth)
+	}
+
+	position := d.lines.position(node.Line, node.Column)
+
+	if node.Anchor != "" {
+		d.anchored[node] = true
+		defer delete(d.anchored, node)
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			d.source.set(d.tokens, position, nil)
+			return nil, nil
+		}
+
+		return d.decode(node.Content[0])
+	case yaml.AliasNode:
+		if d.anchored[node.Alias] {
+			return nil, d.unsupported(node, "alias *%s refers to a value containing it", node.Value)
+		}
+
+		value, err := d.decode(node.Alias)
+		if err != nil {
+			return nil, err
+		}
+
+		// point at the alias, rather than the anchor it refers to
+		decoded := d.source.values[jsonpointer.Ptr{Tokens: d.tokens}.String()]
+		d.source.set(d.tokens, position, decoded.keys)
+		return value, nil
+	case yaml.SequenceNode:
+		if tag := node.ShortTag(); tag != "!!seq" {
+			return nil, d.unsupported(node, "unsupported tag %s", tag)
+		}
+
+		array := make([]interface{}, len(node.Content))
+		for i, elem := range node.Content {
+			d.tokens = append(d.tokens, strconv.Itoa(i))
+			value, err := d.decode(elem)
+			if err != nil {
+				return nil, err
+			}
+			d.tokens = d.tokens[:len(d.tokens)-1]
+
+			array[i] = value
+		}
+
+		d.source.set(d.tokens, position, nil)
+		return array, nil
+	case yaml.MappingNode:
+		return d.decodeMapping(node, position)
+	default:
+		return d.decodeScalar(node, position)
+	}
+}
+
+func (d *yamlDecoder) decodeMapping(node *yaml.Node, position Position) (interface{}, error) {
+	if tag := node.ShortTag(); tag != "!!map" {
+		return nil, d.unsupported(node, "unsupported tag %s", tag)
+	}
+
+	object := map[string]interface{}{}
+	keys := []string{}
+	explicit := map[string]*yaml.Node{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := resolveYAMLAlias(node.Content[i])
+
+		if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == "!!merge" {
+			continue
+		}
+
+		if keyNode.Kind != yaml.ScalarNode || keyNode.ShortTag() != "!!str" {
+			return nil, d.unsupported(node.Content[i], "mapping key %s is not a string; quote it to make it one", describeYAMLNode(keyNode))
+		}
+
+		if previous, ok := explicit[keyNode.Value]; ok {
+			return nil, ErrSyntax{
+				Position: d.lines.position(node.Content[i].Line, node.Content[i].Column),
+				Message:  fmt.Sprintf("mapping key %q already defined at line %d", keyNode.Value, previous.Line),
+			}
+		}
+
+		explicit[keyNode.Value] = node.Content[i]
+	}
+
+	// Merged values are decoded first, so that the positions of explicit keys
+	// take precedence. Of several merged mappings, earlier ones take precedence.
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := resolveYAMLAlias(node.Content[i])
+
+		if keyNode.Kind != yaml.ScalarNode || keyNode.ShortTag() != "!!merge" {
+			continue
+		}
+
+		merged := []*yaml.Node{node.Content[i+1]}
+		if value := resolveYAMLAlias(node.Content[i+1]); value.Kind == yaml.SequenceNode {
+			merged = value.Content
+		}
+
+		for j := len(merged) - 1; j >= 0; j-- {
+			if resolveYAMLAlias(merged[j]).Kind != yaml.MappingNode {
+				return nil, d.unsupported(merged[j], "merge key must refer to a mapping, or a sequence of mappings")
+			}
+
+			value, err := d.decode(merged[j])
+			if err != nil {
+				return nil, err
+			}
+
+			mergedObject := value.(map[string]interface{})
+			mergedKeys := d.source.values[jsonpointer.Ptr{Tokens: d.tokens}.String()].keys
+
+			for _, key := range mergedKeys {
+				if _, ok := explicit[key]; ok {
+					continue
+				}
+
+				if _, ok := object[key]; !ok {
+					keys = append(keys, key)
+				}
+
+				object[key] = mergedObject[key]
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := resolveYAMLAlias(node.Content[i])
+		if keyNode.ShortTag() == "!!merge" {
+			continue
+		}
+
+		d.tokens = append(d.tokens, keyNode.Value)
+		value, err := d.decode(node.Content[i+1])
+		if err != nil {
+			return nil, err
+		}
+		d.tokens = d.tokens[:len(d.tokens)-1]
+
+		keys = append(keys, keyNode.Value)
+		object[keyNode.Value] = value
+	}
+
+	d.source.set(d.tokens, position, keys)
+	return object, nil
+}
+
+func (d *yamlDecoder) decodeScalar(node *yaml.Node, position Position) (interface{}, error) {
+	d.source.set(d.tokens, position, nil)
+
+	switch tag := node.ShortTag(); tag {
+	case "!!null":
+		return nil, nil
+	case "!!str":
+		return node.Value, nil
+	case "!!bool":
+		var value bool
+		if err := node.Decode(&value); err != nil {
+			return nil, d.unsupported(node, "invalid boolean %q", node.Value)
+		}
+
+		return value, nil
+	case "!!int", "!!float":
+		var value float64
+		if err := node.Decode(&value); err != nil {
+			return nil, d.unsupported(node, "invalid number %q", node.Value)
+		}
+
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, d.unsupported(node, "number %s cannot be represented in JSON", node.Value)
+		}
+
+		return value, nil
+	case "!!timestamp":
+		return nil, d.unsupported(node, "timestamp %s cannot be represented in JSON; quote it to make it a string", node.Value)
+	case "!!binary":
+		return nil, d.unsupported(node, "binary data cannot be represented in JSON")
+	default:
+		return nil, d.unsupported(node, "unsupported tag %s", tag)
+	}
+}
+
+func resolveYAMLAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// describeYAMLNode describes a node for use in error messages.
+func describeYAMLNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.SequenceNode:
+		return "of type sequence"
+	case yaml.MappingNode:
+		return "of type mapping"
+	default:
+		return "of unknown type"
+	}
+}
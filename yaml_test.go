@@ -0,0 +1,128 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucarion/json-pointer"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	testCases := []struct {
+		yaml string
+		json string
+	}{
+		{"", "null"},
+		{"~", "null"},
+		{"a: 1\nb: [x, 2.5, true, null]\nc:\n  d: é\n  'e': \"f\"\n", `{"a": 1, "b": ["x", 2.5, true, null], "c": {"d": "é", "e": "f"}}`},
+		{"a: 0x1F\nb: 1e3\nc: '1'\nd: -0.5\n", `{"a": 31, "b": 1000, "c": "1", "d": -0.5}`},
+		{"- &x {a: 1}\n- *x\n", `[{"a": 1}, {"a": 1}]`},
+		{"base: &b {x: 1, y: 2}\nderived:\n  <<: *b\n  y: 3\n", `{"base": {"x": 1, "y": 2}, "derived": {"x": 1, "y": 3}}`},
+		{"a: &a {x: 1}\nb: &b {x: 2, y: 2}\nc:\n  <<: [*a, *b]\n", `{"a": {"x": 1}, "b": {"x": 2, "y": 2}, "c": {"x": 1, "y": 2}}`},
+	}
+
+	for _, tt := range testCases {
+		expected, _, err := DecodeJSON("", []byte(tt.json))
+		assert.NoError(t, err, tt.json)
+
+		actual, _, err := DecodeYAML("", []byte(tt.yaml))
+		assert.NoError(t, err, tt.yaml)
+		assert.Equal(t, expected, actual, tt.yaml)
+	}
+}
+
+func TestDecodeYAMLPositions(t *testing.T) {
+	input := "anchor: &anchor {z: 1}\na: 1\nb: [x, é, true]\nc:\n  d: *anchor\n"
+
+	_, source, err := DecodeYAML("doc.yaml", []byte(input))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		ptr string
+		pos string
+	}{
+		{"", "doc.yaml:1:1"},
+		{"/anchor/z", "doc.yaml:1:21"},
+		{"/a", "doc.yaml:2:4"},
+		{"/b/0", "doc.yaml:3:5"},
+		{"/b/1", "doc.yaml:3:8"},
+		{"/b/2", "doc.yaml:3:12"}, // columns count bytes, so "é" counts for two
+		{"/c", "doc.yaml:5:3"},
+		{"/c/d", "doc.yaml:5:6"},
+		{"/c/d/z", "doc.yaml:1:21"},
+	}
+
+	for _, tt := range testCases {
+		ptr, err := jsonpointer.New(tt.ptr)
+		assert.NoError(t, err)
+
+		pos, ok := source.Position(ptr)
+		assert.True(t, ok, tt.ptr)
+		assert.Equal(t, tt.pos, pos.String(), tt.ptr)
+	}
+
+	keys, ok := source.Keys(jsonpointer.Ptr{})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"anchor", "a", "b", "c"}, keys)
+}
+
+func TestDecodeYAMLErrors(t *testing.T) {
+	testCases := []struct {
+		input string
+		err   string
+	}{
+		{"a: [\n", "doc.yaml:1: did not find expected node content"},
+		{"---\na: 1\n---\nb: 2\n", "doc.yaml:3:1: multiple YAML documents are not supported"},
+		{"a: 1\na: 2\n", `doc.yaml:2:1: mapping key "a" already defined at line 1`},
+		{"1: x\n", "doc.yaml:1:1: #: mapping key 1 is not a string; quote it to make it one"},
+		{"a:\n  [x]: y\n", "doc.yaml:2:3: #/a: mapping key of type sequence is not a string; quote it to make it one"},
+		{"a: 2001-12-14\n", "doc.yaml:1:4: #/a: timestamp 2001-12-14 cannot be represented in JSON; quote it to make it a string"},
+		{"a: [.inf]\n", "doc.yaml:1:5: #/a/0: number .inf cannot be represented in JSON"},
+		{"a: !!binary aGk=\n", "doc.yaml:1:4: #/a: binary data cannot be represented in JSON"},
+		{"a: !Ref x\n", "doc.yaml:1:4: #/a: unsupported tag !Ref"},
+		{"a: &x [*x]\n", "doc.yaml:1:8: #/a/0: alias *x refers to a value containing it"},
+		{"a:\n  <<: 1\n", "doc.yaml:2:7: #/a: merge key must refer to a mapping, or a sequence of mappings"},
+		{
+			"a: &a [1,1,1,1,1,1,1,1,1,1]\n" +
+				"b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a,*a]\n" +
+				"c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b,*b]\n" +
+				"d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c,*c]\n" +
+				"e: [*d,*d,*d,*d,*d,*d,*d,*d,*d,*d]\n",
+			"doc.yaml:1:10: #/e/1/1/5/5/1: document expands to too many values through aliases",
+		},
+	}
+
+	for _, tt := range testCases {
+		_, _, err := DecodeYAML("doc.yaml", []byte(tt.input))
+		if assert.Error(t, err, tt.input) {
+			assert.Equal(t, tt.err, err.Error(), tt.input)
+		}
+	}
+}
+
+func TestValidatorValidateYAML(t *testing.T) {
+	validator, err := NewValidator([]interface{}{
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"replicas": map[string]interface{}{"type": "integer"},
+				"ports": map[string]interface{}{
+					"items": map[string]interface{}{"maximum": 65535.0},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	result, err := validator.ValidateYAML("deploy.yaml", []byte("replicas: three\nports:\n  - 80\n  - 80000\n"))
+	assert.NoError(t, err)
+
+	positions := []string{}
+	for _, e := range result.Errors {
+		positions = append(positions, e.InstancePosition.String()+" "+e.InstancePath.String())
+	}
+
+	assert.Equal(t, []string{"deploy.yaml:4:5 /ports/1", "deploy.yaml:1:11 /replicas"}, positions)
+
+	_, err = validator.ValidateYAML("deploy.yaml", []byte("replicas: 2001-12-14\n"))
+	assert.IsType(t, ErrUnsupportedValue{}, err)
+}